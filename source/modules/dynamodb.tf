@@ -0,0 +1,29 @@
+##########################################
+# ACCOUNT AUTOMATION REQUEST STORE TABLE #
+##########################################
+resource "aws_dynamodb_table" "request_table" {
+  name         = "account-automation-requests"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "RequestId"
+  tags         = var.tags
+
+  attribute {
+    name = "RequestId"
+    type = "S"
+  }
+
+  # Idempotency keys are removed once they expire. Provisioning requests have no ExpiresAt and are kept.
+  ttl {
+    attribute_name = "ExpiresAt"
+    enabled        = true
+  }
+
+  point_in_time_recovery {
+    enabled = true
+  }
+
+  server_side_encryption {
+    enabled     = true
+    kms_key_arn = aws_kms_key.kms_key.arn
+  }
+}
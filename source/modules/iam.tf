@@ -67,6 +67,29 @@ data "aws_iam_policy_document" "post_lambda_permissions" {
       module.kms_key.key_arn,
     ]
   }
+
+  statement {
+    effect = "Allow"
+    actions = [
+      "dynamodb:DeleteItem",
+      "dynamodb:GetItem",
+      "dynamodb:PutItem",
+    ]
+    resources = [
+      aws_dynamodb_table.request_table.arn,
+    ]
+  }
+
+  # The POST lambda invokes itself asynchronously to finish a request once the account is created
+  statement {
+    effect = "Allow"
+    actions = [
+      "lambda:InvokeFunction",
+    ]
+    resources = [
+      "arn:aws:lambda:${var.region}:${var.account_id}:function:account-automation-post-lambda",
+    ]
+  }
 }
 
 ##########################################################
@@ -140,6 +163,148 @@ data "aws_iam_policy_document" "put_lambda_permissions" {
   }
 }
 
+##########################################################
+# ACCOUNT AUTOMATION GET LAMBDA FUNCTION ROLE AND POLICY #
+##########################################################
+resource "aws_iam_role" "get_lambda_role" {
+  name               = "account-automation-get-lambda-role"
+  description        = "IAM Role for account-automation-get-lambda"
+  path               = "/delegated/${var.application_id}/"
+  tags               = var.tags
+  assume_role_policy = data.aws_iam_policy_document.get_lambda_trust_policy.json
+}
+
+resource "aws_iam_role_policy_attachment" "get_lambda_trust_policy" {
+  role       = aws_iam_role.get_lambda_role.name
+  policy_arn = aws_iam_policy.get_lambda_policy.arn
+}
+
+resource "aws_iam_policy" "get_lambda_policy" {
+  name        = "account-automation-get-lambda-policy"
+  path        = "/delegated/${var.application_id}/"
+  description = "IAM policy for account-automation-get-lambda"
+  policy      = data.aws_iam_policy_document.get_lambda_permissions.json
+}
+
+data "aws_iam_policy_document" "get_lambda_trust_policy" {
+  statement {
+    effect = "Allow"
+    actions = [
+      "sts:AssumeRole",
+    ]
+    principals {
+      type = "Service"
+      identifiers = [
+        "lambda.amazonaws.com",
+      ]
+    }
+  }
+}
+
+data "aws_iam_policy_document" "get_lambda_permissions" {
+  statement {
+    effect    = "Allow"
+    actions   = "sts:AssumeRole"
+    resources = [var.create_account_role_arn]
+  }
+
+  statement {
+    effect = "Allow"
+    actions = [
+      "logs:CreateLogGroup",
+      "logs:CreateLogStream",
+      "logs:PutLogEvents",
+    ]
+    resources = [
+      "*",
+    ]
+  }
+
+  statement {
+    effect = "Allow"
+    actions = [
+      "kms:CreateGrant",
+      "kms:Decrypt",
+      "kms:Encrypt",
+      "kms:ListAliases",
+    ]
+    resources = [
+      module.kms_key.key_arn,
+    ]
+  }
+}
+
+#############################################################
+# ACCOUNT AUTOMATION DELETE LAMBDA FUNCTION ROLE AND POLICY #
+#############################################################
+resource "aws_iam_role" "delete_lambda_role" {
+  name               = "account-automation-delete-lambda-role"
+  description        = "IAM Role for account-automation-delete-lambda"
+  path               = "/delegated/${var.application_id}/"
+  tags               = var.tags
+  assume_role_policy = data.aws_iam_policy_document.delete_lambda_trust_policy.json
+}
+
+resource "aws_iam_role_policy_attachment" "delete_lambda_trust_policy" {
+  role       = aws_iam_role.delete_lambda_role.name
+  policy_arn = aws_iam_policy.delete_lambda_policy.arn
+}
+
+resource "aws_iam_policy" "delete_lambda_policy" {
+  name        = "account-automation-delete-lambda-policy"
+  path        = "/delegated/${var.application_id}/"
+  description = "IAM policy for account-automation-delete-lambda"
+  policy      = data.aws_iam_policy_document.delete_lambda_permissions.json
+}
+
+data "aws_iam_policy_document" "delete_lambda_trust_policy" {
+  statement {
+    effect = "Allow"
+    actions = [
+      "sts:AssumeRole",
+    ]
+    principals {
+      type = "Service"
+      identifiers = [
+        "lambda.amazonaws.com",
+      ]
+    }
+  }
+}
+
+data "aws_iam_policy_document" "delete_lambda_permissions" {
+  statement {
+    effect    = "Allow"
+    actions   = "sts:AssumeRole"
+    resources = [var.create_account_role_arn]
+  }
+
+  statement {
+    effect = "Allow"
+    actions = [
+      "logs:CreateLogGroup",
+      "logs:CreateLogStream",
+      "logs:PutLogEvents",
+    ]
+    resources = [
+      "*",
+    ]
+  }
+
+  statement {
+    effect = "Allow"
+    actions = [
+      "kms:CreateGrant",
+      "kms:Decrypt",
+      "kms:Encrypt",
+      "kms:ListAliases",
+    ]
+    resources = [
+      module.kms_key.key_arn,
+    ]
+  }
+}
+
 ####################################
 # API GATEWAY RESOURCE PERMISSIONS #
 ####################################
@@ -175,3 +340,25 @@ resource "aws_lambda_permission" "apigw_put_lambda_permission" {
   # within API Gateway REST API.
   source_arn = "${module.apigw.execution_arn}/*/*/*"
 }
+
+resource "aws_lambda_permission" "apigw_get_lambda_permission" {
+  statement_id  = "AllowInvokeAccountAutomationGetLambda"
+  action        = "lambda:InvokeFunction"
+  function_name = "account-automation-get-lambda"
+  principal     = "apigateway.amazonaws.com"
+
+  # The /*/*/* part allows invocation from any stage, method and resource path
+  # within API Gateway REST API.
+  source_arn = "${module.apigw.execution_arn}/*/*/*"
+}
+
+resource "aws_lambda_permission" "apigw_delete_lambda_permission" {
+  statement_id  = "AllowInvokeAccountAutomationDeleteLambda"
+  action        = "lambda:InvokeFunction"
+  function_name = "account-automation-delete-lambda"
+  principal     = "apigateway.amazonaws.com"
+
+  # The /*/*/* part allows invocation from any stage, method and resource path
+  # within API Gateway REST API.
+  source_arn = "${module.apigw.execution_arn}/*/*/*"
+}
@@ -11,11 +11,11 @@ resource "aws_lambda_function" "post_lambda_function" {
   filename = "${path.module}/src/lambda/go-account-automation-create-archive/go-account-automation-create.zip"
 
   lambda_name = "account-automation-post-lambda"
-  description = "This lambda acts as an API GW POST method handler and will create accounts based on an API GW request event"
+  description = "This lambda acts as an API GW POST method handler and will create accounts based on an API GW request event, then invokes itself asynchronously to move and tag the account once created"
 
   runtime     = "go1.x"
   handler     = "HandleRequest"
-  timeout     = 60
+  timeout     = 900
   memory_size = 512
   role        = aws_iam_role.post_lambda_role.arn
   kms_key_arn = aws_kms_key.kms_key.key_arn
@@ -23,11 +23,19 @@ resource "aws_lambda_function" "post_lambda_function" {
 
   environment {
     variables = {
-      ASSUME_ROLE_ARN = var.create_account_role_arn
-      EMAIL_DOMAIN    = var.email_domain
-      RUNTIME_ENV     = var.runtime_env
-      SEC_OU          = jsonencode(var.infosec_ous)
-      WORKLOAD_OU     = var.workload_ou
+      ASSUME_ROLE_ARN           = var.create_account_role_arn
+      EMAIL_DOMAIN              = var.email_domain
+      REQUEST_TABLE             = aws_dynamodb_table.request_table.name
+      RUNTIME_ENV               = var.runtime_env
+      EXECUTION_MODE            = var.execution_mode
+      SEC_OU                    = jsonencode(var.infosec_ous)
+      WORKLOAD_OU               = var.workload_ou
+      SEC_OU_PATHS              = jsonencode(var.infosec_ou_paths)
+      WORKLOAD_OU_PATH          = var.workload_ou_path
+      PLACEMENT_RULES           = var.placement_rules
+      ACCOUNT_NAMING_CONVENTION = var.account_naming_convention
+      ORG_TREE_TTL              = var.org_tree_ttl
+      AUTO_CREATE_OU_PARENTS    = jsonencode(var.auto_create_ou_parents)
     }
   }
 }
@@ -45,7 +53,7 @@ resource "aws_lambda_function" "put_lambda_function" {
   filename = "${path.module}/src/lambda/go-account-automation-update-archive/go-account-automation-update.zip"
 
   lambda_name = "account-automation-put-lambda"
-  description = "This lambda acts as an API GW PUT method handler and will update an account based on an API GW request event with a request body and query string"
+  description = "This lambda acts as an API GW PUT method handler and will update an account, or move it to the OU for another env or lob, based on an API GW request event"
 
   runtime     = "go1.x"
   handler     = "HandleRequest"
@@ -57,8 +65,92 @@ resource "aws_lambda_function" "put_lambda_function" {
 
   environment {
     variables = {
-      ASSUME_ROLE_ARN = var.create_account_role_arn
-      RUNTIME_ENV     = var.runtime_env
+      ASSUME_ROLE_ARN           = var.create_account_role_arn
+      EMAIL_DOMAIN              = var.email_domain
+      RUNTIME_ENV               = var.runtime_env
+      EXECUTION_MODE            = var.execution_mode
+      SEC_OU                    = jsonencode(var.infosec_ous)
+      WORKLOAD_OU               = var.workload_ou
+      SEC_OU_PATHS              = jsonencode(var.infosec_ou_paths)
+      WORKLOAD_OU_PATH          = var.workload_ou_path
+      PLACEMENT_RULES           = var.placement_rules
+      ACCOUNT_NAMING_CONVENTION = var.account_naming_convention
+    }
+  }
+}
+
+###################################################
+# ACCOUNT AUTOMATION GET LAMBDA FUNCTION RESOURCE #
+###################################################
+data "archive_file" "get_lambda_function_archive" {
+  type        = "zip"
+  source_dir  = "${path.module}/src/lambda/go-account-automation-read/"
+  output_path = "${path.module}/src/lambda/go-account-automation-read-archive/go-account-automation-read.zip"
+}
+
+resource "aws_lambda_function" "get_lambda_function" {
+  filename = "${path.module}/src/lambda/go-account-automation-read-archive/go-account-automation-read.zip"
+
+  lambda_name = "account-automation-get-lambda"
+  description = "This lambda acts as an API GW GET method handler and will return an account's provisioning model, or a filtered list of accounts, based on an API GW request event"
+
+  runtime     = "go1.x"
+  handler     = "HandleRequest"
+  timeout     = 60
+  memory_size = 512
+  role        = aws_iam_role.get_lambda_role.arn
+  kms_key_arn = aws_kms_key.kms_key.key_arn
+  tags        = var.tags
+
+  environment {
+    variables = {
+      ASSUME_ROLE_ARN  = var.create_account_role_arn
+      RUNTIME_ENV      = var.runtime_env
+      EXECUTION_MODE   = var.execution_mode
+      SEC_OU           = jsonencode(var.infosec_ous)
+      WORKLOAD_OU      = var.workload_ou
+      SEC_OU_PATHS     = jsonencode(var.infosec_ou_paths)
+      WORKLOAD_OU_PATH = var.workload_ou_path
+      PLACEMENT_RULES  = var.placement_rules
+    }
+  }
+}
+
+######################################################
+# ACCOUNT AUTOMATION DELETE LAMBDA FUNCTION RESOURCE #
+######################################################
+data "archive_file" "delete_lambda_function_archive" {
+  type        = "zip"
+  source_dir  = "${path.module}/src/lambda/go-account-automation-delete/"
+  output_path = "${path.module}/src/lambda/go-account-automation-delete-archive/go-account-automation-delete.zip"
+}
+
+resource "aws_lambda_function" "delete_lambda_function" {
+  filename = "${path.module}/src/lambda/go-account-automation-delete-archive/go-account-automation-delete.zip"
+
+  lambda_name = "account-automation-delete-lambda"
+  description = "This lambda acts as an API GW DELETE method handler and will close an account, after safety checks, based on an API GW request event"
+
+  runtime     = "go1.x"
+  handler     = "HandleRequest"
+  timeout     = 60
+  memory_size = 512
+  role        = aws_iam_role.delete_lambda_role.arn
+  kms_key_arn = aws_kms_key.kms_key.key_arn
+  tags        = var.tags
+
+  environment {
+    variables = {
+      ASSUME_ROLE_ARN           = var.create_account_role_arn
+      RUNTIME_ENV               = var.runtime_env
+      EXECUTION_MODE            = var.execution_mode
+      SEC_OU                    = jsonencode(var.infosec_ous)
+      WORKLOAD_OU               = var.workload_ou
+      SEC_OU_PATHS              = jsonencode(var.infosec_ou_paths)
+      WORKLOAD_OU_PATH          = var.workload_ou_path
+      PLACEMENT_RULES           = var.placement_rules
+      ACCOUNT_NAMING_CONVENTION = var.account_naming_convention
+      SUSPENDED_OU              = var.suspended_ou
     }
   }
 }
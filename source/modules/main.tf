@@ -1,8 +1,12 @@
 locals {
   template_vars = {
-    account_id                 = var.account_id
-    region                     = var.region
-    account_provision_put_uri  = module.put_lambda_function.data.invoke_arn
-    account_provision_post_uri = module.post_lambda_function.data.invoke_arn
+    account_id                   = var.account_id
+    region                       = var.region
+    account_provision_get_uri    = module.get_lambda_function.data.invoke_arn
+    account_provision_put_uri    = module.put_lambda_function.data.invoke_arn
+    account_provision_post_uri   = module.post_lambda_function.data.invoke_arn
+    account_provision_delete_uri = module.delete_lambda_function.data.invoke_arn
+    # the email domain as a pattern escaped for swagger.json, such as \\@example\\.com
+    account_poc_domain_pattern = "\\\\@${replace(trimprefix(var.email_domain, "@"), ".", "\\\\.")}"
   }
 }
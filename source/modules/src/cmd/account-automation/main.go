@@ -0,0 +1,41 @@
+// Command account-automation runs the operator commands of the create, update and read Lambdas against
+// Organizations, for operators who need to provision or fix an account outside the ticketing flow:
+//
+//	account-automation <create|move|plan|show|update> [flags]
+package main
+
+import (
+	"fmt"
+	"os"
+
+	createlambda "go-account-automation/go-account-automation-create"
+	readlambda "go-account-automation/go-account-automation-read"
+	updatelambda "go-account-automation/go-account-automation-update"
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/orgclient"
+)
+
+func main() {
+	mode, error := automation.CommandMode()
+	if error != nil {
+		fmt.Fprintln(os.Stderr, error.Error())
+		os.Exit(2)
+	}
+	org, error := orgclient.New(mode)
+	if error != nil {
+		fmt.Fprintln(os.Stderr, error.Error())
+		os.Exit(1)
+	}
+
+	commands := map[string]automation.Command{}
+	for _, lambdaCommands := range []map[string]automation.Command{
+		createlambda.NewHandler(mode, org).Commands(),
+		updatelambda.NewHandler(mode, org).Commands(),
+		readlambda.NewHandler(mode, org).Commands(),
+	} {
+		for name, command := range lambdaCommands {
+			commands[name] = command
+		}
+	}
+	os.Exit(automation.RunCommand(commands, os.Args[1:], os.Stdout, os.Stderr))
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"log"
+
+	"github.com/aws/aws-lambda-go/lambda"
+
+	createlambda "go-account-automation/go-account-automation-create"
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/orgclient"
+)
+
+func main() {
+	mode, error := automation.ModeFromEnv()
+	if error != nil {
+		log.Fatal(error.Error())
+	}
+	log.Println("Running in ", mode, " mode...")
+	org, error := orgclient.New(mode)
+	if error != nil {
+		log.Fatal(error.Error())
+	}
+	h := createlambda.NewHandler(mode, org)
+	lambda.Start(h.HandleEvent)
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"log"
+
+	"github.com/aws/aws-lambda-go/lambda"
+
+	deletelambda "go-account-automation/go-account-automation-delete"
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/orgclient"
+)
+
+func main() {
+	mode, error := automation.ModeFromEnv()
+	if error != nil {
+		log.Fatal(error.Error())
+	}
+	log.Println("Running in ", mode, " mode...")
+	org, error := orgclient.New(mode)
+	if error != nil {
+		log.Fatal(error.Error())
+	}
+	h := deletelambda.NewHandler(mode, org)
+	lambda.Start(h.HandleRequest)
+}
@@ -0,0 +1,48 @@
+// Command local-api serves the routes of every account automation Lambda from one HTTP server, under /v1 as in
+// swagger.json, so the whole API can be tried without deploying it behind API GW.
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+
+	createlambda "go-account-automation/go-account-automation-create"
+	deletelambda "go-account-automation/go-account-automation-delete"
+	readlambda "go-account-automation/go-account-automation-read"
+	updatelambda "go-account-automation/go-account-automation-update"
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/orgclient"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "address to serve the API on")
+	flag.Parse()
+
+	mode, error := automation.ModeFromEnv()
+	if error != nil {
+		log.Fatal(error.Error())
+	}
+	log.Println("Running in ", mode, " mode...")
+
+	org, error := orgclient.New(mode)
+	if error != nil {
+		log.Fatal(error.Error())
+	}
+	log.Fatal(automation.ServeLocal(*addr, localLambdas(mode, org)...))
+}
+
+// localLambdas returns the four Lambdas, all running against org, so an account created through POST /accounts in
+// simulated mode can then be read, updated and closed.
+func localLambdas(mode automation.Mode, org organizationsiface.OrganizationsAPI) []automation.LocalLambda {
+	create := createlambda.NewHandler(mode, org)
+	// there is no deployed create Lambda to invoke, so its follow-ups run in this process
+	create.RunFollowUpsInline()
+	return []automation.LocalLambda{
+		{Name: "create", Handle: create.HandleProxyRequest, Routes: createlambda.LocalRoutes},
+		{Name: "read", Handle: readlambda.NewHandler(mode, org).HandleRequest, Routes: readlambda.LocalRoutes},
+		{Name: "update", Handle: updatelambda.NewHandler(mode, org).HandleRequest, Routes: updatelambda.LocalRoutes},
+		{Name: "delete", Handle: deletelambda.NewHandler(mode, org).HandleRequest, Routes: deletelambda.LocalRoutes},
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	createlambda "go-account-automation/go-account-automation-create"
+	readlambda "go-account-automation/go-account-automation-read"
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/orgclient"
+)
+
+func TestLocalLambdas(t *testing.T) {
+	for variable, value := range map[string]string{
+		"WORKLOAD_OU":            "ou-abcd-01234567",
+		"SEC_OU":                 "",
+		"SEC_OU_PATHS":           "",
+		"AUTO_CREATE_OU_PARENTS": `["ou-abcd-01234567"]`,
+		"EMAIL_DOMAIN":           "@example.com",
+	} {
+		defer os.Setenv(variable, os.Getenv(variable))
+		os.Setenv(variable, value)
+	}
+	org, error := orgclient.NewSimulated()
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	server := httptest.NewServer(automation.NewLocalServer(localLambdas(automation.ModeSimulated, org)...))
+	defer server.Close()
+
+	send := func(method string, target string, body string) (int, []byte) {
+		request, _ := http.NewRequest(method, server.URL+target, bytes.NewBufferString(body))
+		request.Header.Set(automation.LocalCallerLobHeader, "APP")
+		response, requestError := http.DefaultClient.Do(request)
+		if requestError != nil {
+			t.Fatal(requestError.Error())
+		}
+		defer response.Body.Close()
+		responseBody, _ := ioutil.ReadAll(response.Body)
+		return response.StatusCode, responseBody
+	}
+
+	//test that an account created through one Lambda can be read and closed through the others
+	status, body := send("POST", "/v1/accounts", `{"name":"AWS_APP_local_Dev","costCenter":"01234","accountPOC":"john.doe@example.com","applicationId":"00000000-0000-0000-0000-000000000000","env":"Dev","lob":"APP"}`)
+	var accepted createlambda.CreateAccountResponse
+	json.Unmarshal(body, &accepted)
+	if status != http.StatusAccepted {
+		t.Fatal("Unexpected create response: ", status, string(body))
+	}
+	status, body = send("GET", "/v1/accounts/requests/"+accepted.RequestID, "")
+	var provisioning createlambda.ProvisioningStatus
+	json.Unmarshal(body, &provisioning)
+	if status != http.StatusOK || provisioning.AccountID == "" {
+		t.Fatal("Unexpected status response: ", status, string(body))
+	}
+
+	status, body = send("GET", "/v1/accounts/"+provisioning.AccountID, "")
+	var account readlambda.AccountResponse
+	json.Unmarshal(body, &account)
+	if status != http.StatusOK || account.Name != "AWS_APP_local_Dev" || account.OUPath != "/Workloads/Dev/APP" {
+		t.Fatal("Unexpected read response: ", status, string(body))
+	}
+
+	status, body = send("DELETE", "/v1/accounts/"+provisioning.AccountID, "")
+	if status != http.StatusAccepted {
+		t.Fatal("Unexpected delete response: ", status, string(body))
+	}
+	status, body = send("GET", "/v1/accounts/"+provisioning.AccountID, "")
+	json.Unmarshal(body, &account)
+	if status != http.StatusOK || account.Status != "PENDING_CLOSURE" {
+		t.Fatal("The closure was not seen by the read Lambda: ", status, string(body))
+	}
+}
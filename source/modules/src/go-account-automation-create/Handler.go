@@ -1,124 +1,159 @@
-package main
+package createlambda
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"log"
 	"os"
-	"reflect"
-	"strings"
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
-	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
-	"github.com/aws/aws-sdk-go/aws/session"
+	lambdasvc "github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
 	"github.com/aws/aws-sdk-go/service/organizations"
 	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+
+	"go-account-automation/internal/automation"
 )
 
-// Global
-var _RUNTIME_ENV_ = os.Getenv("RUNTIME_ENV")
-
-type AccountPayload struct {
-	Name          string `json:"name"`
-	CostCenter    string `json:"costCenter"`
-	AccountPOC    string `json:"accountPOC"`
-	ApplicationID string `json:"applicationId"`
-	Env           string `json:"env"`
-	Lob           string `json:"lob"`
-	AccountID     string `json:"accountId"`
+// CreateAccountResponse is returned by POST /accounts once CreateAccount has been accepted.
+// Creation continues asynchronously; poll GET /accounts/requests/{id} with RequestID for the outcome.
+type CreateAccountResponse struct {
+	automation.AccountPayload
+	RequestID string `json:"createAccountRequestId"`
+	State     string `json:"state"`
 }
 
-func ProcessRequestPayload(request string) (AccountPayload, error) {
-	var payload AccountPayload
-	error := json.Unmarshal([]byte(request), &payload)
-	return payload, error
+// ProvisioningStatus is returned by GET /accounts/requests/{id}.
+type ProvisioningStatus struct {
+	RequestID     string `json:"createAccountRequestId"`
+	State         string `json:"state"`
+	FailureReason string `json:"failureReason,omitempty"`
+	AccountID     string `json:"accountId,omitempty"`
+	AccountName   string `json:"accountName,omitempty"`
+
+	// Request is the stored record of the request, including the steps taken after creation.
+	Request *ProvisioningRequest `json:"request,omitempty"`
 }
 
-func ValidatePayload(payload AccountPayload) error {
-	accountName := &payload.Name
+// FollowUpEvent is sent asynchronously by this Lambda to itself after CreateAccount has been accepted.
+// It waits for the creation to finish and then moves and tags the new account recorded in the RequestStore.
+type FollowUpEvent struct {
+	RequestID string `json:"createAccountRequestId"`
+	// Request is the whole request, sent when HandleRequest could not record it, for the follow-up to record instead.
+	Request *ProvisioningRequest `json:"request,omitempty"`
+}
 
-	s := strings.Split(*accountName, "_")
-	_, lob, _, env := s[0], s[1], s[2], s[3] //'aws'_lob_name_env
+// Event is anything this Lambda can be invoked with: an API GW proxy request or a FollowUpEvent.
+type Event struct {
+	events.APIGatewayProxyRequest
+	FollowUp *FollowUpEvent `json:"followUp,omitempty"`
+}
 
-	if !strings.EqualFold(lob, payload.Lob) || !strings.EqualFold(env, payload.Env) {
-		error := errors.New("error: lob or env provided seems to differ from those in the account name")
-		return error
-	}
-	return nil
+func AccountEmail(accountName string) string {
+	return accountName + os.Getenv("EMAIL_DOMAIN")
 }
 
 func CreateAccount(svc organizationsiface.OrganizationsAPI, accountName string) (string, error) {
-	if strings.EqualFold(_RUNTIME_ENV_, "prod") {
-		email := accountName + os.Getenv("EMAIL_DOMAIN")
-		input := organizations.CreateAccountInput{
-			AccountName: &accountName,
-			Email:       &email,
-		}
-		accountOutput, error := svc.CreateAccount(&input)
-		if error != nil {
-			return "", error
-		}
-
-		requestID := accountOutput.CreateAccountStatus.Id
-		return *requestID, nil
-	} else {
-		log.Println("Dev/Test environment detected - Your request will not trigger an account creation")
-		return "test", nil
+	email := AccountEmail(accountName)
+	input := organizations.CreateAccountInput{
+		AccountName: &accountName,
+		Email:       &email,
+	}
+	accountOutput, error := svc.CreateAccount(&input)
+	if error != nil {
+		return "", error
 	}
+
+	requestID := accountOutput.CreateAccountStatus.Id
+	return *requestID, nil
 }
 
-func ValidateAccountStatus(svc organizationsiface.OrganizationsAPI, requestID string) (string, error) {
-	if strings.EqualFold(_RUNTIME_ENV_, "prod") {
-		for {
-			status, error := svc.DescribeCreateAccountStatus(&organizations.DescribeCreateAccountStatusInput{CreateAccountRequestId: &requestID})
-			if error != nil {
-				return "", error
+// AccountStatusPollInterval is how long ValidateAccountStatus waits between checks of an IN_PROGRESS request.
+var AccountStatusPollInterval = 5 * time.Second
+
+// ErrAccountCreationPending is returned by ValidateAccountStatus when ctx is done, or its deadline is too close for
+// another check, before Organizations has finished creating the account.
+var ErrAccountCreationPending = errors.New("error: account creation is still IN_PROGRESS")
+
+// ValidateAccountStatus waits for the CreateAccount request to finish and returns the new account's ID. It stops
+// waiting with ErrAccountCreationPending before ctx's deadline rather than be cut off by it.
+func ValidateAccountStatus(ctx context.Context, svc organizationsiface.OrganizationsAPI, requestID string) (string, error) {
+	for {
+		status, error := svc.DescribeCreateAccountStatus(&organizations.DescribeCreateAccountStatusInput{CreateAccountRequestId: &requestID})
+		if error != nil {
+			return "", error
+		}
+		state := *status.CreateAccountStatus.State
+		if state == "FAILED" {
+			log.Println("Failed creating Account")
+			error = errors.New(*status.CreateAccountStatus.FailureReason)
+			return "", error
+		} else if state == "IN_PROGRESS" {
+			log.Println("In Progress for creating Account")
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < AccountStatusPollInterval {
+				return "", ErrAccountCreationPending
 			}
-			state := *status.CreateAccountStatus.State
-			if state == "FAILED" {
-				log.Println("Failed creating Account")
-				error = errors.New(*status.CreateAccountStatus.FailureReason)
-				return "", error
-			} else if state == "IN_PROGRESS" {
-				log.Println("In Progress for creating Account")
-				time.Sleep(5 * time.Second)
-			} else {
-				log.Println("Success")
-				return *status.CreateAccountStatus.AccountId, nil
+			select {
+			case <-ctx.Done():
+				return "", ErrAccountCreationPending
+			case <-time.After(AccountStatusPollInterval):
 			}
+		} else {
+			log.Println("Success")
+			return *status.CreateAccountStatus.AccountId, nil
 		}
-	} else {
-		log.Println("Dev/Test environment detected - Your request will not trigger ValidateAccountStatus")
-		return "test_account_id", nil
 	}
 }
 
-func RetrieveOUs(svc organizationsiface.OrganizationsAPI, payload AccountPayload) (string, string, error) {
-	workloadOU := os.Getenv("WORKLOAD_OU")
-	infraSecOUs, error := RetrieveInfraSecOUs()
+func DescribeProvisioningRequest(svc organizationsiface.OrganizationsAPI, requestID string) (ProvisioningStatus, error) {
+	status, error := svc.DescribeCreateAccountStatus(&organizations.DescribeCreateAccountStatusInput{CreateAccountRequestId: &requestID})
 	if error != nil {
-		return "", "", error
+		return ProvisioningStatus{}, error
 	}
-	// log.Println("infraSecOUs, workload:", infraSecOUs, ",", workloadOU)
+	return ProvisioningStatus{
+		RequestID:     requestID,
+		State:         aws.StringValue(status.CreateAccountStatus.State),
+		FailureReason: aws.StringValue(status.CreateAccountStatus.FailureReason),
+		AccountID:     aws.StringValue(status.CreateAccountStatus.AccountId),
+		AccountName:   aws.StringValue(status.CreateAccountStatus.AccountName),
+	}, nil
+}
 
-	parentOU := RetrieveParentOU(infraSecOUs, workloadOU, payload.Lob)
-	// log.Println("parentOU:", parentOU)
+func ScheduleFollowUp(svc lambdaiface.LambdaAPI, event FollowUpEvent) error {
+	eventPayload, error := json.Marshal(Event{FollowUp: &event})
+	if error != nil {
+		return error
+	}
+	input := &lambdasvc.InvokeInput{
+		FunctionName:   aws.String(os.Getenv("AWS_LAMBDA_FUNCTION_NAME")),
+		InvocationType: aws.String(lambdasvc.InvocationTypeEvent),
+		Payload:        eventPayload,
+	}
+	_, error = svc.Invoke(input)
+	return error
+}
 
-	envOU, root, error := RetrieveEnvOU(svc, infraSecOUs, parentOU, payload.Env)
+// RetrieveOUs returns the root ID and the ID of the OU the account belongs in, placed by the PLACEMENT_RULES
+// document when there is one and by the Workloads/Security walk otherwise. The OUs are looked up through tree.
+func RetrieveOUs(svc organizationsiface.OrganizationsAPI, tree *automation.OrgTree, payload automation.AccountPayload) (string, string, error) {
+	autoCreate, error := automation.LoadOUAutoCreation(svc, tree)
 	if error != nil {
 		return "", "", error
 	}
-	// log.Println("envOU,root:", envOU, ",", root)
+	return ResolveDestinationOU(svc, tree, payload, autoCreate)
+}
 
-	ou, error := DetermineDestinationOU(svc, infraSecOUs, envOU, payload.Lob)
+// ResolveDestinationOU places the account like RetrieveOUs, creating the OUs missing from its path where
+// autoCreate allows when it is not nil.
+func ResolveDestinationOU(svc organizationsiface.OrganizationsAPI, tree *automation.OrgTree, payload automation.AccountPayload, autoCreate *automation.OUAutoCreation) (string, string, error) {
+	root, ou, error := automation.ResolveOUs(svc, tree, payload, autoCreate)
 	if error != nil {
 		return "", "", error
 	}
-	if ou == "" && strings.EqualFold(_RUNTIME_ENV_, "prod") {
+	if ou == "" {
 		error = errors.New("error: Destination OU not found")
 		return "", "", error
 	}
@@ -127,205 +162,313 @@ func RetrieveOUs(svc organizationsiface.OrganizationsAPI, payload AccountPayload
 	return root, ou, nil
 }
 
-func RetrieveInfraSecOUs() (map[string]string, error) {
-	var infraSecOUs map[string]string
-	// Serialize json to map
-	jsonMap := os.Getenv("SEC_OU")
-	error := json.Unmarshal([]byte(jsonMap), &infraSecOUs)
+func AcceptedResponse(payload automation.AccountPayload, requestID string) (*events.APIGatewayProxyResponse, error) {
+	log.Println("Stringifying response body...")
+	responseBody := CreateAccountResponse{
+		AccountPayload: payload,
+		RequestID:      requestID,
+		State:          organizations.CreateAccountStateInProgress,
+	}
+	jsonResponseBody, error := json.Marshal(responseBody)
 	if error != nil {
-		return nil, error
+		return automation.HandleErrors(error, 500)
 	}
-	return infraSecOUs, nil
-}
+	log.Println("Response payload: ", responseBody)
 
-func RetrieveParentOU(infraSecOUs map[string]string, workloadOU string, lob string) string {
-	if infraSecOU, ok := infraSecOUs[lob]; ok {
-		return infraSecOU
-	} else {
-		return workloadOU
+	response := &events.APIGatewayProxyResponse{
+		StatusCode: 202,
+		Body:       string(jsonResponseBody),
 	}
+	return response, nil
 }
 
-func RetrieveEnvOU(svc organizationsiface.OrganizationsAPI, infraSecOUs map[string]string, parentOU string, env string) (string, string, error) {
-	rootList, error := svc.ListRoots(&organizations.ListRootsInput{})
+func (h *Handler) HandleRequest(request events.APIGatewayProxyRequest) (*events.APIGatewayProxyResponse, error) {
+	svc, store := h.Org, h.Store
+
+	log.Println("Serializing Payload...")
+	createRequest, error := ProcessCreateRequest(request.Body)
 	if error != nil {
-		return "", "", error
+		return automation.HandleErrors(error, 500)
 	}
-	root := rootList.Roots[0].Id
+	log.Println("Payload serialized without error...")
 
-	envOUs, error := svc.ListOrganizationalUnitsForParent(&organizations.ListOrganizationalUnitsForParentInput{ParentId: &parentOU})
+	log.Println("Generating account name...")
+	payload, error := GenerateAccountName(createRequest)
+	if namingError, ok := error.(*automation.NamingError); ok {
+		return automation.HandleNamingError(namingError)
+	}
+	if validationError, ok := error.(*automation.ValidationError); ok {
+		return automation.HandleValidationError(validationError)
+	}
 	if error != nil {
-		return "", "", error
+		return automation.HandleErrors(error, 500)
 	}
 
-	var envOU string
-	for _, tempOU := range envOUs.OrganizationalUnits {
-		if strings.EqualFold(*tempOU.Name, env) {
-			envOU = *tempOU.Id
-			break
-		}
+	if h.Mode == automation.ModeDryRun || automation.IsDryRun(request) {
+		return HandleDryRun(svc, h.Tree, payload)
 	}
 
-	return envOU, *root, nil
-}
+	log.Println("Claiming idempotency key...")
+	idempotencyKey, error := IdempotencyKey(request, payload)
+	if error != nil {
+		return automation.HandleErrors(error, 500)
+	}
+	claim, error := store.ClaimIdempotencyKey(idempotencyKey)
+	if error == ErrIdempotencyKeyClaimed {
+		log.Println("Idempotency key already claimed, looking up previous request...")
+		previous, conflict, error := FindPreviousRequest(svc, store, claim, payload)
+		if error != nil {
+			return automation.HandleErrors(error, 500)
+		}
+		if conflict != nil {
+			return HandleConflict(conflict)
+		}
+		if previous != nil {
+			log.Println("Replaying response for previous request: ", previous.RequestID)
+			return AcceptedResponse(previous.Payload, previous.RequestID)
+		}
 
-func DetermineDestinationOU(svc organizationsiface.OrganizationsAPI, infraSecOUs map[string]string, envOU string, lob string) (string, error) {
-	if _, ok := infraSecOUs[lob]; ok {
-		return envOU, nil
+		log.Println("Reclaiming idempotency key from the previous request...")
+		_, error = store.ReclaimIdempotencyKey(idempotencyKey, claim)
+		if error == ErrIdempotencyKeyClaimed {
+			conflict := NewConflictError("request_in_progress", "error: a request with the same Idempotency-Key is still being processed")
+			return HandleConflict(conflict)
+		}
+		if error != nil {
+			return automation.HandleErrors(error, 500)
+		}
+	} else if error != nil {
+		return automation.HandleErrors(error, 500)
 	}
 
-	lobOUs, error := svc.ListOrganizationalUnitsForParent(&organizations.ListOrganizationalUnitsForParentInput{ParentId: &envOU})
+	log.Println("Running pre-flight checks...")
+	_, preflightError, error := Preflight(svc, h.Tree, payload)
 	if error != nil {
-		return "", error
+		ReleaseIdempotencyKey(store, idempotencyKey)
+		return automation.HandleErrors(error, 500)
 	}
+	if preflightError != nil {
+		ReleaseIdempotencyKey(store, idempotencyKey)
+		return HandlePreflightError(preflightError)
+	}
+	log.Println("Request passed pre-flight checks...")
 
-	var ou string
-	for _, tempOU := range lobOUs.OrganizationalUnits {
-		if *tempOU.Name == lob {
-			ou = *tempOU.Id
-			break
-		}
+	log.Println("Creating Account...")
+	requestID, error := CreateAccount(svc, payload.Name)
+	if error != nil {
+		ReleaseIdempotencyKey(store, idempotencyKey)
+		return automation.HandleErrors(error, 500)
 	}
 
-	return ou, nil
-}
+	// Bind the key before anything else can fail, so a retry replays this request instead of creating another account
+	error = store.BindIdempotencyKey(idempotencyKey, requestID)
+	if error != nil {
+		// The account is already being created, so report the request rather than failing it
+		log.Println("ERROR: binding idempotency key to request ", requestID, ": ", error.Error())
+	}
 
-func MoveAccount(svc organizationsiface.OrganizationsAPI, accountID string, root string, ou string) error {
-	if strings.EqualFold(_RUNTIME_ENV_, "prod") {
-		input := organizations.MoveAccountInput{
-			AccountId:           &accountID,
-			SourceParentId:      &root,
-			DestinationParentId: &ou,
-		}
-		_, error := svc.MoveAccount(&input)
-		return error
-	} else {
-		log.Println("Dev/Test environment detected - Your request will not trigger MoveAccount")
-		return nil
+	log.Println("Recording provisioning request...")
+	provisioningRequest := &ProvisioningRequest{RequestID: requestID, Payload: payload, IdempotencyKey: idempotencyKey}
+	followUp := FollowUpEvent{RequestID: requestID}
+	error = RecordTransition(store, provisioningRequest, RequestStateCreated, "")
+	if error != nil {
+		log.Println("ERROR: recording request ", requestID, ", retrying: ", error.Error())
+		error = PutWithRetry(store, provisioningRequest)
+	}
+	if error != nil {
+		// The account is already being created, so hand the follow-up the whole request to record rather than failing it
+		log.Println("ERROR: recording request ", requestID, ", leaving it to the follow-up: ", error.Error())
+		followUp.Request = provisioningRequest
 	}
+
+	log.Println("Scheduling follow-up to move and tag the account once created...")
+	error = ScheduleFollowUp(h.Lambda, followUp)
+	if error != nil {
+		RecordFailure(store, provisioningRequest, error)
+		return automation.HandleErrors(error, 500)
+	}
+
+	return AcceptedResponse(payload, requestID)
 }
 
-func GenerateTags(payload AccountPayload) []*organizations.Tag {
-	var tags []*organizations.Tag
-	val := reflect.ValueOf(payload)
-	typeOfS := val.Type()
-
-	// Iterate over fields of struct
-	for i := 0; i < val.NumField(); i++ {
-		key, value := typeOfS.Field(i).Name, fmt.Sprintf("%v", val.Field(i).Interface())
-		tag := &organizations.Tag{
-			Key:   &key,
-			Value: &value,
-		}
+func (h *Handler) HandleStatusRequest(request events.APIGatewayProxyRequest) (*events.APIGatewayProxyResponse, error) {
+	requestID := request.PathParameters["id"]
+	if requestID == "" {
+		return automation.HandleErrors(errors.New("error: create account request id is required"), 400)
+	}
 
-		if !strings.EqualFold(key, "AccountID") {
-			tags = append(tags, tag)
-		}
+	log.Println("Describing account creation status...")
+	status, error := DescribeProvisioningRequest(h.Org, requestID)
+	if error != nil {
+		return automation.HandleErrors(error, 500)
 	}
 
-	return tags
-}
+	log.Println("Looking up stored provisioning request...")
+	provisioningRequest, error := h.Store.Get(requestID)
+	if error != nil && error != ErrRequestNotFound {
+		return automation.HandleErrors(error, 500)
+	}
+	status.Request = provisioningRequest
 
-func TagAccount(svc organizationsiface.OrganizationsAPI, tags []*organizations.Tag, accountID string) error {
-	  else {
-		log.Println("Dev/Test environment detected - Your request will not trigger TagAccount")
-		return nil
+	log.Println("Stringifying response body...")
+	jsonResponseBody, error := json.Marshal(status)
+	if error != nil {
+		return automation.HandleErrors(error, 500)
 	}
-}
+	log.Println("Response payload: ", status)
 
-func HandleErrors(error error, statusCode int) (*events.APIGatewayProxyResponse, error) {
-	log.Println("ERROR: ", error.Error())
 	response := &events.APIGatewayProxyResponse{
-		StatusCode: statusCode,
-		Body:       error.Error(),
+		StatusCode: 200,
+		Body:       string(jsonResponseBody),
 	}
-
-	// return empty error to allow apigw to accurately represent statusCode and error.Error()
-	// removing nil will 'make' apigw think the lambda is not exiting gracefully
 	return response, nil
 }
 
-func GetClient() organizationsiface.OrganizationsAPI {
-	sess := session.Must(session.NewSession())
-	var svc organizationsiface.OrganizationsAPI
-	if strings.EqualFold(_RUNTIME_ENV_, "prod") {
-		log.Println("Production environment detected, setting up svc w/ MP creds...")
-		creds := stscreds.NewCredentials(sess, os.Getenv("ASSUME_ROLE_ARN"))
-		svc = organizations.New(sess, &aws.Config{Credentials: creds})
-	} else {
-		log.Println("Non-production environment detected, setting up mock svc...")
-		svc = mockOrganizationsClient{}
-	}
-	return svc
-}
+// StaleRequestTimeout is how long a request that is neither FAILED nor complete can go without a transition before it
+// is taken to have been stranded by a follow-up that timed out, ran out of memory or ran out of retries, and can be
+// resumed. It is longer than the create Lambda's 15 minute timeout, so a follow-up still running is not resumed.
+var StaleRequestTimeout = 20 * time.Minute
 
-func HandleRequest(request events.APIGatewayProxyRequest) (*events.APIGatewayProxyResponse, error) {
-	log.Println("Setting up session, assume role, and org client...")
-	svc := GetClient()
+func (h *Handler) HandleResumeRequest(request events.APIGatewayProxyRequest) (*events.APIGatewayProxyResponse, error) {
+	store := h.Store
 
-	log.Println("Serializing Payload...")
-	payload, error := ProcessRequestPayload(request.Body)
-	if error != nil {
-		return HandleErrors(error, 500)
+	requestID := request.PathParameters["id"]
+	if requestID == "" {
+		return automation.HandleErrors(errors.New("error: create account request id is required"), 400)
+	}
+	if h.Mode == automation.ModeDryRun {
+		return automation.HandleErrors(errors.New("error: provisioning requests cannot be resumed in dry-run mode"), 400)
 	}
-	log.Println("Payload serialized without error...")
 
-	log.Println("Validating payload...")
-	error = ValidatePayload(payload)
+	log.Println("Looking up stored provisioning request...")
+	provisioningRequest, error := store.Get(requestID)
+	if error == ErrRequestNotFound {
+		return automation.HandleErrors(error, 404)
+	}
 	if error != nil {
-		return HandleErrors(error, 400)
+		return automation.HandleErrors(error, 500)
+	}
+	// Only a failed request, or one whose follow-up died without recording a failure, is resumed, so a resume cannot
+	// run the steps alongside a follow-up still working on them
+	stale := time.Since(provisioningRequest.UpdatedAt) > StaleRequestTimeout
+	if NextStep(provisioningRequest) == len(ProvisioningSteps) {
+		conflict := NewConflictError("request_complete", "error: provisioning request is already complete")
+		conflict.AccountID, conflict.RequestID = provisioningRequest.AccountID, requestID
+		return HandleConflict(conflict)
+	}
+	if provisioningRequest.State != RequestStateFailed && !stale {
+		conflict := NewConflictError("request_not_failed", "error: provisioning request is "+provisioningRequest.State+", only a FAILED request, or one not updated for "+StaleRequestTimeout.String()+", can be resumed")
+		conflict.AccountID, conflict.RequestID = provisioningRequest.AccountID, requestID
+		return HandleConflict(conflict)
 	}
-	log.Println("Payload does not appear malformed...")
 
-	log.Println("Creating Account...")
-	requestID, error := CreateAccount(svc, payload.Name)
+	// Recording the resume makes the request neither FAILED nor stale, so it is not resumed twice
+	log.Println("Recording resume from checkpoint ", provisioningRequest.Checkpoint, "...")
+	error = RecordTransition(store, provisioningRequest, provisioningRequest.Checkpoint, "resumed")
 	if error != nil {
-		return HandleErrors(error, 500)
+		return automation.HandleErrors(error, 500)
 	}
 
-	log.Println("Validating account creation status...")
-	accountID, error := ValidateAccountStatus(svc, requestID)
+	log.Println("Scheduling follow-up to resume from checkpoint ", provisioningRequest.Checkpoint, "...")
+	error = ScheduleFollowUp(h.Lambda, FollowUpEvent{RequestID: requestID})
 	if error != nil {
-		return HandleErrors(error, 500)
+		RecordFailure(store, provisioningRequest, error)
+		return automation.HandleErrors(error, 500)
 	}
 
-	log.Println("Retrieving Root ID and correct OU ID based on payload...")
-	root, ou, error := RetrieveOUs(svc, payload)
+	log.Println("Stringifying response body...")
+	provisioningRequest, error = store.Get(requestID)
 	if error != nil {
-		return HandleErrors(error, 500)
+		return automation.HandleErrors(error, 500)
 	}
-
-	log.Println("Moving account to correct OU...")
-	error = MoveAccount(svc, accountID, root, ou)
+	jsonResponseBody, error := json.Marshal(provisioningRequest)
 	if error != nil {
-		return HandleErrors(error, 500)
+		return automation.HandleErrors(error, 500)
+	}
+	log.Println("Response payload: ", provisioningRequest)
+
+	response := &events.APIGatewayProxyResponse{
+		StatusCode: 202,
+		Body:       string(jsonResponseBody),
 	}
+	return response, nil
+}
+
+// FollowUpDeadlineMargin is how long before the invocation's deadline the follow-up stops waiting for the account,
+// leaving time to record the request and schedule the next follow-up.
+var FollowUpDeadlineMargin = 30 * time.Second
 
-	log.Println("Generating a list of Tag objects from payload...")
-	tags := GenerateTags(payload)
-	log.Println("Tags: ", tags)
+// AccountCreationTimeout is how long after a request was made its follow-ups keep waiting for Organizations to
+// create the account before the request is recorded as FAILED.
+var AccountCreationTimeout = time.Hour
+
+func (h *Handler) HandleFollowUp(ctx context.Context, event FollowUpEvent) error {
+	if h.Mode == automation.ModeDryRun {
+		return errors.New("error: follow-ups are not run in dry-run mode")
+	}
+	if event.Request != nil {
+		error := RestoreRequest(h.Store, event.Request)
+		if error != nil {
+			return error
+		}
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline.Add(-FollowUpDeadlineMargin))
+		defer cancel()
+	}
+	error := CompleteProvisioning(ctx, h.Org, h.Tree, h.Store, event.RequestID)
+	if error == ErrAccountCreationPending {
+		return h.RescheduleFollowUp(event.RequestID)
+	}
+	return error
+}
 
-	log.Println("Tagging Account...")
-	error = TagAccount(svc, tags, accountID)
+// RescheduleFollowUp hands a request whose account is still being created to a new follow-up, as this invocation is
+// about to time out, or records it as FAILED once it has waited AccountCreationTimeout.
+func (h *Handler) RescheduleFollowUp(requestID string) error {
+	request, error := h.Store.Get(requestID)
 	if error != nil {
-		return HandleErrors(error, 500)
+		return error
+	}
+	if time.Since(request.CreatedAt) > AccountCreationTimeout {
+		// the request can be resumed once Organizations has finished, so the follow-up is not retried
+		RecordFailure(h.Store, request, errors.New("error: account creation did not finish within "+AccountCreationTimeout.String()))
+		return nil
 	}
 
-	log.Println("Stringifying response body...")
-	payload.AccountID = accountID
-	jsonResponseBody, error := json.Marshal(payload)
+	log.Println("Account creation still in progress, scheduling another follow-up...")
+	error = RecordTransition(h.Store, request, request.Checkpoint, "account creation still IN_PROGRESS, follow-up rescheduled")
 	if error != nil {
-		return HandleErrors(error, 500)
+		return error
+	}
+	error = ScheduleFollowUp(h.Lambda, FollowUpEvent{RequestID: requestID})
+	if error != nil {
+		RecordFailure(h.Store, request, error)
+		return error
 	}
-	log.Println("Response payload: ", payload)
+	return nil
+}
 
-	response := &events.APIGatewayProxyResponse{
-		StatusCode: 200,
-		Body:       string(jsonResponseBody),
+// HandleEvent routes an invocation to the follow-up or to the handler for the API GW resource.
+// Errors from the follow-up are returned so the asynchronous invocation is retried. ctx carries the invocation's
+// deadline, which the follow-up stops waiting for the account before.
+func (h *Handler) HandleEvent(ctx context.Context, event Event) (*events.APIGatewayProxyResponse, error) {
+	if event.FollowUp != nil {
+		return nil, h.HandleFollowUp(ctx, *event.FollowUp)
+	}
+
+	switch event.Resource {
+	case "/accounts/requests/{id}":
+		return h.HandleStatusRequest(event.APIGatewayProxyRequest)
+	case "/accounts/requests/{id}/resume":
+		return h.HandleResumeRequest(event.APIGatewayProxyRequest)
+	default:
+		return h.HandleRequest(event.APIGatewayProxyRequest)
 	}
-	return response, nil
 }
 
-func main() {
-	lambda.Start(HandleRequest)
+// HandleProxyRequest handles an API GW request, the only event that is not a follow-up.
+func (h *Handler) HandleProxyRequest(request events.APIGatewayProxyRequest) (*events.APIGatewayProxyResponse, error) {
+	return h.HandleEvent(context.Background(), Event{APIGatewayProxyRequest: request})
 }
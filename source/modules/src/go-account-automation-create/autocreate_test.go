@@ -0,0 +1,115 @@
+package createlambda
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/fakeorg"
+)
+
+func TestRetrieveWorkloadOUAutoCreate(t *testing.T) {
+	for variable, value := range map[string]string{
+		"WORKLOAD_OU":            "ou-abcd-workload",
+		"SEC_OU":                 `{"SEC":"ou-abcd-security"}`,
+		"AUTO_CREATE_OU_PARENTS": `["ou-abcd-workload"]`,
+	} {
+		defer os.Setenv(variable, os.Getenv(variable))
+		error := os.Setenv(variable, value)
+		if error != nil {
+			t.Fatal(error.Error())
+		}
+	}
+	svc := placementOrg()
+	autoCreate, error := automation.LoadOUAutoCreation(svc, nil)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+
+	//test that a missing env and lob are both created, the lob under the new env
+	_, ou, error := automation.RetrieveWorkloadOU(svc, nil, automation.AccountPayload{Lob: "OPS", Env: "Test"}, autoCreate)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	env := svc.Parent(ou)
+	if ou == "" || svc.Parent(env) != "ou-abcd-workload" {
+		t.Fatal("Unexpected OU: ", ou)
+	}
+	if svc.Calls("CreateOrganizationalUnit") != 2 {
+		t.Fatal("Unexpected OUs created: ", svc.Calls("CreateOrganizationalUnit"))
+	}
+	for _, created := range []string{env, ou} {
+		if !cmp.Equal(svc.Tags(created), map[string]string{automation.ManagedByTag: automation.ManagedByValue}) {
+			t.Fatal("Created OU was not tagged as managed: ", created, svc.Tags(created))
+		}
+	}
+
+	//test that the created OUs are found by the next request
+	nextAutoCreate, error := automation.LoadOUAutoCreation(svc, nil)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	_, nextOU, error := automation.RetrieveWorkloadOU(svc, nil, automation.AccountPayload{Lob: "OPS", Env: "Test"}, nextAutoCreate)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if nextOU != ou || svc.Calls("CreateOrganizationalUnit") != 2 {
+		t.Fatal("Existing OUs were expected to be reused: ", nextOU, svc.Calls("CreateOrganizationalUnit"))
+	}
+
+	//test that nothing is created under a parent that is not allowed
+	_, ou, error = automation.RetrieveWorkloadOU(svc, nil, automation.AccountPayload{Lob: "SEC", Env: "Lab"}, autoCreate)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if ou != "" || svc.Calls("CreateOrganizationalUnit") != 2 {
+		t.Fatal("No OU was expected to be created under the Security OU: ", ou, svc.Calls("CreateOrganizationalUnit"))
+	}
+}
+
+func TestRetrieveOUsAutoCreate(t *testing.T) {
+	for variable, value := range map[string]string{
+		"PLACEMENT_RULES":        testPlacementRules,
+		"AUTO_CREATE_OU_PARENTS": `["/Workloads/Dev"]`,
+	} {
+		defer os.Setenv(variable, os.Getenv(variable))
+		error := os.Setenv(variable, value)
+		if error != nil {
+			t.Fatal(error.Error())
+		}
+	}
+	svc := placementOrg()
+
+	root, ou, error := RetrieveOUs(svc, nil, automation.AccountPayload{Lob: "OPS", Env: "Dev"})
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if root != fakeorg.RootID || ou == "" || svc.Parent(ou) != "ou-abcd-wkdev" {
+		t.Fatal("RetrieveOUs did not create the missing lob OU: ", root, ou)
+	}
+
+	//test that an env missing from a parent that is not allowed is still an error
+	_, _, error = RetrieveOUs(svc, nil, automation.AccountPayload{Lob: "OPS", Env: "Test"})
+	if error == nil {
+		t.Fatal("RetrieveOUs was expected to fail for an OU it may not create but didn't")
+	}
+	if svc.Calls("CreateOrganizationalUnit") != 1 {
+		t.Fatal("Unexpected OUs created: ", svc.Calls("CreateOrganizationalUnit"))
+	}
+
+	//test that auto-creation is off by default
+	os.Setenv("AUTO_CREATE_OU_PARENTS", "")
+	autoCreate, error := automation.LoadOUAutoCreation(svc, nil)
+	if autoCreate != nil || error != nil {
+		t.Fatal("Auto-creation was expected to be off: ", autoCreate, error)
+	}
+
+	//test that an allowed parent that does not exist is an error
+	os.Setenv("AUTO_CREATE_OU_PARENTS", `["/Missing"]`)
+	_, error = automation.LoadOUAutoCreation(svc, nil)
+	if error == nil {
+		t.Fatal("A missing allowed parent was expected to fail but didn't")
+	}
+}
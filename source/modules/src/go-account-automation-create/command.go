@@ -0,0 +1,87 @@
+package createlambda
+
+import (
+	"context"
+	"errors"
+	"flag"
+
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+
+	"go-account-automation/internal/automation"
+)
+
+// Commands are the operator commands the account-automation command runs with h for this Lambda's routes.
+func (h *Handler) Commands() map[string]automation.Command {
+	return map[string]automation.Command{
+		"create": h.CreateCommand,
+		"plan":   h.PlanCommand,
+	}
+}
+
+// PlanAccount runs the pre-flight checks with a read-only client and returns the plan for payload, or the
+// *PreflightError listing every problem with it as both the result and the error.
+func PlanAccount(svc organizationsiface.OrganizationsAPI, tree *automation.OrgTree, payload automation.AccountPayload) (interface{}, error) {
+	plan, preflightError, error := Preflight(automation.ReadOnlyClient{OrganizationsAPI: svc}, tree, payload)
+	if error != nil {
+		return nil, error
+	}
+	if preflightError != nil {
+		return preflightError, preflightError
+	}
+	plan.DryRun = true
+	return plan, nil
+}
+
+// CreateCommand creates the account described by the payload, generating its name from appName as POST /accounts
+// does, and then, as the follow-up does, waits for it to be created, moves it to its OU and tags it. The result is
+// the provisioning request, as recorded in the request store. With -dry-run, or in dry-run mode, it returns the plan instead, as the plan command does.
+func (h *Handler) CreateCommand(flags *flag.FlagSet) func(args []string) (interface{}, error) {
+	file := flags.String("f", "-", "file holding the account payload as JSON, - for stdin")
+	dryRun := flags.Bool("dry-run", false, "return the plan without making changes")
+
+	return func(args []string) (interface{}, error) {
+		payload, error := ReadCreatePayload(*file)
+		if error != nil {
+			return nil, error
+		}
+		if h.Mode == automation.ModeDryRun || *dryRun {
+			return PlanAccount(h.Org, h.Tree, payload)
+		}
+
+		_, preflightError, error := Preflight(h.Org, h.Tree, payload)
+		if error != nil {
+			return nil, error
+		}
+		if preflightError != nil {
+			return preflightError, preflightError
+		}
+
+		requestID, error := CreateAccount(h.Org, payload.Name)
+		if error != nil {
+			return nil, error
+		}
+		error = RecordTransition(h.Store, &ProvisioningRequest{RequestID: requestID, Payload: payload}, RequestStateCreated, "")
+		if error != nil {
+			return nil, error
+		}
+		error = CompleteProvisioning(context.Background(), h.Org, h.Tree, h.Store, requestID)
+		request, storeError := h.Store.Get(requestID)
+		if storeError != nil {
+			return nil, errors.New("error: reading back request " + requestID + ": " + storeError.Error())
+		}
+		return request, error
+	}
+}
+
+// PlanCommand returns the plan for the account described by the payload without making changes.
+func (h *Handler) PlanCommand(flags *flag.FlagSet) func(args []string) (interface{}, error) {
+	file := flags.String("f", "-", "file holding the account payload as JSON, - for stdin")
+
+	return func(args []string) (interface{}, error) {
+		payload, error := ReadCreatePayload(*file)
+		if error != nil {
+			return nil, error
+		}
+		return PlanAccount(h.Org, h.Tree, payload)
+	}
+}
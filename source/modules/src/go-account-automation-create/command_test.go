@@ -0,0 +1,85 @@
+package createlambda
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/fakeorg"
+)
+
+// writePayloadFile writes payload to a temporary file for a command's -f flag and returns its name.
+func writePayloadFile(t *testing.T, payload automation.AccountPayload) string {
+	file, error := ioutil.TempFile("", "payload-*.json")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	defer file.Close()
+	body, _ := json.Marshal(payload)
+	file.Write(body)
+	return file.Name()
+}
+
+func TestRunCommand(t *testing.T) {
+	defer func(interval time.Duration) { AccountStatusPollInterval = interval }(AccountStatusPollInterval)
+	AccountStatusPollInterval = 0
+
+	svc := fakeorg.NewClient()
+	workloads := svc.AddOU(fakeorg.RootID, "ou-abcd-01234567", "Workloads")
+	svc.AddOU(svc.AddOU(workloads, "", "Dev"), "", "APP")
+	h := &Handler{Mode: automation.ModeLive, Org: svc, Store: NewMemoryRequestStore(), Lambda: &mockLambdaClient{}}
+	payloadFile := writePayloadFile(t, preflightPayload())
+	defer os.Remove(payloadFile)
+	brokenPayload := preflightPayload()
+	brokenPayload.Name = "AWS_APP_test"
+	brokenPayloadFile := writePayloadFile(t, brokenPayload)
+	defer os.Remove(brokenPayloadFile)
+
+	testCases := []struct {
+		name           string
+		args           []string
+		expectedStatus int
+		expectedOutput string
+	}{
+		{"no command", nil, 2, ""},
+		{"unknown command", []string{"delete"}, 2, ""},
+		{"unknown flag", []string{"plan", "-x"}, 2, ""},
+		{"missing file", []string{"plan", "-f", "missing.json"}, 1, ""},
+		{"plan", []string{"plan", "-f", payloadFile}, 0, "dryRun: true"},
+		{"plan with problems", []string{"plan", "-f", brokenPayloadFile}, 1, "problems[0].field:"},
+		{"create dry-run", []string{"create", "-dry-run", "-f", payloadFile}, 0, "dryRun: true"},
+		{"create", []string{"create", "-f", payloadFile}, 0, "state: " + RequestStateTagged},
+	}
+	//test that -json writes the result as JSON
+	var stdout bytes.Buffer
+	if automation.RunCommand(h.Commands(), []string{"plan", "-json", "-f", payloadFile}, &stdout, ioutil.Discard) != 0 {
+		t.Fatal("Plan was expected to succeed")
+	}
+	var plan automation.Plan
+	if error := json.Unmarshal(stdout.Bytes(), &plan); error != nil || !plan.DryRun {
+		t.Fatal("Plan was not written as JSON: ", stdout.String())
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			status := automation.RunCommand(h.Commands(), testCase.args, &stdout, &stderr)
+			if status != testCase.expectedStatus {
+				t.Fatal("Unexpected exit status: ", status, stdout.String(), stderr.String())
+			}
+			if !strings.Contains(stdout.String(), testCase.expectedOutput) {
+				t.Fatal("Unexpected output: ", stdout.String(), stderr.String())
+			}
+		})
+	}
+
+	//test that only the create command made an account
+	if svc.Calls("CreateAccount") != 1 {
+		t.Fatal("Unexpected account creations: ", svc.Calls("CreateAccount"))
+	}
+}
@@ -0,0 +1,38 @@
+package createlambda
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+
+	"go-account-automation/internal/automation"
+)
+
+// HandleDryRun runs the pre-flight checks with a read-only client and returns the plan for the request
+// without claiming its idempotency key or creating anything.
+func HandleDryRun(svc organizationsiface.OrganizationsAPI, tree *automation.OrgTree, payload automation.AccountPayload) (*events.APIGatewayProxyResponse, error) {
+	log.Println("Dry run requested, planning the request without making changes...")
+	plan, preflightError, error := Preflight(automation.ReadOnlyClient{OrganizationsAPI: svc}, tree, payload)
+	if error != nil {
+		return automation.HandleErrors(error, 500)
+	}
+	if preflightError != nil {
+		return HandlePreflightError(preflightError)
+	}
+	plan.DryRun = true
+
+	log.Println("Stringifying response body...")
+	jsonResponseBody, error := json.Marshal(plan)
+	if error != nil {
+		return automation.HandleErrors(error, 500)
+	}
+	log.Println("Plan: ", string(jsonResponseBody))
+
+	response := &events.APIGatewayProxyResponse{
+		StatusCode: 200,
+		Body:       string(jsonResponseBody),
+	}
+	return response, nil
+}
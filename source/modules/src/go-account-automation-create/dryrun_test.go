@@ -0,0 +1,88 @@
+package createlambda
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/fakeorg"
+)
+
+func TestHandleDryRun(t *testing.T) {
+	for variable, value := range map[string]string{
+		"WORKLOAD_OU":            "ou-abcd-workload",
+		"SEC_OU":                 `{"SEC":"ou-abcd-security"}`,
+		"AUTO_CREATE_OU_PARENTS": `["ou-abcd-workload"]`,
+	} {
+		defer os.Setenv(variable, os.Getenv(variable))
+		error := os.Setenv(variable, value)
+		if error != nil {
+			t.Fatal(error.Error())
+		}
+	}
+	svc := placementOrg()
+
+	payload := preflightPayload()
+	payload.Name, payload.Lob, payload.Env = "AWS_OPS_test_Lab", "OPS", "Lab"
+	response, _ := HandleDryRun(svc, nil, payload)
+	if response.StatusCode != 200 {
+		t.Fatal("Unexpected response: ", response.StatusCode, response.Body)
+	}
+
+	var plan automation.Plan
+	error := json.Unmarshal([]byte(response.Body), &plan)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	expectedPlan := automation.Plan{
+		DryRun: true,
+		Name:   "AWS_OPS_test_Lab",
+		Email:  "AWS_OPS_test_Lab@example.com",
+		DestinationOU: &automation.Destination{
+			ID:   "planned:ou-abcd-workload/Lab/OPS",
+			Path: "ou-abcd-workload/Lab/OPS",
+			OUsToCreate: []automation.CreatedOU{
+				{ParentID: "ou-abcd-workload", Name: "Lab", ID: "planned:ou-abcd-workload/Lab"},
+				{ParentID: "planned:ou-abcd-workload/Lab", Name: "OPS", ID: "planned:ou-abcd-workload/Lab/OPS"},
+			},
+		},
+		Tags: automation.TagChanges{Add: map[string]string{
+			"Name":          "AWS_OPS_test_Lab",
+			"CostCenter":    "01234",
+			"AccountPOC":    "john.doe@example.com",
+			"ApplicationID": "00000000-0000-0000-0000-000000000000",
+			"Env":           "Lab",
+			"Lob":           "OPS",
+		}},
+	}
+	if !cmp.Equal(plan, expectedPlan) {
+		t.Fatal("Unexpected plan: ", cmp.Diff(expectedPlan, plan))
+	}
+	for _, operation := range []string{"CreateAccount", "CreateOrganizationalUnit", "MoveAccount", "TagResource"} {
+		if svc.Calls(operation) != 0 {
+			t.Fatal("A dry run was not expected to call ", operation)
+		}
+	}
+
+	//test that a request failing pre-flight checks is reported the same way in a dry run
+	payload.Lob = "APP"
+	response, _ = HandleDryRun(svc, nil, payload)
+	if response.StatusCode != 400 {
+		t.Fatal("Unexpected response: ", response.StatusCode, response.Body)
+	}
+}
+
+func TestReadOnlyClient(t *testing.T) {
+	svc := automation.ReadOnlyClient{OrganizationsAPI: placementOrg()}
+	_, error := CreateAccount(svc, "AWS_SEC_test_Dev")
+	if error == nil {
+		t.Fatal("CreateAccount was expected to fail during a dry run but didn't")
+	}
+	root, error := automation.RetrieveRoot(svc)
+	if error != nil || root != fakeorg.RootID {
+		t.Fatal("Read-only calls were expected to go through: ", root, error)
+	}
+}
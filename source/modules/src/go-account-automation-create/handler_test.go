@@ -1,14 +1,22 @@
-package main
+package createlambda
 
 import (
+	"context"
+	"encoding/json"
 	"io/ioutil"
 	"log"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/organizations"
 	"github.com/google/go-cmp/cmp"
+
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/fakeorg"
 )
 
 func TestProcessRequestPayload(t *testing.T) {
@@ -24,12 +32,12 @@ func TestProcessRequestPayload(t *testing.T) {
 	}
 
 	//test
-	payload, error := ProcessRequestPayload(string(byteValue))
+	payload, error := automation.ProcessRequestPayload(string(byteValue))
 	if error != nil {
 		t.Fatal("Error serializing payload: ", error.Error())
 	}
 
-	if !strings.EqualFold(payload.Name, "aws_SEC_test_Dev") ||
+	if !strings.EqualFold(payload.Name, "AWS_SEC_test_Dev") ||
 		!strings.EqualFold(payload.CostCenter, "01234") ||
 		!strings.EqualFold(payload.AccountPOC, "john.doe@example.com") ||
 		!strings.EqualFold(payload.ApplicationID, "00000000-0000-0000-0000-000000000000") ||
@@ -40,138 +48,295 @@ func TestProcessRequestPayload(t *testing.T) {
 	}
 }
 
-func TestValidatePayload(t *testing.T) {
-	testPayload := AccountPayload{
-		Name:          "aws_SEC_test_Dev",
-		AccountPOC:    "01234",
-		ApplicationID: "john.doe@example.com",
-		Env:           "Dev",
-		Lob:           "SEC",
-		AccountID:     "",
-	}
-	error := ValidatePayload(testPayload)
-	if error != nil {
-		t.Fatal("Payload failed validation: ", error.Error())
-	}
-
-	//test for malformed payload catch
-	testPayload.Name = "aws_IS_test_Dev"
-	error = ValidatePayload(testPayload)
-	if error == nil {
-		t.Fatal("Payload was expected to fail but didn't ")
-
-	}
+// secOrg returns an organization with the Security OU of the SEC and IS lobs, each with a Dev OU,
+// and the Workloads OU.
+func secOrg() *fakeorg.Client {
+	svc := fakeorg.NewClient()
+	svc.AddOU(fakeorg.RootID, "ou-abcd-01234567", "Workloads")
+	svc.AddOU(fakeorg.RootID, "ou-abcd-12345678", "Security")
+	svc.AddOU(fakeorg.RootID, "ou-abcd-23456789", "IS")
+	svc.AddOU("ou-abcd-12345678", "ou-abcd-secdev", "Dev")
+	svc.AddOU("ou-abcd-23456789", "ou-abcd-isdev", "Dev")
+	return svc
 }
 
 func TestRetrieveOUs(t *testing.T) {
-	svc := mockOrganizationsClient{
-		destENV:   "Dev",
-		destOUID:  "ou-abcd-12345678",
-		orgRootID: "r-abcd",
-		createErr: nil,
-	}
-	payload := AccountPayload{
-		Name:          "aws_SEC_test_Dev",
+	svc := secOrg()
+	payload := automation.AccountPayload{
+		Name:          "AWS_SEC_test_Dev",
 		CostCenter:    "01234",
 		AccountPOC:    "john.doe@example.com",
 		ApplicationID: "00000000-0000-0000-0000-000000000000",
 		Env:           "DEV",
 		Lob:           "SEC",
 	}
-	root, ou, error := RetrieveOUs(svc, payload)
+	root, ou, error := RetrieveOUs(svc, nil, payload)
 	if error != nil {
 		t.Fatal("RetrieveOUs failed")
 	}
 
-	if !strings.EqualFold(root, svc.orgRootID) || !strings.EqualFold(ou, svc.destOUID) {
+	if root != fakeorg.RootID || ou != "ou-abcd-secdev" {
 		t.Fatal("RetrieveOUs output not as expected")
 	}
 }
 
-func TestCreateAccount(t *testing.T) {
-	accountName := "aws_SEC_test_Dev"
-	svc := mockOrganizationsClient{
-		createID:  "car-012345678912",
-		createErr: nil,
+func TestRetrieveOUsAcrossPages(t *testing.T) {
+	svc := fakeorg.NewClient()
+	svc.PageSize = 2
+	svc.AddOU(fakeorg.RootID, "ou-abcd-01234567", "Workloads")
+	for _, name := range []string{"Lab", "Test", "Prod", "Dev"} {
+		svc.AddOU("ou-abcd-01234567", "ou-abcd-"+name, name)
+	}
+	for _, name := range []string{"AAA", "BBB", "CCC", "DDD", "APP"} {
+		svc.AddOU("ou-abcd-Dev", "ou-abcd-"+name, name)
+	}
+
+	root, ou, error := RetrieveOUs(svc, nil, automation.AccountPayload{Env: "DEV", Lob: "APP"})
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if root != fakeorg.RootID || ou != "ou-abcd-APP" {
+		t.Fatal("RetrieveOUs did not find OUs past the first page: ", root, ou)
+	}
+	// two pages of env OUs and three of lob OUs
+	if svc.Calls("ListRoots") != 1 || svc.Calls("ListOrganizationalUnitsForParent") != 5 {
+		t.Fatal("Unexpected number of pages read: ", svc.Calls("ListRoots"), svc.Calls("ListOrganizationalUnitsForParent"))
+	}
+}
+
+func TestRetrieveOUsPerHandler(t *testing.T) {
+	// newOrg returns an organization whose Workloads/Dev/APP OU has the ID appOU
+	newOrg := func(appOU string) *fakeorg.Client {
+		svc := fakeorg.NewClient()
+		svc.AddOU(fakeorg.RootID, "ou-abcd-01234567", "Workloads")
+		svc.AddOU("ou-abcd-01234567", "ou-abcd-wkdev", "Dev")
+		svc.AddOU("ou-abcd-wkdev", appOU, "APP")
+		return svc
+	}
+	first := &Handler{Mode: automation.ModeLive, Org: newOrg("ou-abcd-firstapp"), Tree: automation.NewOrgTree(time.Hour)}
+	second := &Handler{Mode: automation.ModeLive, Org: newOrg("ou-abcd-secondapp"), Tree: automation.NewOrgTree(time.Hour)}
+
+	//test that each handler caches the OUs of its own organization, whichever handler looked them up first
+	for i := 0; i < 2; i++ {
+		for h, expectedOU := range map[*Handler]string{first: "ou-abcd-firstapp", second: "ou-abcd-secondapp"} {
+			_, ou, error := RetrieveOUs(h.Org, h.Tree, automation.AccountPayload{Env: "Dev", Lob: "APP"})
+			if error != nil {
+				t.Fatal(error.Error())
+			}
+			if ou != expectedOU {
+				t.Fatal("Unexpected OU: ", ou, " expected ", expectedOU)
+			}
+		}
+	}
+	if calls := first.Org.(*fakeorg.Client).Calls("ListOrganizationalUnitsForParent"); calls != 2 {
+		t.Fatal("The second lookup was expected to be served by the handler's tree: ", calls)
+	}
+}
+
+func TestMoveAccount(t *testing.T) {
+	svc := secOrg()
+	accountID := svc.AddAccount("ou-abcd-01234567", "AWS_SEC_test_Dev", "AWS_SEC_test_Dev@example.com", nil)
+	error := automation.MoveAccount(svc, accountID, "ou-abcd-secdev")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if svc.Calls("MoveAccount") != 1 || svc.Parent(accountID) != "ou-abcd-secdev" {
+		t.Fatal("MoveAccount did not move the account from its actual parent: ", svc.Parent(accountID))
 	}
 
+	//test that an account already in the destination OU is not moved again
+	error = automation.MoveAccount(svc, accountID, "ou-abcd-secdev")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if svc.Calls("MoveAccount") != 1 {
+		t.Fatal("MoveAccount was called for an account already in the destination OU")
+	}
+
+	//test that losing a race to the same move is not an error
+	rootAccountID := svc.AddAccount(fakeorg.RootID, "AWS_SEC_race_Dev", "AWS_SEC_race_Dev@example.com", nil)
+	svc.Fault("MoveAccount", awserr.New(organizations.ErrCodeDuplicateAccountException, "account already in destination", nil))
+	error = automation.MoveAccount(svc, rootAccountID, "ou-abcd-secdev")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if svc.Calls("MoveAccount") != 2 {
+		t.Fatal("MoveAccount was not called from the root: ", svc.Calls("MoveAccount"))
+	}
+
+	svc.Fault("MoveAccount", awserr.New(organizations.ErrCodeAccountNotFoundException, "account not found", nil))
+	error = automation.MoveAccount(svc, rootAccountID, "ou-abcd-secdev")
+	if error == nil {
+		t.Fatal("MoveAccount was expected to fail but didn't")
+	}
+}
+
+func TestCreateAccount(t *testing.T) {
+	accountName := "AWS_SEC_test_Dev"
+	svc := fakeorg.NewClient()
+
 	requestID, error := CreateAccount(svc, accountName)
 	if error != nil {
 		t.Fatal("Account Creation Failed:", error.Error())
 	}
-	if requestID != "car-012345678912" {
+	if !strings.HasPrefix(requestID, "car-") || svc.Calls("CreateAccount") != 1 {
 		log.Println("requestID:", requestID)
 		t.Fatal("Account Creation returned unexpected output")
 	}
 }
 
 func TestValidateAccountStatus(t *testing.T) {
-	svc := mockOrganizationsClient{
-		createState: organizations.CreateAccountStateSucceeded,
-		createErr:   nil,
-		createID:    "car-012345678912",
+	defer func(interval time.Duration) { AccountStatusPollInterval = interval }(AccountStatusPollInterval)
+	AccountStatusPollInterval = 0
+	svc := fakeorg.NewClient()
+	svc.CreatePolls = 1
+	requestID, error := CreateAccount(svc, "AWS_SEC_test_Dev")
+	if error != nil {
+		t.Fatal(error.Error())
 	}
-	accountID, error := ValidateAccountStatus(svc, svc.createID)
+	accountID, error := ValidateAccountStatus(context.Background(), svc, requestID)
 	if error != nil {
 		t.Fatal(error.Error())
 	}
-	if accountID != "999999999999" {
+	if accountID == "" || svc.Parent(accountID) != fakeorg.RootID {
 		t.Fatal("Account ID was not as expected")
 	}
 }
 
-func TestGenerateTags(t *testing.T) {
-	payload := AccountPayload{
-		Name:          "aws_SEC_test_Dev",
+func TestDescribeProvisioningRequest(t *testing.T) {
+	svc := fakeorg.NewClient()
+	requestID, error := CreateAccount(svc, "AWS_SEC_test_Dev")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	status, error := DescribeProvisioningRequest(svc, requestID)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if status.RequestID != requestID || status.State != organizations.CreateAccountStateSucceeded || status.AccountID == "" {
+		t.Fatal("Provisioning status was not as expected: ", status)
+	}
+
+	//test that a failure reason is reported
+	svc.FailAccountCreation("AWS_SEC_failed_Dev", organizations.CreateAccountFailureReasonEmailAlreadyExists)
+	requestID, error = CreateAccount(svc, "AWS_SEC_failed_Dev")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	status, error = DescribeProvisioningRequest(svc, requestID)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if status.State != organizations.CreateAccountStateFailed || status.FailureReason != organizations.CreateAccountFailureReasonEmailAlreadyExists {
+		t.Fatal("Provisioning status was expected to report the failure reason: ", status)
+	}
+}
+
+func TestScheduleFollowUp(t *testing.T) {
+	svc := &mockLambdaClient{}
+	error := ScheduleFollowUp(svc, FollowUpEvent{RequestID: "car-012345678912"})
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if svc.invokeInput == nil || *svc.invokeInput.InvocationType != "Event" {
+		t.Fatal("Follow-up was expected to be invoked asynchronously")
+	}
+
+	var event Event
+	error = json.Unmarshal(svc.invokeInput.Payload, &event)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if event.FollowUp == nil || event.FollowUp.RequestID != "car-012345678912" {
+		t.Fatal("Follow-up event was not as expected")
+	}
+}
+
+func TestCompleteProvisioning(t *testing.T) {
+	svc := secOrg()
+	store := NewMemoryRequestStore()
+	payload := automation.AccountPayload{
+		Name:          "AWS_SEC_test_Dev",
 		CostCenter:    "01234",
 		AccountPOC:    "john.doe@example.com",
 		ApplicationID: "00000000-0000-0000-0000-000000000000",
 		Env:           "DEV",
 		Lob:           "SEC",
 	}
-	name, costCenter, accountPOC, applicationID, env, lob := "Name", "CostCenter", "AccountPOC", "ApplicationID", "Env", "Lob"
-	expectedTags := []*organizations.Tag{
-		{
-			Key:   &name,
-			Value: &payload.Name,
-		},
-		{
-			Key:   &costCenter,
-			Value: &payload.CostCenter,
-		},
-		{
-			Key:   &accountPOC,
-			Value: &payload.AccountPOC,
-		},
-		{
-			Key:   &applicationID,
-			Value: &payload.ApplicationID,
-		},
-		{
-			Key:   &env,
-			Value: &payload.Env,
-		},
-		{
-			Key:   &lob,
-			Value: &payload.Lob,
-		},
-	}
-	tags := GenerateTags(payload)
-
-	if !cmp.Equal(tags, expectedTags) {
-		t.Fatal("Account tagging returned unexpected output")
+	requestID, error := CreateAccount(svc, payload.Name)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	error = RecordTransition(store, &ProvisioningRequest{RequestID: requestID, Payload: payload}, RequestStateCreated, "")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+
+	error = CompleteProvisioning(context.Background(), svc, nil, store, requestID)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	request, error := store.Get(requestID)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if request.State != RequestStateTagged || request.AccountID == "" || request.RootID != fakeorg.RootID || request.OUID != "ou-abcd-secdev" {
+		t.Fatal("Stored request was not as expected: ", request)
+	}
+	if svc.Parent(request.AccountID) != "ou-abcd-secdev" || svc.Tags(request.AccountID)["Lob"] != "SEC" {
+		t.Fatal("Account was not moved and tagged: ", svc.Parent(request.AccountID), svc.Tags(request.AccountID))
+	}
+	if request.Tags["Lob"] != "SEC" {
+		t.Fatal("Stored request was expected to record its tags")
+	}
+
+	var states []string
+	for _, transition := range request.History {
+		states = append(states, transition.State)
+	}
+	expectedStates := []string{RequestStateCreated, RequestStateStatusPolled, RequestStateOUResolved, RequestStateMoved, RequestStateTagged}
+	if !cmp.Equal(states, expectedStates) {
+		t.Fatal("Stored request history was not as expected: ", states)
+	}
+
+	//test that a failed creation is recorded
+	svc.FailAccountCreation("AWS_SEC_failed_Dev", organizations.CreateAccountFailureReasonEmailAlreadyExists)
+	requestID, error = CreateAccount(svc, "AWS_SEC_failed_Dev")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	error = RecordTransition(store, &ProvisioningRequest{RequestID: requestID, Payload: payload}, RequestStateCreated, "")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	error = CompleteProvisioning(context.Background(), svc, nil, store, requestID)
+	if error == nil {
+		t.Fatal("Provisioning was expected to fail but didn't")
+	}
+	request, _ = store.Get(requestID)
+	if request.State != RequestStateFailed || request.FailureReason != organizations.CreateAccountFailureReasonEmailAlreadyExists {
+		t.Fatal("Stored request was expected to record the failure: ", request)
+	}
+}
+
+func TestHandleEvent(t *testing.T) {
+	request := events.APIGatewayProxyRequest{
+		Resource:   "/accounts/requests/{id}",
+		HTTPMethod: "GET",
+	}
+	h := &Handler{Mode: automation.ModeLive, Org: fakeorg.NewClient(), Store: NewMemoryRequestStore()}
+	response, error := h.HandleEvent(context.Background(), Event{APIGatewayProxyRequest: request})
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if response.StatusCode != 400 {
+		t.Fatal("Status request without an id was expected to return 400, got: ", response.StatusCode)
 	}
 }
 
 func TestMain(m *testing.M) {
 	var err error
 
-	err = os.Setenv("RUNTIME_ENV", "prod")
-	if err != nil {
-		log.Panic("Issue setting env var")
-	}
-	_RUNTIME_ENV_ = os.Getenv("RUNTIME_ENV")
-
 	err = os.Setenv("EMAIL_DOMAIN", "@example.com")
 	if err != nil {
 		log.Panic("Issue setting env var")
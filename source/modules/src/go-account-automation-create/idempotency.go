@@ -0,0 +1,143 @@
+package createlambda
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+
+	"go-account-automation/internal/automation"
+)
+
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// ConflictError is returned to the caller as the JSON body of a 409 response, in the APIError envelope with the
+// account or request it conflicts with.
+type ConflictError struct {
+	automation.APIError
+	Field     string `json:"field,omitempty"`
+	AccountID string `json:"accountId,omitempty"`
+	RequestID string `json:"createAccountRequestId,omitempty"`
+}
+
+// NewConflictError returns a *ConflictError with code and message. Only a conflict with a request still being
+// processed is retryable.
+func NewConflictError(code string, message string) *ConflictError {
+	return &ConflictError{APIError: automation.APIError{StatusCode: 409, Code: code, Message: message, Retryable: code == "request_in_progress"}}
+}
+
+// IdempotencyKey returns the Idempotency-Key header of the request or, when there is none, a hash of the payload.
+func IdempotencyKey(request events.APIGatewayProxyRequest, payload automation.AccountPayload) (string, error) {
+	for header, value := range request.Headers {
+		if strings.EqualFold(header, IdempotencyKeyHeader) && value != "" {
+			return value, nil
+		}
+	}
+
+	payload.AccountID = ""
+	jsonPayload, error := json.Marshal(payload)
+	if error != nil {
+		return "", error
+	}
+	hash := sha256.Sum256(jsonPayload)
+	return "payload-" + hex.EncodeToString(hash[:]), nil
+}
+
+// FindPreviousRequest decides what to do with a request whose Idempotency-Key has already been claimed by claim.
+// It returns the earlier request when it should be replayed, a conflict when it must be rejected,
+// or neither when the earlier request never created an account and this one should be made afresh.
+// A claim left unbound for longer than IdempotencyClaimTimeout belongs to an invocation that died before it
+// created an account, so it does not hold the request up.
+func FindPreviousRequest(svc organizationsiface.OrganizationsAPI, store RequestStore, claim IdempotencyClaim, payload automation.AccountPayload) (*ProvisioningRequest, *ConflictError, error) {
+	requestID := claim.RequestID
+	if requestID == "" {
+		if time.Since(claim.ClaimedAt) > IdempotencyClaimTimeout {
+			log.Println("Previous request with the same Idempotency-Key was claimed at ", claim.ClaimedAt, " and never created an account")
+			return nil, nil, nil
+		}
+		conflict := NewConflictError("request_in_progress", "error: a request with the same Idempotency-Key is still being processed")
+		return nil, conflict, nil
+	}
+
+	previous, error := store.Get(requestID)
+	if error != nil {
+		return nil, nil, error
+	}
+
+	previousPayload := previous.Payload
+	previousPayload.AccountID, payload.AccountID = "", ""
+	if previousPayload != payload {
+		conflict := NewConflictError("idempotency_key_reused", "error: the Idempotency-Key has already been used for a different request")
+		conflict.RequestID = requestID
+		return nil, conflict, nil
+	}
+
+	status, error := DescribeProvisioningRequest(svc, requestID)
+	if error != nil {
+		return nil, nil, error
+	}
+	if status.State == organizations.CreateAccountStateFailed {
+		log.Println("Previous request with the same Idempotency-Key failed to create the account: ", status.FailureReason)
+		return nil, nil, nil
+	}
+	return previous, nil, nil
+}
+
+// FindDuplicateAccounts returns a conflict for every account in the org with the given name or email.
+func FindDuplicateAccounts(svc organizationsiface.OrganizationsAPI, accountName string, email string) ([]*ConflictError, error) {
+	var conflicts []*ConflictError
+	input := &organizations.ListAccountsInput{}
+	for {
+		accounts, error := svc.ListAccounts(input)
+		if error != nil {
+			return nil, error
+		}
+
+		for _, account := range accounts.Accounts {
+			if strings.EqualFold(aws.StringValue(account.Name), accountName) {
+				conflict := NewConflictError("duplicate_account", "error: an account named "+accountName+" already exists in the organization")
+				conflict.Field, conflict.AccountID = "name", aws.StringValue(account.Id)
+				conflicts = append(conflicts, conflict)
+			}
+			if strings.EqualFold(aws.StringValue(account.Email), email) {
+				conflict := NewConflictError("duplicate_account", "error: an account with the email "+email+" already exists in the organization")
+				conflict.Field, conflict.AccountID = "email", aws.StringValue(account.Id)
+				conflicts = append(conflicts, conflict)
+			}
+		}
+
+		if accounts.NextToken == nil {
+			return conflicts, nil
+		}
+		input.NextToken = accounts.NextToken
+	}
+}
+
+// ReleaseIdempotencyKey frees the key so the request can be retried. A failure to release it is only logged.
+func ReleaseIdempotencyKey(store RequestStore, key string) {
+	error := store.ReleaseIdempotencyKey(key)
+	if error != nil {
+		log.Println("ERROR: releasing idempotency key: ", error.Error())
+	}
+}
+
+func HandleConflict(conflict *ConflictError) (*events.APIGatewayProxyResponse, error) {
+	log.Println("CONFLICT: ", conflict.Error())
+	jsonResponseBody, error := json.Marshal(conflict)
+	if error != nil {
+		return automation.HandleErrors(error, 500)
+	}
+
+	response := &events.APIGatewayProxyResponse{
+		StatusCode: conflict.StatusCode,
+		Body:       string(jsonResponseBody),
+	}
+	return response, nil
+}
@@ -0,0 +1,142 @@
+package createlambda
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/service/organizations"
+
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/fakeorg"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	payload := automation.AccountPayload{
+		Name: "AWS_SEC_test_Dev",
+		Env:  "DEV",
+		Lob:  "SEC",
+	}
+	request := events.APIGatewayProxyRequest{Headers: map[string]string{"idempotency-key": "ticket-1234"}}
+	key, error := IdempotencyKey(request, payload)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if key != "ticket-1234" {
+		t.Fatal("Expected the Idempotency-Key header to be used, got: ", key)
+	}
+
+	//test the fallback to a hash of the payload
+	key, error = IdempotencyKey(events.APIGatewayProxyRequest{}, payload)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	sameKey, _ := IdempotencyKey(events.APIGatewayProxyRequest{}, payload)
+	payload.Lob = "IS"
+	otherKey, _ := IdempotencyKey(events.APIGatewayProxyRequest{}, payload)
+	if !strings.HasPrefix(key, "payload-") || key != sameKey || key == otherKey {
+		t.Fatal("Expected a stable hash of the payload, got: ", key, sameKey, otherKey)
+	}
+}
+
+func TestFindDuplicateAccounts(t *testing.T) {
+	existingName, existingEmail := "AWS_SEC_existing_Dev", "AWS_SEC_existing_Dev@example.com"
+	svc := fakeorg.NewClient()
+	existingID := svc.AddAccount(fakeorg.RootID, existingName, existingEmail, nil)
+
+	conflicts, error := FindDuplicateAccounts(svc, "AWS_SEC_test_Dev", "AWS_SEC_test_Dev@example.com")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if len(conflicts) != 0 {
+		t.Fatal("Expected no conflict, got: ", conflicts)
+	}
+
+	conflicts, _ = FindDuplicateAccounts(svc, "AWS_SEC_EXISTING_DEV", "other@example.com")
+	if len(conflicts) != 1 || conflicts[0].Field != "name" || conflicts[0].AccountID != existingID {
+		t.Fatal("Expected a name conflict, got: ", conflicts)
+	}
+
+	conflicts, _ = FindDuplicateAccounts(svc, "AWS_SEC_other_Dev", existingEmail)
+	if len(conflicts) != 1 || conflicts[0].Field != "email" {
+		t.Fatal("Expected an email conflict, got: ", conflicts)
+	}
+
+	//test that accounts past the first page of ListAccounts are checked
+	otherName, otherEmail := "AWS_SEC_other_Dev", "AWS_SEC_other_Dev@example.com"
+	svc = fakeorg.NewClient()
+	svc.PageSize = 1
+	otherID := svc.AddAccount(fakeorg.RootID, otherName, otherEmail, nil)
+	existingID = svc.AddAccount(fakeorg.RootID, existingName, existingEmail, nil)
+	conflicts, _ = FindDuplicateAccounts(svc, existingName, "new@example.com")
+	if len(conflicts) != 1 || conflicts[0].AccountID != existingID {
+		t.Fatal("Expected a name conflict on the second page, got: ", conflicts)
+	}
+
+	//test that a name and an email taken by different accounts are both reported
+	conflicts, _ = FindDuplicateAccounts(svc, existingName, otherEmail)
+	if len(conflicts) != 2 || conflicts[0].Field != "email" || conflicts[0].AccountID != otherID || conflicts[1].Field != "name" || conflicts[1].AccountID != existingID {
+		t.Fatal("Expected both conflicts, got: ", conflicts)
+	}
+}
+
+func TestFindPreviousRequest(t *testing.T) {
+	svc := fakeorg.NewClient()
+	svc.CreatePolls = 100
+	store := NewMemoryRequestStore()
+	payload := automation.AccountPayload{
+		Name: "AWS_SEC_test_Dev",
+		Env:  "DEV",
+		Lob:  "SEC",
+	}
+	requestID, error := CreateAccount(svc, payload.Name)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+
+	//test a claim that has not been bound to a request yet
+	_, conflict, error := FindPreviousRequest(svc, store, IdempotencyClaim{ClaimedAt: time.Now()}, payload)
+	if error != nil || conflict == nil || conflict.Code != "request_in_progress" || !conflict.Retryable {
+		t.Fatal("Expected a retryable conflict for a request still being processed: ", conflict)
+	}
+
+	//test that a claim left unbound past the timeout does not hold the request up
+	previous, conflict, error := FindPreviousRequest(svc, store, IdempotencyClaim{ClaimedAt: time.Now().Add(-IdempotencyClaimTimeout - time.Minute)}, payload)
+	if error != nil || conflict != nil || previous != nil {
+		t.Fatal("Expected a stale claim to be made afresh: ", previous, conflict, error)
+	}
+
+	error = store.Put(&ProvisioningRequest{RequestID: requestID, Payload: payload})
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	previous, conflict, error = FindPreviousRequest(svc, store, IdempotencyClaim{RequestID: requestID}, payload)
+	if error != nil || conflict != nil || previous == nil || previous.RequestID != requestID {
+		t.Fatal("Expected the previous request to be replayed")
+	}
+
+	//test a key reused with a different payload
+	otherPayload := payload
+	otherPayload.Name = "AWS_SEC_other_Dev"
+	_, conflict, _ = FindPreviousRequest(svc, store, IdempotencyClaim{RequestID: requestID}, otherPayload)
+	if conflict == nil || conflict.Code != "idempotency_key_reused" || conflict.Retryable || conflict.RequestID != requestID {
+		t.Fatal("Expected a conflict for a different payload")
+	}
+
+	//test that a failed creation can be made again
+	svc.CreatePolls = 0
+	svc.FailAccountCreation(payload.Name, organizations.CreateAccountFailureReasonEmailAlreadyExists)
+	requestID, error = CreateAccount(svc, payload.Name)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	error = store.Put(&ProvisioningRequest{RequestID: requestID, Payload: payload})
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	previous, conflict, error = FindPreviousRequest(svc, store, IdempotencyClaim{RequestID: requestID}, payload)
+	if error != nil || conflict != nil || previous != nil {
+		t.Fatal("Expected a failed request to be made again")
+	}
+}
@@ -0,0 +1,10 @@
+package createlambda
+
+import "go-account-automation/internal/automation"
+
+// LocalRoutes are the routes swagger.json sends to this Lambda.
+var LocalRoutes = []automation.LocalRoute{
+	{Method: "POST", Resource: "/accounts"},
+	{Method: "GET", Resource: "/accounts/requests/{id}"},
+	{Method: "POST", Resource: "/accounts/requests/{id}/resume"},
+}
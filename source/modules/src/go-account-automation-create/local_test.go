@@ -0,0 +1,92 @@
+package createlambda
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/organizations"
+
+	"go-account-automation/internal/automation"
+)
+
+func TestLocalRequest(t *testing.T) {
+	httpRequest := httptest.NewRequest("POST", "/v1/accounts/requests/car-012345678912/resume?dryRun=true", bytes.NewBufferString(`{}`))
+	httpRequest.Header.Set(IdempotencyKeyHeader, "request-1")
+	httpRequest.Header.Set(automation.LocalCallerLobHeader, "SEC")
+
+	request, status := automation.LocalRequest(httpRequest, LocalRoutes)
+	if status != http.StatusOK {
+		t.Fatal("Unexpected status: ", status)
+	}
+	if request.Resource != "/accounts/requests/{id}/resume" || request.PathParameters["id"] != "car-012345678912" || request.Body != `{}` {
+		t.Fatal("Request was not matched to its route: ", request.Resource, request.PathParameters)
+	}
+	if request.QueryStringParameters["dryRun"] != "true" || request.Headers[IdempotencyKeyHeader] != "request-1" || request.RequestContext.Authorizer["lob"] != "SEC" {
+		t.Fatal("Query string, headers or caller lob were not passed on: ", request)
+	}
+
+	testCases := []struct {
+		method         string
+		target         string
+		expectedStatus int
+	}{
+		{"PUT", "/v1/accounts", http.StatusMethodNotAllowed},
+		{"GET", "/v1/accounts/requests", http.StatusNotFound},
+		{"POST", "/accounts", http.StatusNotFound},
+		{"GET", "/v1/accounts/requests/", http.StatusNotFound},
+	}
+	for _, testCase := range testCases {
+		_, status = automation.LocalRequest(httptest.NewRequest(testCase.method, testCase.target, nil), LocalRoutes)
+		if status != testCase.expectedStatus {
+			t.Fatal("Unexpected status for ", testCase.method, " ", testCase.target, ": ", status)
+		}
+	}
+}
+
+func TestLocalServer(t *testing.T) {
+	defer os.Setenv("AUTO_CREATE_OU_PARENTS", os.Getenv("AUTO_CREATE_OU_PARENTS"))
+	os.Setenv("AUTO_CREATE_OU_PARENTS", `["ou-abcd-01234567"]`)
+
+	h, _ := simulatedHandler(t)
+	server := httptest.NewServer(automation.NewLocalServer(automation.LocalLambda{Name: "create", Handle: h.HandleProxyRequest, Routes: LocalRoutes}))
+	defer server.Close()
+
+	body, _ := json.Marshal(preflightPayload())
+	response, error := http.Post(server.URL+"/v1/accounts", "application/json", bytes.NewBuffer(body))
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	responseBody, _ := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if response.StatusCode != 202 {
+		t.Fatal("Unexpected response: ", response.StatusCode, string(responseBody))
+	}
+	var accepted CreateAccountResponse
+	json.Unmarshal(responseBody, &accepted)
+
+	response, error = http.Get(server.URL + "/v1/accounts/requests/" + accepted.RequestID)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	responseBody, _ = ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	var status ProvisioningStatus
+	json.Unmarshal(responseBody, &status)
+	if response.StatusCode != 200 || status.State != organizations.CreateAccountStateSucceeded {
+		t.Fatal("Unexpected status response: ", response.StatusCode, string(responseBody))
+	}
+
+	response, error = http.Get(server.URL + "/v1/accounts")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatal("A route served by another Lambda was expected to be refused: ", response.StatusCode)
+	}
+}
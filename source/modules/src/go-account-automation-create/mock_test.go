@@ -0,0 +1,86 @@
+package createlambda
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	lambdasvc "github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+)
+
+type mockLambdaClient struct {
+	lambdaiface.LambdaAPI
+	invokeErr   error
+	invokeInput *lambdasvc.InvokeInput
+}
+
+func (m *mockLambdaClient) Invoke(input *lambdasvc.InvokeInput) (*lambdasvc.InvokeOutput, error) {
+	m.invokeInput = input
+	output := &lambdasvc.InvokeOutput{}
+	return output, m.invokeErr
+}
+
+type mockDynamoDBClient struct {
+	dynamodbiface.DynamoDBAPI
+	dynamoErr error
+	items     map[string]map[string]*dynamodb.AttributeValue
+	lastPut   *dynamodb.PutItemInput
+}
+
+func (m *mockDynamoDBClient) GetItem(input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+	output := &dynamodb.GetItemOutput{Item: m.items[*input.Key["RequestId"].S]}
+	return output, m.dynamoErr
+}
+
+func (m *mockDynamoDBClient) PutItem(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+	if m.items == nil {
+		m.items = map[string]map[string]*dynamodb.AttributeValue{}
+	}
+	m.lastPut = input
+	id := *input.Item["RequestId"].S
+	if item, ok := m.items[id]; ok && input.ConditionExpression != nil {
+		// a reclaim only replaces the claim it read
+		claimedAt, reclaim := input.ExpressionAttributeValues[":claimedAt"]
+		if !reclaim || *item["ClaimedAt"].S != *claimedAt.S {
+			return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil)
+		}
+	} else if input.ConditionExpression != nil && strings.Contains(*input.ConditionExpression, ":claimedAt") {
+		return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil)
+	}
+	m.items[id] = input.Item
+	output := &dynamodb.PutItemOutput{}
+	return output, m.dynamoErr
+}
+
+func (m *mockDynamoDBClient) DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+	delete(m.items, *input.Key["RequestId"].S)
+	output := &dynamodb.DeleteItemOutput{}
+	return output, m.dynamoErr
+}
+
+// failingRequestStore fails the next putFailures Puts, as a DynamoDB outage would.
+type failingRequestStore struct {
+	*MemoryRequestStore
+	putFailures int
+}
+
+func (s *failingRequestStore) Put(request *ProvisioningRequest) error {
+	if s.putFailures > 0 {
+		s.putFailures--
+		return errors.New("injected failure")
+	}
+	return s.MemoryRequestStore.Put(request)
+}
+
+// racingRequestStore lets another retry of the same request reclaim an idempotency key just before the handler does.
+type racingRequestStore struct {
+	*MemoryRequestStore
+}
+
+func (s *racingRequestStore) ReclaimIdempotencyKey(key string, previous IdempotencyClaim) (IdempotencyClaim, error) {
+	s.MemoryRequestStore.ReclaimIdempotencyKey(key, previous)
+	return s.MemoryRequestStore.ReclaimIdempotencyKey(key, previous)
+}
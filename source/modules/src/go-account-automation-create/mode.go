@@ -0,0 +1,48 @@
+package createlambda
+
+import (
+	"log"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	lambdasvc "github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+
+	"go-account-automation/internal/automation"
+)
+
+// Handler serves this Lambda's events with the clients of its Mode. Everything it calls is given these clients,
+// so nothing below the Handler needs to know which mode it is running in. Tree caches the OUs of the Org's
+// organization; a Handler without one looks every OU up.
+type Handler struct {
+	Mode   automation.Mode
+	Org    organizationsiface.OrganizationsAPI
+	Tree   *automation.OrgTree
+	Store  RequestStore
+	Lambda lambdaiface.LambdaAPI
+}
+
+// NewHandler sets up the clients for mode around org, the Organizations client of orgclient.New. Dry runs get no
+// Lambda client, and simulated handlers keep their requests in memory and run the follow-up inline rather than
+// invoking the Lambda.
+func NewHandler(mode automation.Mode, org organizationsiface.OrganizationsAPI) *Handler {
+	h := &Handler{Mode: mode, Org: org, Tree: automation.NewOrgTree(automation.RetrieveOrgTreeTTL()), Store: NewMemoryRequestStore()}
+	if mode == automation.ModeSimulated {
+		log.Println("Simulated mode, setting up in-memory request store...")
+		h.Lambda = &inlineLambdaClient{handler: h}
+		return h
+	}
+
+	log.Println("Setting up session, request store and Lambda client...")
+	sess := session.Must(session.NewSession())
+	// an operator's command can run without the request table, keeping its request in memory
+	if table := os.Getenv("REQUEST_TABLE"); table != "" {
+		h.Store = NewDynamoDBRequestStore(dynamodb.New(sess), table)
+	}
+	if mode != automation.ModeDryRun {
+		h.Lambda = lambdasvc.New(sess)
+	}
+	return h
+}
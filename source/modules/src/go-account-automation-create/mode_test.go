@@ -0,0 +1,139 @@
+package createlambda
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/organizations"
+
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/fakeorg"
+	"go-account-automation/internal/automation/orgclient"
+)
+
+// simulatedHandler returns a simulated Handler and the organization it runs against.
+func simulatedHandler(t *testing.T) (*Handler, *fakeorg.Client) {
+	org, error := orgclient.NewSimulated()
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	return NewHandler(automation.ModeSimulated, org), org
+}
+
+func TestSimulatedHandler(t *testing.T) {
+	defer os.Setenv("AUTO_CREATE_OU_PARENTS", os.Getenv("AUTO_CREATE_OU_PARENTS"))
+	os.Setenv("AUTO_CREATE_OU_PARENTS", `["ou-abcd-01234567"]`)
+
+	h, org := simulatedHandler(t)
+	payload := preflightPayload()
+	body, _ := json.Marshal(payload)
+	request := events.APIGatewayProxyRequest{Resource: "/accounts", HTTPMethod: "POST", Body: string(body)}
+
+	response, _ := h.HandleEvent(context.Background(), Event{APIGatewayProxyRequest: request})
+	if response.StatusCode != 202 {
+		t.Fatal("Unexpected response: ", response.StatusCode, response.Body)
+	}
+	var accepted CreateAccountResponse
+	error := json.Unmarshal([]byte(response.Body), &accepted)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+
+	//test that the follow-up ran inline and placed and tagged the account in the simulated organization
+	statusRequest := events.APIGatewayProxyRequest{
+		Resource:       "/accounts/requests/{id}",
+		HTTPMethod:     "GET",
+		PathParameters: map[string]string{"id": accepted.RequestID},
+	}
+	response, _ = h.HandleEvent(context.Background(), Event{APIGatewayProxyRequest: statusRequest})
+	var status ProvisioningStatus
+	error = json.Unmarshal([]byte(response.Body), &status)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if status.State != organizations.CreateAccountStateSucceeded || status.Request == nil || status.Request.State != RequestStateTagged {
+		t.Fatal("Simulated request was not completed: ", response.Body)
+	}
+	if org.Parent(status.AccountID) != status.Request.OUID || org.Tags(status.AccountID)["Lob"] != "APP" {
+		t.Fatal("Simulated account was not moved and tagged: ", org.Parent(status.AccountID), org.Tags(status.AccountID))
+	}
+	if org.Parent(org.Parent(status.AccountID)) == "" || org.Parent(org.Parent(org.Parent(status.AccountID))) != "ou-abcd-01234567" {
+		t.Fatal("Simulated account was not placed under the configured Workload OU")
+	}
+
+	//test that the account now exists in the simulated organization
+	response, _ = h.HandleEvent(context.Background(), Event{APIGatewayProxyRequest: events.APIGatewayProxyRequest{Body: string(body), Headers: map[string]string{IdempotencyKeyHeader: "another-request"}}})
+	if response.StatusCode != 409 {
+		t.Fatal("A second account with the same name was expected to conflict: ", response.StatusCode, response.Body)
+	}
+}
+
+func TestOrganizationsErrorResponses(t *testing.T) {
+	defer os.Setenv("AUTO_CREATE_OU_PARENTS", os.Getenv("AUTO_CREATE_OU_PARENTS"))
+	os.Setenv("AUTO_CREATE_OU_PARENTS", `["ou-abcd-01234567"]`)
+
+	testCases := []struct {
+		name              string
+		fault             error
+		expectedStatus    int
+		expectedCode      string
+		expectedRetryable bool
+	}{
+		{"account limit", &organizations.ConstraintViolationException{Message_: aws.String("too many accounts"), Reason: aws.String(organizations.ConstraintViolationExceptionReasonAccountNumberLimitExceeded)}, 409, "constraint_violation", false},
+		{"throttled", awserr.New(organizations.ErrCodeTooManyRequestsException, "slow down", nil), 429, "too_many_requests", true},
+		{"unavailable", awserr.New(organizations.ErrCodeServiceException, "down", nil), 503, "service_unavailable", true},
+	}
+	h, org := simulatedHandler(t)
+	body, _ := json.Marshal(preflightPayload())
+	request := events.APIGatewayProxyRequest{Resource: "/accounts", HTTPMethod: "POST", Body: string(body)}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			org.Fault("CreateAccount", testCase.fault)
+			response, _ := h.HandleEvent(context.Background(), Event{APIGatewayProxyRequest: request})
+			var apiError automation.APIError
+			error := json.Unmarshal([]byte(response.Body), &apiError)
+			if error != nil {
+				t.Fatal(error.Error())
+			}
+			if response.StatusCode != testCase.expectedStatus || apiError.Code != testCase.expectedCode || apiError.Retryable != testCase.expectedRetryable {
+				t.Fatal("Unexpected response: ", response.StatusCode, response.Body)
+			}
+		})
+	}
+
+	//test that a malformed body is the caller's fault
+	response, _ := h.HandleEvent(context.Background(), Event{APIGatewayProxyRequest: events.APIGatewayProxyRequest{Resource: "/accounts", HTTPMethod: "POST", Body: `{"name":`}})
+	if response.StatusCode != 400 {
+		t.Fatal("Unexpected response: ", response.StatusCode, response.Body)
+	}
+}
+
+func TestDryRunModeHandler(t *testing.T) {
+	defer os.Setenv("WORKLOAD_OU", os.Getenv("WORKLOAD_OU"))
+	os.Setenv("WORKLOAD_OU", "ou-abcd-workload")
+
+	svc := placementOrg()
+	h := &Handler{Mode: automation.ModeDryRun, Org: automation.ReadOnlyClient{OrganizationsAPI: svc}, Store: NewMemoryRequestStore()}
+
+	payload := preflightPayload()
+	body, _ := json.Marshal(payload)
+	response, _ := h.HandleEvent(context.Background(), Event{APIGatewayProxyRequest: events.APIGatewayProxyRequest{Body: string(body)}})
+	if response.StatusCode != 200 {
+		t.Fatal("Unexpected response: ", response.StatusCode, response.Body)
+	}
+	if svc.Calls("CreateAccount") != 0 {
+		t.Fatal("A dry-run handler was not expected to create the account")
+	}
+
+	request := events.APIGatewayProxyRequest{Resource: "/accounts/requests/{id}/resume", PathParameters: map[string]string{"id": "car-012345678912"}}
+	response, _ = h.HandleEvent(context.Background(), Event{APIGatewayProxyRequest: request})
+	if response.StatusCode != 400 {
+		t.Fatal("A dry-run handler was not expected to resume requests: ", response.StatusCode, response.Body)
+	}
+}
@@ -0,0 +1,67 @@
+package createlambda
+
+import (
+	"encoding/json"
+	"strings"
+
+	"go-account-automation/internal/automation"
+)
+
+// CreateRequest is the body of POST /accounts: the account payload, whose name may be left out for this Lambda to
+// generate from AppName, lob and env with the account naming convention.
+type CreateRequest struct {
+	automation.AccountPayload
+	AppName string `json:"appName,omitempty"`
+}
+
+func ProcessCreateRequest(body string) (CreateRequest, error) {
+	var request CreateRequest
+	error := json.Unmarshal([]byte(body), &request)
+	return request, error
+}
+
+// GenerateAccountName returns the payload of request, with the name generated from its AppName, Lob and Env when it
+// has none. The env is written as it is in automation.Envs, whatever its case in the payload, and the prefix is the
+// convention's default, AWS unless ACCOUNT_NAMING_CONVENTION gives another. A name given along with AppName must be the one the convention generates for them, or a
+// *ValidationError is returned. A name that cannot be generated is returned as a *NamingError.
+func GenerateAccountName(request CreateRequest) (automation.AccountPayload, error) {
+	payload := request.AccountPayload
+	if request.AppName == "" {
+		return payload, nil
+	}
+
+	convention, error := automation.LoadNamingConvention()
+	if error != nil {
+		return automation.AccountPayload{}, error
+	}
+	name, error := convention.Generate(map[string]string{"app": request.AppName, "lob": payload.Lob, "env": automation.CanonicalEnv(payload.Env)})
+	if error != nil {
+		return automation.AccountPayload{}, error
+	}
+	if payload.Name != "" && !strings.EqualFold(payload.Name, name) {
+		error = automation.NewValidationError([]automation.FieldError{{
+			Field:   "name",
+			Code:    "mismatch",
+			Message: "error: name " + payload.Name + " differs from " + name + ", the name generated from appName, lob and env",
+		}})
+		return automation.AccountPayload{}, error
+	}
+	if payload.Name == "" {
+		payload.Name = name
+	}
+	return payload, nil
+}
+
+// ReadCreatePayload reads a CreateRequest from file, or from stdin when file is "-", and returns its payload with
+// the name generated as GenerateAccountName does.
+func ReadCreatePayload(file string) (automation.AccountPayload, error) {
+	body, error := automation.ReadCommandFile(file)
+	if error != nil {
+		return automation.AccountPayload{}, error
+	}
+	request, error := ProcessCreateRequest(body)
+	if error != nil {
+		return automation.AccountPayload{}, error
+	}
+	return GenerateAccountName(request)
+}
@@ -0,0 +1,90 @@
+package createlambda
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+
+	"go-account-automation/internal/automation"
+)
+
+func TestGenerateAccountName(t *testing.T) {
+	payload := preflightPayload()
+	payload.Name = ""
+	generated, error := GenerateAccountName(CreateRequest{AccountPayload: payload, AppName: "billing"})
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if generated.Name != "AWS_APP_billing_Dev" {
+		t.Fatal("Unexpected account name: ", generated.Name)
+	}
+
+	//test that the canonical name is generated, with the AWS prefix and the env written as in automation.Envs
+	generated, error = GenerateAccountName(CreateRequest{AccountPayload: automation.AccountPayload{Lob: "SEC", Env: "DEV"}, AppName: "myapp"})
+	if error != nil || generated.Name != "AWS_SEC_myapp_Dev" {
+		t.Fatal("Unexpected account name: ", generated.Name, error)
+	}
+
+	//test that a name given without appName is left as it is
+	generated, error = GenerateAccountName(CreateRequest{AccountPayload: preflightPayload()})
+	if error != nil || generated.Name != "AWS_APP_test_Dev" {
+		t.Fatal("Account name was not expected to change: ", generated.Name, error)
+	}
+
+	//test that a name given with appName must be the generated one
+	_, error = GenerateAccountName(CreateRequest{AccountPayload: preflightPayload(), AppName: "test"})
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	_, error = GenerateAccountName(CreateRequest{AccountPayload: preflightPayload(), AppName: "billing"})
+	if validationError, ok := error.(*automation.ValidationError); !ok || validationError.Problems[0].Code != "mismatch" {
+		t.Fatal("A name differing from the generated one was expected to fail: ", error)
+	}
+
+	//test that an appName the convention cannot use is reported as a naming error
+	_, error = GenerateAccountName(CreateRequest{AccountPayload: payload, AppName: "my_app"})
+	if namingError, ok := error.(*automation.NamingError); !ok || namingError.Segment != "app" {
+		t.Fatal("Unexpected naming error: ", error)
+	}
+
+	//test that the configured convention is used
+	defer os.Setenv("ACCOUNT_NAMING_CONVENTION", os.Getenv("ACCOUNT_NAMING_CONVENTION"))
+	os.Setenv("ACCOUNT_NAMING_CONVENTION", `pattern: "acme-{lob}-{env}-{app}"`)
+	generated, error = GenerateAccountName(CreateRequest{AccountPayload: payload, AppName: "billing"})
+	if error != nil || generated.Name != "acme-APP-Dev-billing" {
+		t.Fatal("Account name was not generated with the configured convention: ", generated.Name, error)
+	}
+}
+
+func TestHandleRequestWithAppName(t *testing.T) {
+	defer os.Setenv("AUTO_CREATE_OU_PARENTS", os.Getenv("AUTO_CREATE_OU_PARENTS"))
+	os.Setenv("AUTO_CREATE_OU_PARENTS", `["ou-abcd-01234567"]`)
+
+	h, _ := simulatedHandler(t)
+	payload := preflightPayload()
+	payload.Name = ""
+	body, _ := json.Marshal(CreateRequest{AccountPayload: payload, AppName: "billing"})
+	response, _ := h.HandleEvent(context.Background(), Event{APIGatewayProxyRequest: events.APIGatewayProxyRequest{Resource: "/accounts", HTTPMethod: "POST", Body: string(body)}})
+	if response.StatusCode != 202 {
+		t.Fatal("Unexpected response: ", response.StatusCode, response.Body)
+	}
+	var accepted CreateAccountResponse
+	error := json.Unmarshal([]byte(response.Body), &accepted)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if accepted.Name != "AWS_APP_billing_Dev" {
+		t.Fatal("Generated account name was not returned: ", response.Body)
+	}
+
+	//test that an appName the convention cannot use is rejected with the naming error
+	body, _ = json.Marshal(CreateRequest{AccountPayload: payload, AppName: "my_app"})
+	response, _ = h.HandleEvent(context.Background(), Event{APIGatewayProxyRequest: events.APIGatewayProxyRequest{Resource: "/accounts", HTTPMethod: "POST", Body: string(body)}})
+	var namingError automation.NamingError
+	if response.StatusCode != 400 || json.Unmarshal([]byte(response.Body), &namingError) != nil || namingError.Code != "invalid_segment" {
+		t.Fatal("Unexpected response: ", response.StatusCode, response.Body)
+	}
+}
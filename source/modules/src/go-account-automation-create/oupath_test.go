@@ -0,0 +1,115 @@
+package createlambda
+
+import (
+	"os"
+	"testing"
+
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/fakeorg"
+)
+
+func TestResolveOUPath(t *testing.T) {
+	testCases := []struct {
+		path       string
+		expectedOU string
+	}{
+		{"/Workloads/Dev/APP", "ou-abcd-wkdevapp"},
+		{"workloads/DEV/app/", "ou-abcd-wkdevapp"},
+		{"/Security", "ou-abcd-security"},
+		{"ou-abcd-workload/Prod/Finance", "ou-abcd-wkprodfin"},
+		{"/ou-abcd-sandbox", "ou-abcd-sandbox"},
+		{"/", fakeorg.RootID},
+		{"/Workloads/Lab", ""},
+		{"/Workloads/Dev/APP/Team", ""},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.path, func(t *testing.T) {
+			root, ou, error := automation.ResolveOUPath(placementOrg(), nil, testCase.path)
+			if error != nil {
+				t.Fatal(error.Error())
+			}
+			if root != fakeorg.RootID || ou != testCase.expectedOU {
+				t.Fatal("Unexpected OU: ", root, ou)
+			}
+		})
+	}
+}
+
+func TestResolveOURef(t *testing.T) {
+	svc := placementOrg()
+	ou, error := automation.ResolveOURef(svc, nil, "ou-abcd-01234567")
+	if error != nil || ou != "ou-abcd-01234567" {
+		t.Fatal("An OU ID was expected to be used as it is: ", ou, error)
+	}
+	ou, error = automation.ResolveOURef(svc, nil, "/Security")
+	if error != nil || ou != "ou-abcd-security" {
+		t.Fatal("An OU path was not resolved: ", ou, error)
+	}
+	for _, ref := range []string{"", "/Missing"} {
+		_, error = automation.ResolveOURef(svc, nil, ref)
+		if error == nil {
+			t.Fatal("OU ref was expected to fail but didn't: ", ref)
+		}
+	}
+}
+
+func TestRetrieveWorkloadOUByPath(t *testing.T) {
+	for variable, value := range map[string]string{
+		"WORKLOAD_OU":      "",
+		"WORKLOAD_OU_PATH": "/Workloads",
+		"SEC_OU":           "",
+		"SEC_OU_PATHS":     `{"sec":"/Security"}`,
+	} {
+		defer os.Setenv(variable, os.Getenv(variable))
+		error := os.Setenv(variable, value)
+		if error != nil {
+			t.Fatal(error.Error())
+		}
+	}
+	svc := placementOrg()
+
+	_, ou, error := automation.RetrieveWorkloadOU(svc, nil, automation.AccountPayload{Lob: "app", Env: "dev"}, nil)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if ou != "ou-abcd-wkdevapp" {
+		t.Fatal("Workload OU was not found by path: ", ou)
+	}
+
+	_, ou, error = automation.RetrieveWorkloadOU(svc, nil, automation.AccountPayload{Lob: "SEC", Env: "Dev"}, nil)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if ou != "ou-abcd-secdev" {
+		t.Fatal("Security OU was not found by path: ", ou)
+	}
+
+	//test that the configured IDs override the paths
+	os.Setenv("WORKLOAD_OU", "ou-abcd-sandbox")
+	os.Setenv("SEC_OU", `{"SEC":"ou-abcd-workload"}`)
+	infraSecOUs, error := automation.RetrieveInfraSecOUs()
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if len(infraSecOUs) != 1 || infraSecOUs["SEC"] != "ou-abcd-workload" {
+		t.Fatal("SEC_OU did not override SEC_OU_PATHS: ", infraSecOUs)
+	}
+	_, ou, error = automation.RetrieveWorkloadOU(svc, nil, automation.AccountPayload{Lob: "SEC", Env: "Prod"}, nil)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if ou != "ou-abcd-wkprod" {
+		t.Fatal("Security OU ID did not override its path: ", ou)
+	}
+	if automation.RetrieveWorkloadOURef() != "ou-abcd-sandbox" {
+		t.Fatal("WORKLOAD_OU did not override WORKLOAD_OU_PATH")
+	}
+
+	//test that a configured path that does not exist is an error
+	os.Setenv("WORKLOAD_OU", "")
+	os.Setenv("WORKLOAD_OU_PATH", "/Missing")
+	_, _, error = automation.RetrieveWorkloadOU(svc, nil, automation.AccountPayload{Lob: "APP", Env: "Dev"}, nil)
+	if error == nil {
+		t.Fatal("A missing workload OU path was expected to fail but didn't")
+	}
+}
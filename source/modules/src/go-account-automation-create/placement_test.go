@@ -0,0 +1,115 @@
+package createlambda
+
+import (
+	"os"
+	"testing"
+
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/fakeorg"
+)
+
+const testPlacementRules = `
+rules:
+  - name: security
+    match:
+      lob: [SEC, IS]
+    path: Security/{env}
+  - name: finance
+    match:
+      costCenter: "99999"
+      env: Prod
+    path: Workloads/{env}/Finance
+  - name: sandbox
+    match:
+      env: Lab
+    path: Sandbox/{lob}
+default:
+  path: Workloads/{env}/{lob}
+`
+
+// placementOrg is an organization with the OUs
+// Security/Dev, Sandbox/APP, Workloads/Dev/APP and Workloads/Prod/Finance.
+func placementOrg() *fakeorg.Client {
+	svc := fakeorg.NewClient()
+	svc.AddOU(fakeorg.RootID, "ou-abcd-security", "Security")
+	svc.AddOU(fakeorg.RootID, "ou-abcd-sandbox", "Sandbox")
+	svc.AddOU(fakeorg.RootID, "ou-abcd-workload", "Workloads")
+	svc.AddOU("ou-abcd-security", "ou-abcd-secdev", "Dev")
+	svc.AddOU("ou-abcd-sandbox", "ou-abcd-sbapp", "APP")
+	svc.AddOU("ou-abcd-workload", "ou-abcd-wkdev", "Dev")
+	svc.AddOU("ou-abcd-workload", "ou-abcd-wkprod", "Prod")
+	svc.AddOU("ou-abcd-wkdev", "ou-abcd-wkdevapp", "APP")
+	svc.AddOU("ou-abcd-wkprod", "ou-abcd-wkprodfin", "Finance")
+	return svc
+}
+
+func TestPlaceAccount(t *testing.T) {
+	rules, error := automation.ParsePlacementRules([]byte(testPlacementRules))
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+
+	testCases := []struct {
+		name         string
+		payload      automation.AccountPayload
+		expectedRule string
+		expectedPath string
+		expectedOU   string
+	}{
+		{"security lob", automation.AccountPayload{Lob: "SEC", Env: "DEV"}, "security", "Security/DEV", "ou-abcd-secdev"},
+		{"second security lob", automation.AccountPayload{Lob: "is", Env: "Dev"}, "security", "Security/Dev", "ou-abcd-secdev"},
+		{"all of a rule's matches", automation.AccountPayload{Lob: "APP", Env: "Prod", CostCenter: "99999"}, "finance", "Workloads/Prod/Finance", "ou-abcd-wkprodfin"},
+		{"some of a rule's matches", automation.AccountPayload{Lob: "APP", Env: "Dev", CostCenter: "99999"}, "default", "Workloads/Dev/APP", "ou-abcd-wkdevapp"},
+		{"sandbox env", automation.AccountPayload{Lob: "APP", Env: "LAB"}, "sandbox", "Sandbox/APP", "ou-abcd-sbapp"},
+		{"earlier rule wins", automation.AccountPayload{Lob: "SEC", Env: "Lab"}, "security", "Security/Lab", ""},
+		{"default rule", automation.AccountPayload{Lob: "APP", Env: "Dev"}, "default", "Workloads/Dev/APP", "ou-abcd-wkdevapp"},
+		{"missing OU", automation.AccountPayload{Lob: "OPS", Env: "Dev"}, "default", "Workloads/Dev/OPS", ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			rule, path, error := rules.Evaluate(automation.PlacementAttributes(testCase.payload))
+			if error != nil {
+				t.Fatal(error.Error())
+			}
+			if rule.Name != testCase.expectedRule || path != testCase.expectedPath {
+				t.Fatal("Unexpected placement: ", rule.Name, path)
+			}
+
+			root, ou, error := automation.PlaceAccount(placementOrg(), nil, rules, testCase.payload, nil)
+			if error != nil {
+				t.Fatal(error.Error())
+			}
+			if root != fakeorg.RootID || ou != testCase.expectedOU {
+				t.Fatal("Unexpected OU: ", root, ou)
+			}
+		})
+	}
+
+	//test that a placeholder the request does not fill is an error
+	_, _, error = rules.Evaluate(automation.PlacementAttributes(automation.AccountPayload{Env: "Dev"}))
+	if error == nil {
+		t.Fatal("Placement without a lob was expected to fail but didn't")
+	}
+}
+
+func TestRetrieveOUsWithPlacementRules(t *testing.T) {
+	error := os.Setenv("PLACEMENT_RULES", testPlacementRules)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	defer os.Unsetenv("PLACEMENT_RULES")
+
+	root, ou, error := RetrieveOUs(placementOrg(), nil, automation.AccountPayload{Lob: "APP", Env: "Lab"})
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if root != fakeorg.RootID || ou != "ou-abcd-sbapp" {
+		t.Fatal("RetrieveOUs did not follow the placement rules: ", root, ou)
+	}
+
+	_, _, error = RetrieveOUs(placementOrg(), nil, automation.AccountPayload{Lob: "OPS", Env: "Dev"})
+	if error == nil {
+		t.Fatal("RetrieveOUs was expected to fail for a missing OU but didn't")
+	}
+}
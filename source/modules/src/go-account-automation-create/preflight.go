@@ -0,0 +1,271 @@
+package createlambda
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+
+	"go-account-automation/internal/automation"
+)
+
+// Limits Organizations puts on the values a new account is created and tagged with.
+const (
+	maxAccountNameLength = 50
+	maxEmailLength       = 64
+	maxTagValueLength    = 256
+)
+
+var emailPattern = regexp.MustCompile(`^[^\s@]+@[^\s@]+\.[^\s@]+$`)
+var tagValuePattern = regexp.MustCompile(`^[\p{L}\p{Z}\p{N}_.:/=+\-@]*$`)
+
+// PreflightProblem is one problem Preflight found with a request.
+type PreflightProblem struct {
+	Field     string `json:"field,omitempty"`
+	Message   string `json:"message"`
+	AccountID string `json:"accountId,omitempty"`
+	// Code says what the problem is: how the field is not valid, see automation.FieldError, invalid_configuration,
+	// duplicate_account or ou_not_found. Segment is the segment of the naming convention an account name breaks.
+	Code    string `json:"code,omitempty"`
+	Segment string `json:"segment,omitempty"`
+	// StatusCode is the status the problem would be reported with on its own:
+	// 500 for configuration, 409 for an existing account and 400 for the request itself.
+	StatusCode int `json:"-"`
+}
+
+// PreflightError reports every problem Preflight found as the JSON body of a single response, in the APIError
+// envelope with the problems under it.
+type PreflightError struct {
+	automation.APIError
+	Problems []PreflightProblem `json:"problems"`
+}
+
+// preflightCodes are the codes a PreflightError is reported with, by the status of its most severe problem.
+var preflightCodes = map[int]string{
+	400: "invalid_request",
+	409: "duplicate_account",
+	500: "invalid_configuration",
+}
+
+func (e *PreflightError) Error() string {
+	messages := []string{}
+	for _, problem := range e.Problems {
+		messages = append(messages, problem.Message)
+	}
+	return e.Message + ": " + strings.Join(messages, "; ")
+}
+
+// NewPreflightError returns a *PreflightError for problems, reported with the most severe status among them, so a
+// broken configuration is never reported as the caller's fault.
+func NewPreflightError(problems []PreflightProblem) *PreflightError {
+	statusCode := 400
+	for _, problem := range problems {
+		if problem.StatusCode > statusCode {
+			statusCode = problem.StatusCode
+		}
+	}
+	apiError := automation.APIError{StatusCode: statusCode, Code: preflightCodes[statusCode], Message: "error: the request failed pre-flight checks"}
+	return &PreflightError{APIError: apiError, Problems: problems}
+}
+
+// Preflight checks everything about a request that can be checked before CreateAccount is called: the configuration,
+// the account name and email, the tags and the destination OU, and that no account has the same name or email.
+// It returns the plan for the request when it can go ahead, and otherwise every problem it found. No OUs are created.
+func Preflight(svc organizationsiface.OrganizationsAPI, tree *automation.OrgTree, payload automation.AccountPayload) (*automation.Plan, *PreflightError, error) {
+	configuration := PreflightConfiguration()
+	problems := append(configuration, PreflightPayload(payload)...)
+
+	log.Println("Checking organization for an existing account with the same name or email...")
+	conflicts, error := FindDuplicateAccounts(svc, payload.Name, AccountEmail(payload.Name))
+	if error != nil {
+		return nil, nil, error
+	}
+	for _, conflict := range conflicts {
+		problems = append(problems, PreflightProblem{Field: conflict.Field, Message: conflict.Message, AccountID: conflict.AccountID, Code: conflict.Code, StatusCode: 409})
+	}
+
+	// the destination can only be resolved with a working configuration
+	var destination *automation.Destination
+	if len(configuration) == 0 {
+		log.Println("Resolving destination OU...")
+		var problem *PreflightProblem
+		destination, problem, error = PreflightDestinationOU(svc, tree, payload)
+		if error != nil {
+			return nil, nil, error
+		}
+		if problem != nil {
+			problems = append(problems, *problem)
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, NewPreflightError(problems), nil
+	}
+	plan := &automation.Plan{
+		Name:          payload.Name,
+		Email:         AccountEmail(payload.Name),
+		DestinationOU: destination,
+		Tags:          automation.DiffTags(map[string]string{}, nil, automation.TagMap(automation.GenerateTags(payload))),
+	}
+	return plan, nil, nil
+}
+
+// PreflightConfiguration checks the environment variables the request will be placed with.
+func PreflightConfiguration() []PreflightProblem {
+	var problems []PreflightProblem
+	configurationProblem := func(variable string, message string) {
+		problems = append(problems, PreflightProblem{Field: variable, Message: "error: " + variable + " " + message, Code: "invalid_configuration", StatusCode: 500})
+	}
+
+	if emailDomain := os.Getenv("EMAIL_DOMAIN"); !strings.HasPrefix(emailDomain, "@") {
+		configurationProblem("EMAIL_DOMAIN", "must be set to a domain starting with @")
+	}
+	for _, variable := range []string{"SEC_OU", "SEC_OU_PATHS"} {
+		if jsonMap := os.Getenv(variable); jsonMap != "" {
+			var ous map[string]string
+			if error := json.Unmarshal([]byte(jsonMap), &ous); error != nil {
+				configurationProblem(variable, "is not a JSON map of lob to OU: "+error.Error())
+			}
+		}
+	}
+	if jsonList := os.Getenv("AUTO_CREATE_OU_PARENTS"); strings.TrimSpace(jsonList) != "" {
+		var parents []string
+		if error := json.Unmarshal([]byte(jsonList), &parents); error != nil {
+			configurationProblem("AUTO_CREATE_OU_PARENTS", "is not a JSON list of OUs: "+error.Error())
+		}
+	}
+
+	if _, error := automation.LoadNamingConvention(); error != nil {
+		configurationProblem("ACCOUNT_NAMING_CONVENTION", "is not valid: "+error.Error())
+	}
+
+	rules, error := automation.LoadPlacementRules()
+	if error != nil {
+		configurationProblem("PLACEMENT_RULES", "is not valid: "+error.Error())
+	} else if rules == nil && automation.RetrieveWorkloadOURef() == "" {
+		configurationProblem("WORKLOAD_OU", "or WORKLOAD_OU_PATH must be set when there are no placement rules")
+	}
+	return problems
+}
+
+// PreflightPayload checks every field of the payload, the email derived from the account name and the tags the
+// account will be given.
+func PreflightPayload(payload automation.AccountPayload) []PreflightProblem {
+	var problems []PreflightProblem
+	payloadProblem := func(field string, code string, message string) {
+		problems = append(problems, PreflightProblem{Field: field, Message: message, Code: code, StatusCode: 400})
+	}
+
+	error := automation.ValidatePayload(payload)
+	if validationError, ok := error.(*automation.ValidationError); ok {
+		for _, fieldError := range validationError.Problems {
+			problems = append(problems, PreflightProblem{Field: fieldError.Field, Message: fieldError.Message, Code: fieldError.Code, Segment: fieldError.Segment, StatusCode: 400})
+		}
+	} else if error != nil {
+		payloadProblem("name", "", error.Error())
+	}
+	if len(payload.Name) > maxAccountNameLength {
+		payloadProblem("name", "too_long", fmt.Sprintf("error: account name must be at most %d characters", maxAccountNameLength))
+	}
+	// a missing EMAIL_DOMAIN is reported with the configuration
+	if email := AccountEmail(payload.Name); strings.HasPrefix(os.Getenv("EMAIL_DOMAIN"), "@") && (len(email) > maxEmailLength || !emailPattern.MatchString(email)) {
+		payloadProblem("name", "invalid_email", fmt.Sprintf("error: account email %s must be a valid address of at most %d characters", email, maxEmailLength))
+	}
+
+	val := reflect.ValueOf(payload)
+	typeOfS := val.Type()
+
+	// Iterate over fields of struct
+	for i := 0; i < val.NumField(); i++ {
+		field, value := typeOfS.Field(i), fmt.Sprintf("%v", val.Field(i).Interface())
+		if strings.EqualFold(field.Name, "AccountID") {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if len(value) > maxTagValueLength {
+			payloadProblem(name, "too_long", fmt.Sprintf("error: %s must be at most %d characters to be tagged on the account", name, maxTagValueLength))
+		}
+		if !tagValuePattern.MatchString(value) {
+			payloadProblem(name, "invalid_characters", "error: "+name+" contains characters that cannot be tagged on the account")
+		}
+	}
+	return problems
+}
+
+// PreflightDestinationOU resolves the OU the account will be moved to without creating any OUs, and returns a problem
+// when it cannot be. Errors calling Organizations are returned as errors rather than problems.
+func PreflightDestinationOU(svc organizationsiface.OrganizationsAPI, tree *automation.OrgTree, payload automation.AccountPayload) (*automation.Destination, *PreflightProblem, error) {
+	problem := func(field string, error error, statusCode int) (*automation.Destination, *PreflightProblem, error) {
+		if _, ok := error.(awserr.Error); ok {
+			return nil, nil, error
+		}
+		code := "invalid_configuration"
+		if statusCode == 400 {
+			code = "ou_not_found"
+		}
+		return nil, &PreflightProblem{Field: field, Message: error.Error(), Code: code, StatusCode: statusCode}, nil
+	}
+
+	rules, error := automation.LoadPlacementRules()
+	if error != nil {
+		return problem("PLACEMENT_RULES", error, 500)
+	}
+	if rules == nil {
+		infraSecOUs, error := automation.RetrieveInfraSecOUs()
+		if error != nil {
+			return problem("SEC_OU", error, 500)
+		}
+		variable := "WORKLOAD_OU"
+		if _, ok := automation.LookupLob(infraSecOUs, payload.Lob); ok {
+			variable = "SEC_OU"
+		}
+		_, error = automation.ResolveOURef(svc, tree, automation.RetrieveParentOU(infraSecOUs, automation.RetrieveWorkloadOURef(), payload.Lob))
+		if error != nil {
+			return problem(variable, error, 500)
+		}
+	}
+
+	autoCreate, error := automation.LoadOUAutoCreation(svc, tree)
+	if error != nil {
+		return problem("AUTO_CREATE_OU_PARENTS", error, 500)
+	}
+	if autoCreate != nil {
+		autoCreate.DryRun = true
+	}
+	_, ou, error := ResolveDestinationOU(svc, tree, payload, autoCreate)
+	if error != nil {
+		return problem("ou", error, 400)
+	}
+	path, error := automation.DestinationPath(payload)
+	if error != nil {
+		return problem("ou", error, 400)
+	}
+	log.Println("Destination OU: ", ou, " at ", path)
+
+	destination := &automation.Destination{ID: ou, Path: path}
+	if autoCreate != nil {
+		destination.OUsToCreate = autoCreate.Created
+	}
+	return destination, nil, nil
+}
+
+func HandlePreflightError(preflightError *PreflightError) (*events.APIGatewayProxyResponse, error) {
+	log.Println("PREFLIGHT: ", preflightError.Error())
+	jsonResponseBody, error := json.Marshal(preflightError)
+	if error != nil {
+		return automation.HandleErrors(error, 500)
+	}
+
+	response := &events.APIGatewayProxyResponse{
+		StatusCode: preflightError.StatusCode,
+		Body:       string(jsonResponseBody),
+	}
+	return response, nil
+}
@@ -0,0 +1,136 @@
+package createlambda
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/fakeorg"
+)
+
+func preflightPayload() automation.AccountPayload {
+	return automation.AccountPayload{
+		Name:          "AWS_APP_test_Dev",
+		CostCenter:    "01234",
+		AccountPOC:    "john.doe@example.com",
+		ApplicationID: "00000000-0000-0000-0000-000000000000",
+		Env:           "Dev",
+		Lob:           "APP",
+	}
+}
+
+func problemFields(preflightError *PreflightError) []string {
+	fields := []string{}
+	for _, problem := range preflightError.Problems {
+		fields = append(fields, problem.Field)
+	}
+	return fields
+}
+
+func TestPreflight(t *testing.T) {
+	for variable, value := range map[string]string{
+		"WORKLOAD_OU": "ou-abcd-workload",
+		"SEC_OU":      `{"SEC":"ou-abcd-security"}`,
+	} {
+		defer os.Setenv(variable, os.Getenv(variable))
+		error := os.Setenv(variable, value)
+		if error != nil {
+			t.Fatal(error.Error())
+		}
+	}
+	svc := placementOrg()
+	existingID := svc.AddAccount(fakeorg.RootID, "AWS_APP_existing_Dev", "AWS_APP_existing_Dev@example.com", nil)
+
+	_, preflightError, error := Preflight(svc, nil, preflightPayload())
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if preflightError != nil {
+		t.Fatal("A valid request failed pre-flight checks: ", preflightError)
+	}
+
+	//test that every problem is reported at once, with the conflict deciding the status
+	payload := preflightPayload()
+	payload.Name, payload.Lob, payload.CostCenter = "AWS_APP_existing_Dev", "OPS", "0123<4>"
+	_, preflightError, error = Preflight(svc, nil, payload)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if preflightError == nil {
+		t.Fatal("An invalid request passed pre-flight checks")
+	}
+	if fields := problemFields(preflightError); !cmp.Equal(fields, []string{"costCenter", "lob", "costCenter", "name", "email", "ou"}) {
+		t.Fatal("Unexpected problems: ", preflightError.Problems)
+	}
+	if preflightError.Problems[0].Code != "invalid_format" || preflightError.Problems[1].Code != "mismatch" {
+		t.Fatal("Unexpected problem codes: ", preflightError.Problems)
+	}
+	if preflightError.StatusCode != 409 || preflightError.Problems[3].AccountID != existingID {
+		t.Fatal("Unexpected status or conflicting account: ", preflightError.StatusCode, preflightError.Problems[3])
+	}
+
+	//test that a name with more segments than the naming convention is reported with the segment it breaks
+	payload = preflightPayload()
+	payload.Name = "AWS_SEC_my_app_Dev"
+	_, preflightError, error = Preflight(svc, nil, payload)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if preflightError == nil || preflightError.StatusCode != 400 || preflightError.Problems[0].Code != "too_many_segments" || preflightError.Problems[0].Segment != "env" {
+		t.Fatal("Name with too many segments was expected to fail: ", preflightError)
+	}
+
+	//test that a missing OU that would be auto-created passes without being created
+	os.Setenv("AUTO_CREATE_OU_PARENTS", `["/Workloads"]`)
+	defer os.Unsetenv("AUTO_CREATE_OU_PARENTS")
+	payload = preflightPayload()
+	payload.Name, payload.Lob, payload.Env = "AWS_OPS_test_Lab", "OPS", "Lab"
+	_, preflightError, error = Preflight(svc, nil, payload)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if preflightError != nil || svc.Calls("CreateOrganizationalUnit") != 0 {
+		t.Fatal("Pre-flight checks were expected to plan the missing OUs without creating them: ", preflightError, svc.Calls("CreateOrganizationalUnit"))
+	}
+}
+
+func TestPreflightConfiguration(t *testing.T) {
+	for variable, value := range map[string]string{
+		"SEC_OU":       "not json",
+		"EMAIL_DOMAIN": "example.com",
+	} {
+		defer os.Setenv(variable, os.Getenv(variable))
+		error := os.Setenv(variable, value)
+		if error != nil {
+			t.Fatal(error.Error())
+		}
+	}
+	svc := placementOrg()
+
+	_, preflightError, error := Preflight(svc, nil, preflightPayload())
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if preflightError == nil || preflightError.StatusCode != 500 {
+		t.Fatal("A broken configuration was expected to fail with a 500: ", preflightError)
+	}
+	if fields := problemFields(preflightError); !cmp.Equal(fields, []string{"EMAIL_DOMAIN", "SEC_OU"}) {
+		t.Fatal("Unexpected problems: ", preflightError.Problems)
+	}
+	if svc.Calls("ListOrganizationalUnitsForParent") != 0 {
+		t.Fatal("The destination OU was not expected to be resolved with a broken configuration: ", svc.Calls("ListOrganizationalUnitsForParent"))
+	}
+
+	response, _ := HandlePreflightError(preflightError)
+	var body PreflightError
+	error = json.Unmarshal([]byte(response.Body), &body)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if response.StatusCode != 500 || body.Code != "invalid_configuration" || body.Retryable || len(body.Problems) != 2 || body.Problems[1].Field != "SEC_OU" {
+		t.Fatal("Unexpected response: ", response.StatusCode, response.Body)
+	}
+}
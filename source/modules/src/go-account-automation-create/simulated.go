@@ -0,0 +1,47 @@
+package createlambda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+
+	lambdasvc "github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+
+	"go-account-automation/internal/automation"
+)
+
+// RunFollowUpsInline makes h run the follow-ups it schedules in this process, for when it serves its routes without a
+// deployed Lambda to invoke. In live mode the follow-up runs in the background, as the Lambda it stands in for would.
+func (h *Handler) RunFollowUpsInline() {
+	if h.Lambda != nil {
+		h.Lambda = &inlineLambdaClient{handler: h, background: h.Mode == automation.ModeLive}
+	}
+}
+
+// inlineLambdaClient runs the follow-up a handler schedules in this process, for when there is no Lambda to invoke.
+// The follow-up is run before Invoke returns unless background is set.
+type inlineLambdaClient struct {
+	lambdaiface.LambdaAPI
+	handler    *Handler
+	background bool
+}
+
+func (c *inlineLambdaClient) Invoke(input *lambdasvc.InvokeInput) (*lambdasvc.InvokeOutput, error) {
+	var event Event
+	error := json.Unmarshal(input.Payload, &event)
+	if error != nil {
+		return nil, error
+	}
+	if event.FollowUp == nil {
+		return nil, errors.New("error: the follow-up is the only event that can be run inline")
+	}
+	if c.background {
+		log.Println("Running the follow-up in the background instead of invoking the Lambda")
+		go c.handler.HandleFollowUp(context.Background(), *event.FollowUp)
+		return &lambdasvc.InvokeOutput{}, nil
+	}
+	log.Println("Running the follow-up inline instead of invoking the Lambda")
+	return &lambdasvc.InvokeOutput{}, c.handler.HandleFollowUp(context.Background(), *event.FollowUp)
+}
@@ -0,0 +1,337 @@
+package createlambda
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"go-account-automation/internal/automation"
+)
+
+// States recorded for a provisioning request, in the order HandleRequest and CompleteProvisioning reach them.
+const (
+	RequestStateCreated      = "CREATED"
+	RequestStateStatusPolled = "STATUS_POLLED"
+	RequestStateOUResolved   = "OU_RESOLVED"
+	RequestStateMoved        = "MOVED"
+	RequestStateTagged       = "TAGGED"
+	RequestStateFailed       = "FAILED"
+)
+
+var ErrRequestNotFound = errors.New("error: provisioning request not found")
+
+var ErrIdempotencyKeyClaimed = errors.New("error: idempotency key has already been claimed")
+
+// idempotencyKeyPrefix keeps idempotency key items apart from requests in the DynamoDB table.
+const idempotencyKeyPrefix = "idempotency#"
+
+// IdempotencyKeyTTL is how long a claimed idempotency key is kept. A request repeated after that is made afresh.
+// DynamoDB removes the key's item some time after its ExpiresAt, so a key past it is also treated as unclaimed.
+const IdempotencyKeyTTL = 24 * time.Hour
+
+// IdempotencyClaimTimeout is how long a claim may go without being bound to a request before it is taken to belong to
+// an invocation that died before calling CreateAccount. It is longer than the create Lambda's 15 minute timeout.
+const IdempotencyClaimTimeout = 20 * time.Minute
+
+// IdempotencyClaim is what is known about a claimed idempotency key.
+type IdempotencyClaim struct {
+	// RequestID is empty until BindIdempotencyKey is called.
+	RequestID string
+	ClaimedAt time.Time
+}
+
+// ProvisioningRequest is everything known about a request made to POST /accounts, keyed by CreateAccount's request ID.
+type ProvisioningRequest struct {
+	RequestID      string                    `json:"createAccountRequestId" dynamodbav:"RequestId"`
+	Payload        automation.AccountPayload `json:"payload" dynamodbav:"Payload"`
+	IdempotencyKey string                    `json:"idempotencyKey,omitempty" dynamodbav:"IdempotencyKey,omitempty"`
+	State          string                    `json:"state" dynamodbav:"State"`
+	Checkpoint     string                    `json:"checkpoint" dynamodbav:"Checkpoint"`
+	AccountID      string                    `json:"accountId,omitempty" dynamodbav:"AccountId,omitempty"`
+	RootID         string                    `json:"rootId,omitempty" dynamodbav:"RootId,omitempty"`
+	OUID           string                    `json:"ouId,omitempty" dynamodbav:"OuId,omitempty"`
+	Tags           map[string]string         `json:"tags,omitempty" dynamodbav:"Tags,omitempty"`
+	FailureReason  string                    `json:"failureReason,omitempty" dynamodbav:"FailureReason,omitempty"`
+	History        []StateTransition         `json:"history" dynamodbav:"History"`
+	CreatedAt      time.Time                 `json:"createdAt" dynamodbav:"CreatedAt"`
+	UpdatedAt      time.Time                 `json:"updatedAt" dynamodbav:"UpdatedAt"`
+}
+
+type StateTransition struct {
+	State  string    `json:"state" dynamodbav:"State"`
+	Detail string    `json:"detail,omitempty" dynamodbav:"Detail,omitempty"`
+	Time   time.Time `json:"time" dynamodbav:"Time"`
+}
+
+// RequestStore persists provisioning requests so they outlive the Lambda invocation that made them.
+type RequestStore interface {
+	// Get returns ErrRequestNotFound when there is no request with the given ID.
+	Get(requestID string) (*ProvisioningRequest, error)
+	Put(request *ProvisioningRequest) error
+
+	// ClaimIdempotencyKey reserves key for a new request. If the key is already claimed, and has not expired, it returns
+	// ErrIdempotencyKeyClaimed and the claim.
+	ClaimIdempotencyKey(key string) (IdempotencyClaim, error)
+	// ReclaimIdempotencyKey takes over a key from previous, a claim whose request will not be replayed. It returns
+	// ErrIdempotencyKeyClaimed and the current claim if the key no longer holds previous, because another retry
+	// reclaimed it first.
+	ReclaimIdempotencyKey(key string, previous IdempotencyClaim) (IdempotencyClaim, error)
+	BindIdempotencyKey(key string, requestID string) error
+	// ReleaseIdempotencyKey frees a claimed key when its request did not get as far as creating an account.
+	ReleaseIdempotencyKey(key string) error
+}
+
+// RecordTransition moves the request to state, appends it to the request's history and saves it.
+// Every state but RequestStateFailed also becomes the request's checkpoint, the last step it completed.
+func RecordTransition(store RequestStore, request *ProvisioningRequest, state string, detail string) error {
+	now := time.Now().UTC()
+	if request.CreatedAt.IsZero() {
+		request.CreatedAt = now
+	}
+	request.State = state
+	request.UpdatedAt = now
+	request.History = append(request.History, StateTransition{State: state, Detail: detail, Time: now})
+	if state == RequestStateFailed {
+		request.FailureReason = detail
+	} else {
+		request.Checkpoint = state
+		request.FailureReason = ""
+	}
+	return store.Put(request)
+}
+
+// PutRetries is how many more times PutWithRetry saves a request after the store fails to, PutRetryDelay apart.
+var (
+	PutRetries    = 2
+	PutRetryDelay = 200 * time.Millisecond
+)
+
+// PutWithRetry saves request, trying again when the store fails to, and returns the last error.
+func PutWithRetry(store RequestStore, request *ProvisioningRequest) error {
+	error := store.Put(request)
+	for retry := 0; error != nil && retry < PutRetries; retry++ {
+		time.Sleep(PutRetryDelay)
+		error = store.Put(request)
+	}
+	return error
+}
+
+type DynamoDBRequestStore struct {
+	svc   dynamodbiface.DynamoDBAPI
+	table string
+}
+
+func NewDynamoDBRequestStore(svc dynamodbiface.DynamoDBAPI, table string) *DynamoDBRequestStore {
+	return &DynamoDBRequestStore{svc: svc, table: table}
+}
+
+func (s *DynamoDBRequestStore) Get(requestID string) (*ProvisioningRequest, error) {
+	output, error := s.svc.GetItem(&dynamodb.GetItemInput{
+		TableName:      &s.table,
+		Key:            map[string]*dynamodb.AttributeValue{"RequestId": {S: &requestID}},
+		ConsistentRead: aws.Bool(true),
+	})
+	if error != nil {
+		return nil, error
+	}
+	if len(output.Item) == 0 {
+		return nil, ErrRequestNotFound
+	}
+
+	var request ProvisioningRequest
+	error = dynamodbattribute.UnmarshalMap(output.Item, &request)
+	if error != nil {
+		return nil, error
+	}
+	return &request, nil
+}
+
+func (s *DynamoDBRequestStore) Put(request *ProvisioningRequest) error {
+	item, error := dynamodbattribute.MarshalMap(request)
+	if error != nil {
+		return error
+	}
+	_, error = s.svc.PutItem(&dynamodb.PutItemInput{
+		TableName: &s.table,
+		Item:      item,
+	})
+	return error
+}
+
+// idempotencyKeyItem is the item a claimed idempotency key is kept in. ExpiresAt is the table's TTL attribute.
+type idempotencyKeyItem struct {
+	ID             string    `dynamodbav:"RequestId"`
+	BoundRequestID string    `dynamodbav:"BoundRequestId,omitempty"`
+	ClaimedAt      time.Time `dynamodbav:"ClaimedAt"`
+	ExpiresAt      int64     `dynamodbav:"ExpiresAt"`
+}
+
+func (s *DynamoDBRequestStore) putIdempotencyKey(item idempotencyKeyItem, condition *string, values map[string]*dynamodb.AttributeValue, now time.Time) error {
+	item.ExpiresAt = now.Add(IdempotencyKeyTTL).Unix()
+	attributes, error := dynamodbattribute.MarshalMap(item)
+	if error != nil {
+		return error
+	}
+	input := &dynamodb.PutItemInput{TableName: &s.table, Item: attributes}
+	if condition != nil {
+		input.ConditionExpression = condition
+		input.ExpressionAttributeValues = values
+	}
+	_, error = s.svc.PutItem(input)
+	return error
+}
+
+// getIdempotencyClaim returns the claim a conditional put of the key's item failed on, with ErrIdempotencyKeyClaimed.
+func (s *DynamoDBRequestStore) getIdempotencyClaim(id string) (IdempotencyClaim, error) {
+	output, error := s.svc.GetItem(&dynamodb.GetItemInput{
+		TableName:      &s.table,
+		Key:            map[string]*dynamodb.AttributeValue{"RequestId": {S: &id}},
+		ConsistentRead: aws.Bool(true),
+	})
+	if error != nil {
+		return IdempotencyClaim{}, error
+	}
+	var item idempotencyKeyItem
+	error = dynamodbattribute.UnmarshalMap(output.Item, &item)
+	if error != nil {
+		return IdempotencyClaim{}, error
+	}
+	return IdempotencyClaim{RequestID: item.BoundRequestID, ClaimedAt: item.ClaimedAt}, ErrIdempotencyKeyClaimed
+}
+
+func (s *DynamoDBRequestStore) ClaimIdempotencyKey(key string) (IdempotencyClaim, error) {
+	id := idempotencyKeyPrefix + key
+	now := time.Now().UTC()
+	// A key whose TTL has passed may not have been removed yet, and can be claimed again
+	values := map[string]*dynamodb.AttributeValue{":now": {N: aws.String(strconv.FormatInt(now.Unix(), 10))}}
+	error := s.putIdempotencyKey(idempotencyKeyItem{ID: id, ClaimedAt: now}, aws.String("attribute_not_exists(RequestId) OR ExpiresAt < :now"), values, now)
+	if aerr, ok := error.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return s.getIdempotencyClaim(id)
+	}
+	return IdempotencyClaim{}, error
+}
+
+// ReclaimIdempotencyKey only replaces the key's item while its ClaimedAt is still previous's. Every claim and bind
+// gives the item a new ClaimedAt, so of two retries that read the same claim only the first gets the key.
+func (s *DynamoDBRequestStore) ReclaimIdempotencyKey(key string, previous IdempotencyClaim) (IdempotencyClaim, error) {
+	id := idempotencyKeyPrefix + key
+	claimedAt, error := dynamodbattribute.Marshal(previous.ClaimedAt)
+	if error != nil {
+		return IdempotencyClaim{}, error
+	}
+	values := map[string]*dynamodb.AttributeValue{":claimedAt": claimedAt}
+	now := time.Now().UTC()
+	error = s.putIdempotencyKey(idempotencyKeyItem{ID: id, ClaimedAt: now}, aws.String("ClaimedAt = :claimedAt"), values, now)
+	if aerr, ok := error.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return s.getIdempotencyClaim(id)
+	}
+	return IdempotencyClaim{}, error
+}
+
+func (s *DynamoDBRequestStore) BindIdempotencyKey(key string, requestID string) error {
+	now := time.Now().UTC()
+	return s.putIdempotencyKey(idempotencyKeyItem{ID: idempotencyKeyPrefix + key, BoundRequestID: requestID, ClaimedAt: now}, nil, nil, now)
+}
+
+func (s *DynamoDBRequestStore) ReleaseIdempotencyKey(key string) error {
+	id := idempotencyKeyPrefix + key
+	_, error := s.svc.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName: &s.table,
+		Key:       map[string]*dynamodb.AttributeValue{"RequestId": {S: &id}},
+	})
+	return error
+}
+
+// MemoryRequestStore keeps requests for the life of the process. It backs tests and simulated handlers.
+type MemoryRequestStore struct {
+	mu              sync.Mutex
+	requests        map[string]ProvisioningRequest
+	idempotencyKeys map[string]IdempotencyClaim
+}
+
+func NewMemoryRequestStore() *MemoryRequestStore {
+	return &MemoryRequestStore{
+		requests:        map[string]ProvisioningRequest{},
+		idempotencyKeys: map[string]IdempotencyClaim{},
+	}
+}
+
+func (s *MemoryRequestStore) Get(requestID string) (*ProvisioningRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	request, ok := s.requests[requestID]
+	if !ok {
+		return nil, ErrRequestNotFound
+	}
+	copied := copyRequest(request)
+	return &copied, nil
+}
+
+func (s *MemoryRequestStore) Put(request *ProvisioningRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests[request.RequestID] = copyRequest(*request)
+	return nil
+}
+
+func (s *MemoryRequestStore) ClaimIdempotencyKey(key string) (IdempotencyClaim, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	if claim, ok := s.idempotencyKeys[key]; ok && now.Sub(claim.ClaimedAt) < IdempotencyKeyTTL {
+		return claim, ErrIdempotencyKeyClaimed
+	}
+	s.idempotencyKeys[key] = IdempotencyClaim{ClaimedAt: now}
+	return IdempotencyClaim{}, nil
+}
+
+func (s *MemoryRequestStore) ReclaimIdempotencyKey(key string, previous IdempotencyClaim) (IdempotencyClaim, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	claim, ok := s.idempotencyKeys[key]
+	if !ok || !claim.ClaimedAt.Equal(previous.ClaimedAt) {
+		return claim, ErrIdempotencyKeyClaimed
+	}
+	s.idempotencyKeys[key] = IdempotencyClaim{ClaimedAt: time.Now().UTC()}
+	return IdempotencyClaim{}, nil
+}
+
+func (s *MemoryRequestStore) BindIdempotencyKey(key string, requestID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.idempotencyKeys[key] = IdempotencyClaim{RequestID: requestID, ClaimedAt: time.Now().UTC()}
+	return nil
+}
+
+func (s *MemoryRequestStore) ReleaseIdempotencyKey(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.idempotencyKeys, key)
+	return nil
+}
+
+// copyRequest stops callers of MemoryRequestStore from mutating stored requests through shared slices and maps.
+func copyRequest(request ProvisioningRequest) ProvisioningRequest {
+	request.History = append([]StateTransition(nil), request.History...)
+	if request.Tags != nil {
+		tags := make(map[string]string, len(request.Tags))
+		for key, value := range request.Tags {
+			tags[key] = value
+		}
+		request.Tags = tags
+	}
+	return request
+}
@@ -0,0 +1,110 @@
+package createlambda
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go-account-automation/internal/automation"
+)
+
+func testRequestStore(t *testing.T, store RequestStore) {
+	_, error := store.Get("car-missing")
+	if error != ErrRequestNotFound {
+		t.Fatal("Expected ErrRequestNotFound for an unknown request, got: ", error)
+	}
+
+	request := &ProvisioningRequest{
+		RequestID: "car-012345678912",
+		Payload: automation.AccountPayload{
+			Name: "AWS_SEC_test_Dev",
+			Env:  "DEV",
+			Lob:  "SEC",
+		},
+	}
+	error = RecordTransition(store, request, RequestStateCreated, "")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	request.AccountID = "999999999999"
+	request.Tags = map[string]string{"Lob": "SEC"}
+	error = RecordTransition(store, request, RequestStateFailed, "error: Destination OU not found")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+
+	stored, error := store.Get(request.RequestID)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if !cmp.Equal(stored, request) {
+		t.Fatal("Stored request differs from the one saved: ", cmp.Diff(request, stored))
+	}
+	if stored.FailureReason != "error: Destination OU not found" || len(stored.History) != 2 {
+		t.Fatal("Stored request was expected to record both transitions and the failure reason")
+	}
+}
+
+func testIdempotencyKeys(t *testing.T, store RequestStore) {
+	claim, error := store.ClaimIdempotencyKey("key-1")
+	if error != nil || claim.RequestID != "" {
+		t.Fatal("Expected to claim an unused idempotency key, got: ", claim, error)
+	}
+
+	//test that an unbound claim is reported without a request ID and with the time it was claimed
+	claim, error = store.ClaimIdempotencyKey("key-1")
+	if error != ErrIdempotencyKeyClaimed || claim.RequestID != "" || time.Since(claim.ClaimedAt) > time.Minute {
+		t.Fatal("Expected an unbound claim, got: ", claim, error)
+	}
+
+	error = store.BindIdempotencyKey("key-1", "car-012345678912")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	claim, error = store.ClaimIdempotencyKey("key-1")
+	if error != ErrIdempotencyKeyClaimed || claim.RequestID != "car-012345678912" {
+		t.Fatal("Expected the claim to be bound to the request, got: ", claim, error)
+	}
+
+	//test that of two retries that read the same claim, only the first reclaims the key
+	_, error = store.ReclaimIdempotencyKey("key-1", claim)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	current, error := store.ReclaimIdempotencyKey("key-1", claim)
+	if error != ErrIdempotencyKeyClaimed || current.RequestID != "" || current.ClaimedAt.Equal(claim.ClaimedAt) {
+		t.Fatal("Expected the key to have been reclaimed already, got: ", current, error)
+	}
+
+	error = store.ReleaseIdempotencyKey("key-1")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	claim, error = store.ClaimIdempotencyKey("key-1")
+	if error != nil || claim.RequestID != "" {
+		t.Fatal("Expected to claim a released idempotency key, got: ", claim, error)
+	}
+}
+
+func TestMemoryRequestStore(t *testing.T) {
+	testRequestStore(t, NewMemoryRequestStore())
+	testIdempotencyKeys(t, NewMemoryRequestStore())
+}
+
+func TestDynamoDBRequestStore(t *testing.T) {
+	testRequestStore(t, NewDynamoDBRequestStore(&mockDynamoDBClient{}, "account-automation-requests"))
+	testIdempotencyKeys(t, NewDynamoDBRequestStore(&mockDynamoDBClient{}, "account-automation-requests"))
+
+	//test that a claim is given the TTL DynamoDB expires it by, and can only replace an expired one
+	svc := &mockDynamoDBClient{}
+	_, error := NewDynamoDBRequestStore(svc, "account-automation-requests").ClaimIdempotencyKey("key-1")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	expiresAt, _ := strconv.ParseInt(*svc.items["idempotency#key-1"]["ExpiresAt"].N, 10, 64)
+	if time.Until(time.Unix(expiresAt, 0)) < IdempotencyKeyTTL-time.Minute || *svc.lastPut.ConditionExpression != "attribute_not_exists(RequestId) OR ExpiresAt < :now" {
+		t.Fatal("Unexpected claim: ", svc.lastPut)
+	}
+}
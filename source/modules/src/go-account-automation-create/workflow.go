@@ -0,0 +1,130 @@
+package createlambda
+
+import (
+	"context"
+	"log"
+
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+
+	"go-account-automation/internal/automation"
+)
+
+// ProvisioningStep is one checkpointed step of the work done after CreateAccount has been accepted.
+// State is recorded as the request's checkpoint once Run succeeds.
+type ProvisioningStep struct {
+	State string
+	Run   func(ctx context.Context, svc organizationsiface.OrganizationsAPI, tree *automation.OrgTree, request *ProvisioningRequest) error
+}
+
+// ProvisioningSteps are run in order by CompleteProvisioning. The create step itself is
+// checkpointed as RequestStateCreated by HandleRequest.
+var ProvisioningSteps = []ProvisioningStep{
+	{State: RequestStateStatusPolled, Run: AwaitAccountStep},
+	{State: RequestStateOUResolved, Run: ResolveOUStep},
+	{State: RequestStateMoved, Run: MoveAccountStep},
+	{State: RequestStateTagged, Run: TagAccountStep},
+}
+
+func AwaitAccountStep(ctx context.Context, svc organizationsiface.OrganizationsAPI, tree *automation.OrgTree, request *ProvisioningRequest) error {
+	log.Println("Validating account creation status...")
+	accountID, error := ValidateAccountStatus(ctx, svc, request.RequestID)
+	if error != nil {
+		return error
+	}
+	request.AccountID = accountID
+	return nil
+}
+
+func ResolveOUStep(ctx context.Context, svc organizationsiface.OrganizationsAPI, tree *automation.OrgTree, request *ProvisioningRequest) error {
+	log.Println("Retrieving Root ID and correct OU ID based on payload...")
+	root, ou, error := RetrieveOUs(svc, tree, request.Payload)
+	stats := tree.Stats()
+	log.Println("Organization tree cache hits: ", stats.Hits, ", misses: ", stats.Misses)
+	if error != nil {
+		return error
+	}
+	request.RootID, request.OUID = root, ou
+	return nil
+}
+
+func MoveAccountStep(ctx context.Context, svc organizationsiface.OrganizationsAPI, tree *automation.OrgTree, request *ProvisioningRequest) error {
+	log.Println("Moving account to correct OU...")
+	return automation.MoveAccount(svc, request.AccountID, request.OUID)
+}
+
+func TagAccountStep(ctx context.Context, svc organizationsiface.OrganizationsAPI, tree *automation.OrgTree, request *ProvisioningRequest) error {
+	log.Println("Generating a list of Tag objects from payload...")
+	tags := automation.GenerateTags(request.Payload)
+	log.Println("Tags: ", tags)
+
+	log.Println("Tagging Account...")
+	error := automation.TagAccount(svc, tags, request.AccountID)
+	if error != nil {
+		return error
+	}
+	request.Tags = map[string]string{}
+	for _, tag := range tags {
+		request.Tags[*tag.Key] = *tag.Value
+	}
+	return nil
+}
+
+// NextStep returns the index in ProvisioningSteps of the first step after the request's checkpoint,
+// or len(ProvisioningSteps) when the request is complete.
+func NextStep(request *ProvisioningRequest) int {
+	for i, step := range ProvisioningSteps {
+		if step.State == request.Checkpoint {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// RecordFailure marks the request as failed. The original error is what gets reported,
+// so a failure to record it is only logged.
+func RecordFailure(store RequestStore, request *ProvisioningRequest, cause error) {
+	error := RecordTransition(store, request, RequestStateFailed, cause.Error())
+	if error != nil {
+		log.Println("ERROR: recording failure for request ", request.RequestID, ": ", error.Error())
+	}
+}
+
+// RestoreRequest records request, sent with the follow-up by an invocation that could not record it, unless the
+// store already has it.
+func RestoreRequest(store RequestStore, request *ProvisioningRequest) error {
+	_, error := store.Get(request.RequestID)
+	if error != ErrRequestNotFound {
+		return error
+	}
+	log.Println("Recording provisioning request the follow-up was sent with...")
+	return store.Put(request)
+}
+
+// CompleteProvisioning runs the provisioning steps from the first one the request has not checkpointed,
+// so it both finishes a new request and resumes one that failed part way through. It returns
+// ErrAccountCreationPending, without recording a failure, when ctx is done before the account has been created.
+func CompleteProvisioning(ctx context.Context, svc organizationsiface.OrganizationsAPI, tree *automation.OrgTree, store RequestStore, requestID string) error {
+	log.Println("Looking up stored provisioning request...")
+	request, error := store.Get(requestID)
+	if error != nil {
+		return error
+	}
+
+	for _, step := range ProvisioningSteps[NextStep(request):] {
+		error = step.Run(ctx, svc, tree, request)
+		if error == ErrAccountCreationPending {
+			return error
+		}
+		if error != nil {
+			RecordFailure(store, request, error)
+			return error
+		}
+		error = RecordTransition(store, request, step.State, "")
+		if error != nil {
+			return error
+		}
+	}
+
+	log.Println("Provisioning complete for account: ", request.AccountID)
+	return nil
+}
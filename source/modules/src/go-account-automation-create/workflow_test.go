@@ -0,0 +1,348 @@
+package createlambda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/fakeorg"
+)
+
+func TestCompleteProvisioningResumesFromCheckpoint(t *testing.T) {
+	payload := automation.AccountPayload{
+		Name:          "AWS_SEC_test_Dev",
+		CostCenter:    "01234",
+		AccountPOC:    "john.doe@example.com",
+		ApplicationID: "00000000-0000-0000-0000-000000000000",
+		Env:           "DEV",
+		Lob:           "SEC",
+	}
+	testCases := []struct {
+		failingOperation   string
+		expectedCheckpoint string
+		// operations that must not be repeated when the request is resumed
+		completedOperations []string
+	}{
+		{"DescribeCreateAccountStatus", RequestStateCreated, nil},
+		{"ListRoots", RequestStateStatusPolled, []string{"DescribeCreateAccountStatus"}},
+		{"MoveAccount", RequestStateOUResolved, []string{"DescribeCreateAccountStatus", "ListRoots"}},
+		{"TagResource", RequestStateMoved, []string{"DescribeCreateAccountStatus", "ListRoots", "MoveAccount"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.failingOperation, func(t *testing.T) {
+			store := NewMemoryRequestStore()
+			svc := secOrg()
+			requestID, error := CreateAccount(svc, payload.Name)
+			if error != nil {
+				t.Fatal(error.Error())
+			}
+			error = RecordTransition(store, &ProvisioningRequest{RequestID: requestID, Payload: payload}, RequestStateCreated, "")
+			if error != nil {
+				t.Fatal(error.Error())
+			}
+
+			svc.Fault(testCase.failingOperation, errors.New("injected failure"))
+			error = CompleteProvisioning(context.Background(), svc, nil, store, requestID)
+			if error == nil {
+				t.Fatal("Provisioning was expected to fail but didn't")
+			}
+			request, _ := store.Get(requestID)
+			if request.State != RequestStateFailed || request.Checkpoint != testCase.expectedCheckpoint || request.FailureReason != "injected failure" {
+				t.Fatal("Failed request was not checkpointed as expected: ", request.State, request.Checkpoint, request.FailureReason)
+			}
+
+			//resume once the failure has cleared
+			svc.Fault(testCase.failingOperation, nil)
+			calls := map[string]int{}
+			for _, operation := range append(testCase.completedOperations, testCase.failingOperation) {
+				calls[operation] = svc.Calls(operation)
+			}
+			error = CompleteProvisioning(context.Background(), svc, nil, store, requestID)
+			if error != nil {
+				t.Fatal(error.Error())
+			}
+			request, _ = store.Get(requestID)
+			if request.State != RequestStateTagged || request.Checkpoint != RequestStateTagged || request.FailureReason != "" {
+				t.Fatal("Resumed request was not completed: ", request.State, request.Checkpoint)
+			}
+			if request.AccountID == "" || request.OUID != "ou-abcd-secdev" || request.Tags["Name"] != payload.Name {
+				t.Fatal("Resumed request lost the results of its completed steps: ", request)
+			}
+			for _, operation := range testCase.completedOperations {
+				if svc.Calls(operation) != calls[operation] {
+					t.Fatal("Resuming repeated a completed step: ", operation)
+				}
+			}
+			if svc.Calls(testCase.failingOperation) == calls[testCase.failingOperation] {
+				t.Fatal("Resuming did not retry the failed step")
+			}
+		})
+	}
+}
+
+func TestProvisioningAgainstFakeOrganization(t *testing.T) {
+	defer func(interval time.Duration) { AccountStatusPollInterval = interval }(AccountStatusPollInterval)
+	AccountStatusPollInterval = 0
+
+	svc := fakeorg.NewClient()
+	svc.PageSize = 1
+	svc.CreatePolls = 2
+	workloads := svc.AddOU(fakeorg.RootID, "ou-abcd-01234567", "Workloads")
+	svc.AddOU(workloads, "", "Prod")
+	dev := svc.AddOU(workloads, "", "Dev")
+	svc.AddOU(dev, "", "OPS")
+	app := svc.AddOU(dev, "", "APP")
+	svc.FailAccountCreation("AWS_APP_broken_Dev", organizations.CreateAccountFailureReasonInternalFailure)
+	h := &Handler{Mode: automation.ModeLive, Org: svc, Store: NewMemoryRequestStore(), Lambda: &mockLambdaClient{}}
+
+	provision := func(name string) (*ProvisioningRequest, error) {
+		payload := preflightPayload()
+		payload.Name = name
+		body, _ := json.Marshal(payload)
+		response, _ := h.HandleEvent(context.Background(), Event{APIGatewayProxyRequest: events.APIGatewayProxyRequest{Body: string(body)}})
+		if response.StatusCode != 202 {
+			t.Fatal("Unexpected response: ", response.StatusCode, response.Body)
+		}
+		var accepted CreateAccountResponse
+		json.Unmarshal([]byte(response.Body), &accepted)
+
+		error := h.HandleFollowUp(context.Background(), FollowUpEvent{RequestID: accepted.RequestID})
+		request, _ := h.Store.Get(accepted.RequestID)
+		return request, error
+	}
+
+	//test that the account is polled until created, then moved down the tree, across pages, and tagged
+	request, error := provision("AWS_APP_test_Dev")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if request.State != RequestStateTagged || request.RootID != fakeorg.RootID || request.OUID != app {
+		t.Fatal("Request was not completed: ", request)
+	}
+	if svc.Parent(request.AccountID) != app || svc.Tags(request.AccountID)["Name"] != "AWS_APP_test_Dev" {
+		t.Fatal("Account was not moved and tagged: ", svc.Parent(request.AccountID), svc.Tags(request.AccountID))
+	}
+	if svc.Calls("DescribeCreateAccountStatus") != 3 {
+		t.Fatal("Unexpected polls of the account creation: ", svc.Calls("DescribeCreateAccountStatus"))
+	}
+
+	//test that a failed creation is recorded with the reason Organizations gives
+	request, error = provision("AWS_APP_broken_Dev")
+	if error == nil {
+		t.Fatal("Provisioning was expected to fail but didn't")
+	}
+	if request.State != RequestStateFailed || request.Checkpoint != RequestStateCreated || request.FailureReason != organizations.CreateAccountFailureReasonInternalFailure {
+		t.Fatal("Failed request was not recorded as expected: ", request.State, request.Checkpoint, request.FailureReason)
+	}
+}
+
+func TestNextStep(t *testing.T) {
+	if NextStep(&ProvisioningRequest{Checkpoint: RequestStateCreated}) != 0 {
+		t.Fatal("A created request was expected to start at the first step")
+	}
+	if NextStep(&ProvisioningRequest{Checkpoint: RequestStateMoved}) != 3 {
+		t.Fatal("A moved request was expected to resume at the tag step")
+	}
+	if NextStep(&ProvisioningRequest{Checkpoint: RequestStateTagged}) != len(ProvisioningSteps) {
+		t.Fatal("A tagged request was expected to be complete")
+	}
+}
+
+func TestHandleRequestWhenTheRequestCannotBeRecorded(t *testing.T) {
+	defer func(interval time.Duration, delay time.Duration) {
+		AccountStatusPollInterval, PutRetryDelay = interval, delay
+	}(AccountStatusPollInterval, PutRetryDelay)
+	AccountStatusPollInterval, PutRetryDelay = 0, 0
+
+	svc := fakeorg.NewClient()
+	workloads := svc.AddOU(fakeorg.RootID, "ou-abcd-01234567", "Workloads")
+	dev := svc.AddOU(workloads, "", "Dev")
+	svc.AddOU(dev, "", "APP")
+	store := &failingRequestStore{MemoryRequestStore: NewMemoryRequestStore()}
+	lambda := &mockLambdaClient{}
+	h := &Handler{Mode: automation.ModeLive, Org: svc, Store: store, Lambda: lambda}
+
+	create := func(name string, putFailures int) (CreateAccountResponse, *FollowUpEvent) {
+		store.putFailures = putFailures
+		payload := preflightPayload()
+		payload.Name = name
+		body, _ := json.Marshal(payload)
+		response, _ := h.HandleEvent(context.Background(), Event{APIGatewayProxyRequest: events.APIGatewayProxyRequest{Body: string(body)}})
+		if response.StatusCode != 202 {
+			t.Fatal("Unexpected response: ", response.StatusCode, response.Body)
+		}
+		var accepted CreateAccountResponse
+		json.Unmarshal([]byte(response.Body), &accepted)
+		var event Event
+		json.Unmarshal(lambda.invokeInput.Payload, &event)
+		return accepted, event.FollowUp
+	}
+
+	//test that a Put that fails once is retried
+	accepted, followUp := create("AWS_APP_retried_Dev", 1)
+	if request, error := store.Get(accepted.RequestID); error != nil || request.State != RequestStateCreated || followUp.Request != nil {
+		t.Fatal("Request was not recorded by the retry: ", request, error, followUp)
+	}
+
+	//test that a request that cannot be recorded, by RecordTransition or any retry, is accepted, and recorded by the follow-up it is sent with
+	accepted, followUp = create("AWS_APP_unrecorded_Dev", PutRetries+2)
+	if _, error := store.Get(accepted.RequestID); error != ErrRequestNotFound || followUp.Request == nil || followUp.Request.RequestID != accepted.RequestID {
+		t.Fatal("Request was expected to be left to the follow-up: ", error, followUp)
+	}
+	error := h.HandleFollowUp(context.Background(), *followUp)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	request, error := store.Get(accepted.RequestID)
+	if error != nil || request.State != RequestStateTagged || request.IdempotencyKey == "" {
+		t.Fatal("Request was not recorded and completed by the follow-up: ", request, error)
+	}
+
+	//test that a repeat of the request replays it rather than creating another account
+	create("AWS_APP_unrecorded_Dev", 0)
+	if svc.Calls("CreateAccount") != 2 {
+		t.Fatal("A repeated request was not expected to create another account: ", svc.Calls("CreateAccount"))
+	}
+}
+
+func TestHandleRequestWithAStaleIdempotencyKey(t *testing.T) {
+	defer func(interval time.Duration) { AccountStatusPollInterval = interval }(AccountStatusPollInterval)
+	AccountStatusPollInterval = 0
+
+	svc := fakeorg.NewClient()
+	workloads := svc.AddOU(fakeorg.RootID, "ou-abcd-01234567", "Workloads")
+	dev := svc.AddOU(workloads, "", "Dev")
+	svc.AddOU(dev, "", "APP")
+	store := &racingRequestStore{MemoryRequestStore: NewMemoryRequestStore()}
+	h := &Handler{Mode: automation.ModeLive, Org: svc, Store: store, Lambda: &mockLambdaClient{}}
+	body, _ := json.Marshal(preflightPayload())
+	request := events.APIGatewayProxyRequest{Body: string(body), Headers: map[string]string{IdempotencyKeyHeader: "INC0012345"}}
+
+	//test that of two retries of a request whose claim went stale, the one that loses the reclaim gets 409 and creates no account
+	store.idempotencyKeys["INC0012345"] = IdempotencyClaim{ClaimedAt: time.Now().Add(-IdempotencyClaimTimeout - time.Minute)}
+	response, _ := h.HandleEvent(context.Background(), Event{APIGatewayProxyRequest: request})
+	var conflict ConflictError
+	json.Unmarshal([]byte(response.Body), &conflict)
+	if response.StatusCode != 409 || conflict.Code != "request_in_progress" || svc.Calls("CreateAccount") != 0 {
+		t.Fatal("Unexpected response: ", response.StatusCode, response.Body, svc.Calls("CreateAccount"))
+	}
+
+	//test that a retry that reclaims the key creates the account and binds the key to it
+	store.idempotencyKeys["INC0012345"] = IdempotencyClaim{ClaimedAt: time.Now().Add(-IdempotencyClaimTimeout - time.Minute)}
+	h.Store = store.MemoryRequestStore
+	response, _ = h.HandleEvent(context.Background(), Event{APIGatewayProxyRequest: request})
+	var accepted CreateAccountResponse
+	json.Unmarshal([]byte(response.Body), &accepted)
+	if response.StatusCode != 202 || svc.Calls("CreateAccount") != 1 || store.idempotencyKeys["INC0012345"].RequestID != accepted.RequestID {
+		t.Fatal("Unexpected response: ", response.StatusCode, response.Body, store.idempotencyKeys["INC0012345"])
+	}
+}
+
+func TestHandleResumeRequest(t *testing.T) {
+	store := NewMemoryRequestStore()
+	h := &Handler{Mode: automation.ModeLive, Org: fakeorg.NewClient(), Store: store, Lambda: &mockLambdaClient{}}
+	resume := func(requestID string) *events.APIGatewayProxyResponse {
+		response, _ := h.HandleEvent(context.Background(), Event{APIGatewayProxyRequest: events.APIGatewayProxyRequest{
+			Resource:       "/accounts/requests/{id}/resume",
+			PathParameters: map[string]string{"id": requestID},
+		}})
+		return response
+	}
+	record := func(requestID string, states ...string) {
+		request := &ProvisioningRequest{RequestID: requestID, Payload: preflightPayload()}
+		for _, state := range states {
+			if error := RecordTransition(store, request, state, ""); error != nil {
+				t.Fatal(error.Error())
+			}
+		}
+	}
+	record("car-failed", RequestStateCreated, RequestStateFailed)
+	record("car-in-progress", RequestStateCreated, RequestStateStatusPolled)
+	record("car-complete", RequestStateCreated, RequestStateStatusPolled, RequestStateOUResolved, RequestStateMoved, RequestStateTagged)
+	record("car-stranded", RequestStateCreated, RequestStateStatusPolled)
+	stranded, _ := store.Get("car-stranded")
+	stranded.UpdatedAt = time.Now().Add(-StaleRequestTimeout - time.Minute)
+	store.Put(stranded)
+
+	testCases := []struct {
+		requestID      string
+		expectedStatus int
+	}{
+		{"car-failed", 202},
+		{"car-in-progress", 409},
+		{"car-complete", 409},
+		{"car-missing", 404},
+		//test that a request stranded by a follow-up that died without recording a failure can be resumed
+		{"car-stranded", 202},
+		//test that a resumed request is not resumed again while its follow-up runs
+		{"car-failed", 409},
+		{"car-stranded", 409},
+	}
+	for _, testCase := range testCases {
+		response := resume(testCase.requestID)
+		if response.StatusCode != testCase.expectedStatus {
+			t.Fatal("Unexpected response resuming ", testCase.requestID, ": ", response.StatusCode, response.Body)
+		}
+	}
+	if request, _ := store.Get("car-stranded"); request.State != RequestStateStatusPolled || request.Checkpoint != RequestStateStatusPolled {
+		t.Fatal("Resumed request was expected to keep its checkpoint: ", request)
+	}
+}
+
+func TestHandleFollowUpBeforeTheDeadline(t *testing.T) {
+	defer func(interval time.Duration, margin time.Duration) {
+		AccountStatusPollInterval, FollowUpDeadlineMargin = interval, margin
+	}(AccountStatusPollInterval, FollowUpDeadlineMargin)
+	AccountStatusPollInterval, FollowUpDeadlineMargin = 10*time.Millisecond, 0
+
+	svc := fakeorg.NewClient()
+	svc.CreatePolls = 1000
+	output, error := svc.CreateAccount(&organizations.CreateAccountInput{AccountName: aws.String("AWS_APP_slow_Dev"), Email: aws.String("AWS_APP_slow_Dev@example.com")})
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	requestID := aws.StringValue(output.CreateAccountStatus.Id)
+	store := NewMemoryRequestStore()
+	error = RecordTransition(store, &ProvisioningRequest{RequestID: requestID, Payload: preflightPayload()}, RequestStateCreated, "")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	lambda := &mockLambdaClient{}
+	h := &Handler{Mode: automation.ModeLive, Org: svc, Store: store, Lambda: lambda}
+	followUp := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if followUpError := h.HandleFollowUp(ctx, FollowUpEvent{RequestID: requestID}); followUpError != nil {
+			t.Fatal(followUpError.Error())
+		}
+	}
+
+	//test that a creation still in progress at the deadline is handed to another follow-up rather than cut off
+	followUp()
+	request, _ := store.Get(requestID)
+	var event Event
+	if lambda.invokeInput != nil {
+		json.Unmarshal(lambda.invokeInput.Payload, &event)
+	}
+	if request.State != RequestStateCreated || len(request.History) != 2 || event.FollowUp == nil || event.FollowUp.RequestID != requestID {
+		t.Fatal("Follow-up was expected to be rescheduled: ", request, event.FollowUp)
+	}
+
+	//test that a request that has waited AccountCreationTimeout is recorded as failed instead
+	request.CreatedAt = time.Now().Add(-AccountCreationTimeout - time.Minute)
+	store.Put(request)
+	lambda.invokeInput = nil
+	followUp()
+	request, _ = store.Get(requestID)
+	if request.State != RequestStateFailed || request.Checkpoint != RequestStateCreated || lambda.invokeInput != nil {
+		t.Fatal("Request was expected to fail after AccountCreationTimeout: ", request)
+	}
+}
@@ -0,0 +1,157 @@
+package deletelambda
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+
+	"go-account-automation/internal/automation"
+)
+
+type CloseAccountResponse struct {
+	AccountID   string `json:"accountId"`
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	SuspendedOU string `json:"suspendedOu,omitempty"`
+	// DryRun is set when the account passed the safety checks but was left as it is
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// ValidateClosure checks that the account may be closed by this caller and returns the account's current parent.
+// A failed check is returned as an *automation.APIError whose code names the check, and a name that does not follow
+// the naming convention as a *NamingError.
+func ValidateClosure(svc organizationsiface.OrganizationsAPI, tree *automation.OrgTree, account *organizations.Account, callerLob string, suspendedOU string) (string, error) {
+	accountID := aws.StringValue(account.Id)
+	lob, env, error := automation.ParseAccountName(aws.StringValue(account.Name))
+	if error != nil {
+		// a name that does not follow the convention is returned as the *NamingError it is
+		return "", error
+	}
+	// An account moved since it was created carries its current lob and env in its tags
+	tags, error := automation.ListAccountTags(svc, accountID)
+	if error != nil {
+		return "", error
+	}
+	if tags["Lob"] != "" {
+		lob = tags["Lob"]
+	}
+	if tags["Env"] != "" {
+		env = tags["Env"]
+	}
+	payload := automation.PayloadFromTags(accountID, tags)
+	payload.Lob, payload.Env = lob, env
+
+	if callerLob == "" {
+		return "", &automation.APIError{StatusCode: 403, Code: "caller_lob_unknown", Message: "error: the caller's line of business could not be determined"}
+	}
+	if !strings.EqualFold(callerLob, lob) {
+		return "", &automation.APIError{StatusCode: 403, Code: "lob_mismatch", Message: "error: account " + accountID + " belongs to line of business " + lob + ", not " + callerLob}
+	}
+
+	if aws.StringValue(account.Status) != organizations.AccountStatusActive {
+		return "", &automation.APIError{StatusCode: 409, Code: "account_not_active", Message: "error: account " + accountID + " is " + aws.StringValue(account.Status) + " and cannot be closed"}
+	}
+
+	parent, error := automation.RetrieveParent(svc, accountID)
+	if error != nil {
+		return "", error
+	}
+	// An earlier attempt may have moved the account to the Suspended OU before CloseAccount failed
+	if suspendedOU != "" && parent == suspendedOU {
+		return parent, nil
+	}
+
+	_, ou, error := automation.RetrieveOUs(svc, tree, payload)
+	if error != nil {
+		return "", error
+	}
+	if ou == "" || parent != ou {
+		return "", &automation.APIError{StatusCode: 409, Code: "account_not_managed", Message: "error: account " + accountID + " is not in the " + env + " OU managed for line of business " + lob}
+	}
+	return parent, nil
+}
+
+func CloseAccount(svc organizationsiface.OrganizationsAPI, accountID string) error {
+	_, error := svc.CloseAccount(&organizations.CloseAccountInput{AccountId: &accountID})
+	return error
+}
+
+func (h *Handler) HandleRequest(request events.APIGatewayProxyRequest) (*events.APIGatewayProxyResponse, error) {
+	svc := h.Org
+	suspendedOU := os.Getenv("SUSPENDED_OU")
+
+	accountID := request.PathParameters["accountId"]
+	if accountID == "" {
+		return automation.HandleErrors(errors.New("error: account id is required"), 400)
+	}
+
+	log.Println("Describing account...")
+	account, error := svc.DescribeAccount(&organizations.DescribeAccountInput{AccountId: &accountID})
+	if error != nil {
+		return automation.HandleErrors(error, 500)
+	}
+
+	log.Println("Validating account may be closed...")
+	parent, error := ValidateClosure(svc, h.Tree, account.Account, automation.RetrieveCallerLob(request), suspendedOU)
+	if namingError, ok := error.(*automation.NamingError); ok {
+		return automation.HandleNamingError(namingError)
+	}
+	if error != nil {
+		return automation.HandleErrors(error, 500)
+	}
+	log.Println("Account passed closure checks...")
+
+	if h.Mode == automation.ModeDryRun {
+		log.Println("Dry-run mode, leaving the account as it is...")
+		return CloseResponse(200, CloseAccountResponse{
+			AccountID:   accountID,
+			Name:        aws.StringValue(account.Account.Name),
+			Status:      aws.StringValue(account.Account.Status),
+			SuspendedOU: suspendedOU,
+			DryRun:      true,
+		})
+	}
+
+	if suspendedOU != "" && parent != suspendedOU {
+		log.Println("Moving account to Suspended OU...")
+		error = automation.MoveAccount(svc, accountID, suspendedOU)
+		if error != nil {
+			return automation.HandleErrors(error, 500)
+		}
+	}
+
+	log.Println("Closing account...")
+	error = CloseAccount(svc, accountID)
+	if error != nil {
+		return automation.HandleErrors(error, 500)
+	}
+
+	return CloseResponse(202, CloseAccountResponse{
+		AccountID:   accountID,
+		Name:        aws.StringValue(account.Account.Name),
+		Status:      organizations.AccountStatusPendingClosure,
+		SuspendedOU: suspendedOU,
+	})
+}
+
+func CloseResponse(statusCode int, responseBody CloseAccountResponse) (*events.APIGatewayProxyResponse, error) {
+	log.Println("Stringifying response body...")
+	jsonResponseBody, error := json.Marshal(responseBody)
+	if error != nil {
+		return automation.HandleErrors(error, 500)
+	}
+	log.Println("Response payload: ", responseBody)
+
+	response := &events.APIGatewayProxyResponse{
+		StatusCode: statusCode,
+		Body:       string(jsonResponseBody),
+	}
+	return response, nil
+}
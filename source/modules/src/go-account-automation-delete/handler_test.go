@@ -0,0 +1,179 @@
+package deletelambda
+
+import (
+	"log"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/fakeorg"
+)
+
+// closureOrg returns an organization with a Dev OU under each of the SEC and IS OUs, an unmanaged OU and a Suspended
+// OU, and the ID of an untagged SEC account in the SEC Dev OU.
+func closureOrg() (*fakeorg.Client, string) {
+	svc := fakeorg.NewClient()
+	svc.AddOU(fakeorg.RootID, "ou-abcd-12345678", "Security")
+	svc.AddOU("ou-abcd-12345678", "ou-abcd-secdev", "Dev")
+	svc.AddOU(fakeorg.RootID, "ou-abcd-23456789", "IS")
+	svc.AddOU("ou-abcd-23456789", "ou-abcd-isdev", "Dev")
+	svc.AddOU(fakeorg.RootID, "ou-abcd-87654321", "Unmanaged")
+	svc.AddOU(fakeorg.RootID, "ou-abcd-suspended", "Suspended")
+	accountID := svc.AddAccount("ou-abcd-secdev", "AWS_SEC_test_Dev", "AWS_SEC_test_Dev@example.com", nil)
+	return svc, accountID
+}
+
+// describeAccount returns the account as Organizations describes it.
+func describeAccount(t *testing.T, svc *fakeorg.Client, accountID string) *organizations.Account {
+	t.Helper()
+	output, error := svc.DescribeAccount(&organizations.DescribeAccountInput{AccountId: aws.String(accountID)})
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	return output.Account
+}
+
+func TestValidateClosure(t *testing.T) {
+	svc, accountID := closureOrg()
+	account := describeAccount(t, svc, accountID)
+
+	parent, error := ValidateClosure(svc, nil, account, "SEC", "")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if parent != "ou-abcd-secdev" {
+		t.Fatal("Parent was not as expected: ", parent)
+	}
+
+	testCases := []struct {
+		name               string
+		status             string
+		callerLob          string
+		parentID           string
+		expectedStatusCode int
+		expectedCode       string
+	}{
+		{"unknown caller", organizations.AccountStatusActive, "", "ou-abcd-secdev", 403, "caller_lob_unknown"},
+		{"other caller", organizations.AccountStatusActive, "IS", "ou-abcd-secdev", 403, "lob_mismatch"},
+		{"suspended account", organizations.AccountStatusSuspended, "SEC", "ou-abcd-secdev", 409, "account_not_active"},
+		{"unmanaged OU", organizations.AccountStatusActive, "SEC", "ou-abcd-87654321", 409, "account_not_managed"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			svc, accountID := closureOrg()
+			error := automation.MoveAccount(svc, accountID, testCase.parentID)
+			if error != nil {
+				t.Fatal(error.Error())
+			}
+			account := describeAccount(t, svc, accountID)
+			account.Status = aws.String(testCase.status)
+			_, error = ValidateClosure(svc, nil, account, testCase.callerLob, "")
+			apiError, ok := error.(*automation.APIError)
+			if !ok || apiError.StatusCode != testCase.expectedStatusCode || apiError.Code != testCase.expectedCode {
+				t.Fatal("Closure was expected to fail with ", testCase.expectedStatusCode, " ", testCase.expectedCode, ", got: ", error)
+			}
+		})
+	}
+
+	//test that a name that does not follow the naming convention is reported as a naming error
+	malformed := describeAccount(t, svc, accountID)
+	malformed.Name = aws.String("AWS_SEC_Dev")
+	_, error = ValidateClosure(svc, nil, malformed, "SEC", "")
+	if namingError, ok := error.(*automation.NamingError); !ok || namingError.Code != "too_few_segments" {
+		t.Fatal("Closure was expected to fail with a naming error, got: ", error)
+	}
+
+	//test that the lob in the tags of an account moved since it was created is the one checked
+	error = automation.MoveAccount(svc, accountID, "ou-abcd-isdev")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	error = automation.TagAccount(svc, []*organizations.Tag{{Key: aws.String("Lob"), Value: aws.String("IS")}}, accountID)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	_, error = ValidateClosure(svc, nil, account, "SEC", "")
+	if apiError, ok := error.(*automation.APIError); !ok || apiError.StatusCode != 403 || apiError.Code != "lob_mismatch" {
+		t.Fatal("Closure was expected to fail with lob_mismatch, got: ", error)
+	}
+	_, error = ValidateClosure(svc, nil, account, "IS", "")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+
+	//test that an account already moved to the Suspended OU passes
+	error = automation.MoveAccount(svc, accountID, "ou-abcd-suspended")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	parent, error = ValidateClosure(svc, nil, account, "IS", "ou-abcd-suspended")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if parent != "ou-abcd-suspended" {
+		t.Fatal("Parent was not as expected: ", parent)
+	}
+}
+
+func TestRetrieveOUsAcrossPages(t *testing.T) {
+	svc := fakeorg.NewClient()
+	svc.AddOU(fakeorg.RootID, "ou-abcd-01234567", "Workloads")
+	svc.AddOU("ou-abcd-01234567", "ou-abcd-22222222", "Lab")
+	svc.AddOU("ou-abcd-01234567", "ou-abcd-33333333", "Test")
+	svc.AddOU("ou-abcd-01234567", "ou-abcd-44444444", "Dev")
+	svc.AddOU("ou-abcd-44444444", "ou-abcd-55555555", "OPS")
+	svc.AddOU("ou-abcd-44444444", "ou-abcd-66666666", "APP")
+	svc.PageSize = 1
+
+	root, ou, error := automation.RetrieveOUs(svc, nil, automation.AccountPayload{Env: "Dev", Lob: "APP"})
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if root != fakeorg.RootID || ou != "ou-abcd-66666666" {
+		t.Fatal("RetrieveOUs did not find OUs past the first page: ", root, ou)
+	}
+}
+
+func TestRetrieveOUsWithPlacementRules(t *testing.T) {
+	error := os.Setenv("PLACEMENT_RULES", `{rules: [{name: sandbox, match: {env: Lab}, path: "Sandbox/{lob}"}], default: {path: "Workloads/{env}/{lob}"}}`)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	defer os.Unsetenv("PLACEMENT_RULES")
+
+	svc := fakeorg.NewClient()
+	svc.AddOU(fakeorg.RootID, "ou-abcd-22222222", "Sandbox")
+	svc.AddOU("ou-abcd-22222222", "ou-abcd-33333333", "APP")
+	root, ou, error := automation.RetrieveOUs(svc, nil, automation.AccountPayload{Env: "Lab", Lob: "APP"})
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if root != fakeorg.RootID || ou != "ou-abcd-33333333" {
+		t.Fatal("RetrieveOUs did not follow the placement rules: ", root, ou)
+	}
+
+	//test that an OU missing from the tree is reported as empty
+	_, ou, error = automation.RetrieveOUs(svc, nil, automation.AccountPayload{Env: "Dev", Lob: "APP"})
+	if error != nil || ou != "" {
+		t.Fatal("RetrieveOUs was expected to find no OU: ", ou, error)
+	}
+}
+
+func TestMain(m *testing.M) {
+	var err error
+
+	err = os.Setenv("WORKLOAD_OU", "ou-abcd-01234567")
+	if err != nil {
+		log.Panic("Issue setting env var")
+	}
+
+	err = os.Setenv("SEC_OU", "{\"SEC\":\"ou-abcd-12345678\",\"IS\":\"ou-abcd-23456789\"}")
+	if err != nil {
+		log.Panic("Issue setting env var")
+	}
+
+	m.Run()
+}
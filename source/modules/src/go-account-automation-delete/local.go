@@ -0,0 +1,8 @@
+package deletelambda
+
+import "go-account-automation/internal/automation"
+
+// LocalRoutes are the routes swagger.json sends to this Lambda.
+var LocalRoutes = []automation.LocalRoute{
+	{Method: "DELETE", Resource: "/accounts/{accountId}"},
+}
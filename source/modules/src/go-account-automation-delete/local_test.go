@@ -0,0 +1,29 @@
+package deletelambda
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-account-automation/internal/automation"
+)
+
+func TestLocalServer(t *testing.T) {
+	h, _, accountID := simulatedHandler(t)
+	server := httptest.NewServer(automation.NewLocalServer(automation.LocalLambda{Name: "delete", Handle: h.HandleRequest, Routes: LocalRoutes}))
+	defer server.Close()
+
+	//test that the caller lob header stands in for the authorizer
+	for lob, expectedStatus := range map[string]int{"SEC": http.StatusAccepted, "IS": http.StatusForbidden} {
+		request, _ := http.NewRequest("DELETE", server.URL+"/v1/accounts/"+accountID, nil)
+		request.Header.Set(automation.LocalCallerLobHeader, lob)
+		response, error := http.DefaultClient.Do(request)
+		if error != nil {
+			t.Fatal(error.Error())
+		}
+		response.Body.Close()
+		if response.StatusCode != expectedStatus {
+			t.Fatal("Unexpected status for caller lob ", lob, ": ", response.StatusCode)
+		}
+	}
+}
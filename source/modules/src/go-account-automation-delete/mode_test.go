@@ -0,0 +1,70 @@
+package deletelambda
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/service/organizations"
+
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/fakeorg"
+	"go-account-automation/internal/automation/orgclient"
+)
+
+func closeRequest(accountID string, lob string) events.APIGatewayProxyRequest {
+	request := events.APIGatewayProxyRequest{PathParameters: map[string]string{"accountId": accountID}}
+	request.RequestContext.Authorizer = map[string]interface{}{"lob": lob}
+	return request
+}
+
+// simulatedHandler returns a simulated Handler whose organization holds an active SEC account in the SEC Dev OU.
+func simulatedHandler(t *testing.T) (*Handler, *fakeorg.Client, string) {
+	org, error := orgclient.NewSimulated()
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	h := NewHandler(automation.ModeSimulated, org)
+	dev := org.AddOU("ou-abcd-12345678", "", "Dev")
+	accountID := org.AddAccount(dev, "AWS_SEC_simulated_Dev", "AWS_SEC_simulated_Dev@example.com", map[string]string{"Lob": "SEC", "Env": "Dev"})
+	return h, org, accountID
+}
+
+func TestSimulatedHandler(t *testing.T) {
+	h, org, accountID := simulatedHandler(t)
+
+	//test that the safety checks still run
+	response, _ := h.HandleRequest(closeRequest(accountID, "IS"))
+	if response.StatusCode != 403 {
+		t.Fatal("Unexpected response: ", response.StatusCode, response.Body)
+	}
+
+	response, _ = h.HandleRequest(closeRequest(accountID, "SEC"))
+	if response.StatusCode != 202 || org.Calls("CloseAccount") != 1 {
+		t.Fatal("Unexpected response: ", response.StatusCode, response.Body)
+	}
+
+	//test that the closure is kept by the simulated organization, so the account cannot be closed twice
+	response, _ = h.HandleRequest(closeRequest(accountID, "SEC"))
+	if response.StatusCode != 409 {
+		t.Fatal("A closed account was not expected to be closed again: ", response.StatusCode, response.Body)
+	}
+}
+
+func TestDryRunModeHandler(t *testing.T) {
+	svc, accountID := closureOrg()
+	h := &Handler{Mode: automation.ModeDryRun, Org: automation.ReadOnlyClient{OrganizationsAPI: svc}}
+
+	response, _ := h.HandleRequest(closeRequest(accountID, "SEC"))
+	if response.StatusCode != 200 {
+		t.Fatal("Unexpected response: ", response.StatusCode, response.Body)
+	}
+	var responseBody CloseAccountResponse
+	error := json.Unmarshal([]byte(response.Body), &responseBody)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if !responseBody.DryRun || responseBody.Status != organizations.AccountStatusActive || svc.Calls("CloseAccount") != 0 {
+		t.Fatal("A dry-run handler was expected to leave the account open: ", response.Body)
+	}
+}
@@ -0,0 +1,30 @@
+package readlambda
+
+import (
+	"errors"
+	"flag"
+	"strconv"
+
+	"go-account-automation/internal/automation"
+)
+
+// Commands are the operator commands the account-automation command runs with h for this Lambda's routes.
+func (h *Handler) Commands() map[string]automation.Command {
+	return map[string]automation.Command{
+		"show": h.ShowCommand,
+	}
+}
+
+// ShowCommand returns the account whose ID is its argument, as the get account API does.
+func (h *Handler) ShowCommand(flags *flag.FlagSet) func(args []string) (interface{}, error) {
+	return func(args []string) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, errors.New("error: expected one account id, got " + strconv.Itoa(len(args)))
+		}
+		response, error := RetrieveAccountResponse(h.Org, args[0])
+		if error != nil {
+			return nil, error
+		}
+		return response, nil
+	}
+}
@@ -0,0 +1,49 @@
+package readlambda
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"go-account-automation/internal/automation"
+)
+
+func TestRunCommand(t *testing.T) {
+	svc, accountID := testClient()
+	h := &Handler{Mode: automation.ModeLive, Org: svc}
+
+	testCases := []struct {
+		name           string
+		args           []string
+		expectedStatus int
+		expectedOutput string
+	}{
+		{"unknown command", []string{"list"}, 2, ""},
+		{"missing account id", []string{"show"}, 1, ""},
+		{"show", []string{"show", accountID}, 0, "ouPath: /Workloads/DEV/SEC"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			status := automation.RunCommand(h.Commands(), testCase.args, &stdout, &stderr)
+			if status != testCase.expectedStatus {
+				t.Fatal("Unexpected exit status: ", status, stdout.String(), stderr.String())
+			}
+			if !strings.Contains(stdout.String(), testCase.expectedOutput) {
+				t.Fatal("Unexpected output: ", stdout.String(), stderr.String())
+			}
+		})
+	}
+
+	//test that -json writes the account as the get account API returns it
+	var stdout bytes.Buffer
+	if automation.RunCommand(h.Commands(), []string{"show", "-json", accountID}, &stdout, ioutil.Discard) != 0 {
+		t.Fatal("Show was expected to succeed")
+	}
+	var response AccountResponse
+	if error := json.Unmarshal(stdout.Bytes(), &response); error != nil || response.Lob != "SEC" || response.OUID != "ou-abcd-33333333" {
+		t.Fatal("Account was not written as JSON: ", stdout.String())
+	}
+}
@@ -0,0 +1,139 @@
+package readlambda
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+
+	"go-account-automation/internal/automation"
+)
+
+// AccountResponse is the account as the create Lambda provisioned it, along with where it is now.
+type AccountResponse struct {
+	automation.AccountPayload
+	Email  string `json:"email"`
+	Status string `json:"status"`
+	OUID   string `json:"ouId,omitempty"`
+	OUPath string `json:"ouPath,omitempty"`
+}
+
+func DescribeAccount(svc organizationsiface.OrganizationsAPI, accountID string) (*organizations.Account, error) {
+	account, error := svc.DescribeAccount(&organizations.DescribeAccountInput{AccountId: &accountID})
+	if error != nil {
+		return nil, error
+	}
+	return account.Account, nil
+}
+
+// RetrieveOUPath walks the account's parents up to the root and returns the ID of the OU the account
+// is in and the path of OU names to it, such as /Workloads/DEV/SEC.
+func RetrieveOUPath(svc organizationsiface.OrganizationsAPI, accountID string) (string, string, error) {
+	var ouID string
+	var names []string
+	childID := accountID
+	for {
+		parents, error := svc.ListParents(&organizations.ListParentsInput{ChildId: &childID})
+		if error != nil {
+			return "", "", error
+		}
+		if len(parents.Parents) == 0 {
+			return "", "", errors.New("error: no parent found for " + childID)
+		}
+
+		parent := parents.Parents[0]
+		if ouID == "" {
+			ouID = aws.StringValue(parent.Id)
+		}
+		if aws.StringValue(parent.Type) == organizations.ParentTypeRoot {
+			break
+		}
+
+		ou, error := svc.DescribeOrganizationalUnit(&organizations.DescribeOrganizationalUnitInput{OrganizationalUnitId: parent.Id})
+		if error != nil {
+			return "", "", error
+		}
+		names = append([]string{aws.StringValue(ou.OrganizationalUnit.Name)}, names...)
+		childID = aws.StringValue(parent.Id)
+	}
+
+	return ouID, "/" + strings.Join(names, "/"), nil
+}
+
+// RetrieveAccountResponse describes the account, rebuilds its payload from its tags and finds the OU it is in.
+func RetrieveAccountResponse(svc organizationsiface.OrganizationsAPI, accountID string) (*AccountResponse, error) {
+	log.Println("Describing account...")
+	account, error := DescribeAccount(svc, accountID)
+	if error != nil {
+		return nil, error
+	}
+
+	log.Println("Listing account tags...")
+	tags, error := automation.ListAccountTags(svc, accountID)
+	if error != nil {
+		return nil, error
+	}
+
+	log.Println("Retrieving account OU path...")
+	ouID, ouPath, error := RetrieveOUPath(svc, accountID)
+	if error != nil {
+		return nil, error
+	}
+
+	log.Println("Rebuilding payload from tags...")
+	payload := automation.PayloadFromTags(accountID, tags)
+	if payload.Name == "" {
+		payload.Name = aws.StringValue(account.Name)
+	}
+
+	response := &AccountResponse{
+		AccountPayload: payload,
+		Email:          aws.StringValue(account.Email),
+		Status:         aws.StringValue(account.Status),
+		OUID:           ouID,
+		OUPath:         ouPath,
+	}
+	return response, nil
+}
+
+func (h *Handler) HandleGetRequest(request events.APIGatewayProxyRequest) (*events.APIGatewayProxyResponse, error) {
+	svc := h.Org
+
+	accountID := request.PathParameters["accountId"]
+	if accountID == "" {
+		return automation.HandleErrors(errors.New("error: account id is required"), 400)
+	}
+
+	responseBody, error := RetrieveAccountResponse(svc, accountID)
+	if error != nil {
+		return automation.HandleErrors(error, 500)
+	}
+
+	log.Println("Stringifying response body...")
+	jsonResponseBody, error := json.Marshal(responseBody)
+	if error != nil {
+		return automation.HandleErrors(error, 500)
+	}
+	log.Println("Response payload: ", *responseBody)
+
+	response := &events.APIGatewayProxyResponse{
+		StatusCode: 200,
+		Body:       string(jsonResponseBody),
+	}
+	return response, nil
+}
+
+// HandleRequest routes the request to the handler for its API GW resource.
+func (h *Handler) HandleRequest(request events.APIGatewayProxyRequest) (*events.APIGatewayProxyResponse, error) {
+	switch request.Resource {
+	case "/accounts":
+		return h.HandleListRequest(request)
+	default:
+		return h.HandleGetRequest(request)
+	}
+}
@@ -0,0 +1,125 @@
+package readlambda
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/google/go-cmp/cmp"
+
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/fakeorg"
+)
+
+// testTags returns the tags of the account testClient holds.
+func testTags() map[string]string {
+	return map[string]string{
+		"Name":          "AWS_SEC_test_Dev",
+		"CostCenter":    "01234",
+		"AccountPOC":    "john.doe@example.com",
+		"ApplicationID": "00000000-0000-0000-0000-000000000000",
+		"Env":           "DEV",
+		"Lob":           "SEC",
+	}
+}
+
+// testClient returns an organization holding the account AWS_SEC_test_Dev, tagged with testTags, in its
+// Workloads/DEV/SEC OU, and the ID of that account.
+func testClient() (*fakeorg.Client, string) {
+	svc := fakeorg.NewClient()
+	svc.AddOU(fakeorg.RootID, "ou-abcd-11111111", "Workloads")
+	svc.AddOU("ou-abcd-11111111", "ou-abcd-22222222", "DEV")
+	svc.AddOU("ou-abcd-22222222", "ou-abcd-33333333", "SEC")
+	accountID := svc.AddAccount("ou-abcd-33333333", "AWS_SEC_test_Dev", "AWS_SEC_test_Dev@example.com", testTags())
+	return svc, accountID
+}
+
+func TestListAccountTags(t *testing.T) {
+	svc, accountID := testClient()
+	tags, error := automation.ListAccountTags(svc, accountID)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if !cmp.Equal(tags, testTags()) {
+		t.Fatal("Account tags were not as expected: ", cmp.Diff(testTags(), tags))
+	}
+}
+
+func TestPayloadFromTags(t *testing.T) {
+	expectedPayload := automation.AccountPayload{
+		Name:          "AWS_SEC_test_Dev",
+		CostCenter:    "01234",
+		AccountPOC:    "john.doe@example.com",
+		ApplicationID: "00000000-0000-0000-0000-000000000000",
+		Env:           "DEV",
+		Lob:           "SEC",
+		AccountID:     "999999999999",
+	}
+	tags := testTags()
+	tags["Unrelated"] = "ignored"
+
+	payload := automation.PayloadFromTags("999999999999", tags)
+	if payload != expectedPayload {
+		t.Fatal("Payload rebuilt from tags was not as expected: ", cmp.Diff(expectedPayload, payload))
+	}
+}
+
+func TestRetrieveOUPath(t *testing.T) {
+	svc, accountID := testClient()
+	ouID, ouPath, error := RetrieveOUPath(svc, accountID)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if ouID != "ou-abcd-33333333" || ouPath != "/Workloads/DEV/SEC" {
+		t.Fatal("OU path was not as expected: ", ouID, ouPath)
+	}
+
+	//test an account directly under the root
+	rootAccountID := svc.AddAccount(fakeorg.RootID, "AWS_SEC_root_Dev", "AWS_SEC_root_Dev@example.com", nil)
+	ouID, ouPath, error = RetrieveOUPath(svc, rootAccountID)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if ouID != fakeorg.RootID || ouPath != "/" {
+		t.Fatal("OU path for an account in the root was not as expected: ", ouID, ouPath)
+	}
+}
+
+func TestAccountResponse(t *testing.T) {
+	response := AccountResponse{
+		AccountPayload: automation.PayloadFromTags("999999999999", testTags()),
+		Status:         organizations.AccountStatusActive,
+		OUPath:         "/Workloads/DEV/SEC",
+	}
+	jsonResponse, error := json.Marshal(response)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+
+	var fields map[string]string
+	error = json.Unmarshal(jsonResponse, &fields)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if fields["lob"] != "SEC" || fields["accountId"] != "999999999999" || fields["ouPath"] != "/Workloads/DEV/SEC" || fields["status"] != "ACTIVE" {
+		t.Fatal("Response body was not as expected: ", string(jsonResponse))
+	}
+}
+
+func TestMain(m *testing.M) {
+	var err error
+
+	err = os.Setenv("WORKLOAD_OU", "ou-abcd-11111111")
+	if err != nil {
+		log.Panic("Issue setting env var")
+	}
+
+	err = os.Setenv("SEC_OU", "{\"IS\":\"ou-abcd-99999999\"}")
+	if err != nil {
+		log.Panic("Issue setting env var")
+	}
+
+	m.Run()
+}
@@ -0,0 +1,205 @@
+package readlambda
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+
+	"go-account-automation/internal/automation"
+)
+
+const defaultMaxResults = 20
+
+// maxAccountsPage is the most accounts Organizations returns in one page of ListAccounts or ListAccountsForParent.
+const maxAccountsPage = 20
+
+// MaxScannedAccounts is the most accounts one call to FilterAccounts reads the tags of. Each account costs a
+// ListTagsForResource call, so a filter few accounts match returns a short or empty page with a nextToken instead of
+// reading every account in the organization.
+var MaxScannedAccounts = 100
+
+// ListFilters maps the query string parameters GET /accounts filters on to the tag keys GenerateTags writes.
+var ListFilters = map[string]string{
+	"lob":           "Lob",
+	"env":           "Env",
+	"costCenter":    "CostCenter",
+	"applicationId": "ApplicationID",
+}
+
+type ListAccountsResponse struct {
+	Accounts  []AccountResponse `json:"accounts"`
+	NextToken string            `json:"nextToken,omitempty"`
+}
+
+// RetrieveFilters returns the tag filters in the query string, keyed by tag key.
+func RetrieveFilters(queryStringParameters map[string]string) map[string]string {
+	filters := map[string]string{}
+	for parameter, tagKey := range ListFilters {
+		if value := queryStringParameters[parameter]; value != "" {
+			filters[tagKey] = value
+		}
+	}
+	return filters
+}
+
+func MatchesFilters(tags map[string]string, filters map[string]string) bool {
+	for key, value := range filters {
+		if !strings.EqualFold(tags[key], value) {
+			return false
+		}
+	}
+	return true
+}
+
+// EncodeNextToken makes an Organizations pagination token safe to pass back in a query string.
+func EncodeNextToken(token *string) string {
+	if token == nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(*token))
+}
+
+func DecodeNextToken(token string) (*string, error) {
+	if token == "" {
+		return nil, nil
+	}
+	decoded, error := base64.RawURLEncoding.DecodeString(token)
+	if error != nil {
+		return nil, errors.New("error: nextToken is not valid")
+	}
+	return aws.String(string(decoded)), nil
+}
+
+func listAccountsPage(svc organizationsiface.OrganizationsAPI, parentID string, nextToken *string, maxResults int64) ([]*organizations.Account, *string, error) {
+	if parentID != "" {
+		output, error := svc.ListAccountsForParent(&organizations.ListAccountsForParentInput{ParentId: &parentID, NextToken: nextToken, MaxResults: &maxResults})
+		if error != nil {
+			return nil, nil, error
+		}
+		return output.Accounts, output.NextToken, nil
+	}
+
+	output, error := svc.ListAccounts(&organizations.ListAccountsInput{NextToken: nextToken, MaxResults: &maxResults})
+	if error != nil {
+		return nil, nil, error
+	}
+	return output.Accounts, output.NextToken, nil
+}
+
+// FilterAccounts pages through the accounts under parentID, or the whole organization when parentID is empty,
+// starting at nextToken. It stops once the matches reach maxResults, or once it has scanned MaxScannedAccounts
+// accounts, and returns the token for the page after it. Each page asked of Organizations is no bigger than the
+// matches still needed, so a page of results never holds more than maxResults accounts, and can hold none at all when
+// none of the accounts scanned match.
+func FilterAccounts(svc organizationsiface.OrganizationsAPI, parentID string, filters map[string]string, nextToken *string, maxResults int) ([]AccountResponse, *string, error) {
+	matches := []AccountResponse{}
+	scanned := 0
+	for {
+		pageSize := MaxScannedAccounts - scanned
+		if pageSize > maxAccountsPage {
+			pageSize = maxAccountsPage
+		}
+		// every account of the page may match, and the token only resumes after the whole page
+		if pageSize > maxResults-len(matches) {
+			pageSize = maxResults - len(matches)
+		}
+		accounts, pageToken, error := listAccountsPage(svc, parentID, nextToken, int64(pageSize))
+		if error != nil {
+			return nil, nil, error
+		}
+		scanned += len(accounts)
+
+		for _, account := range accounts {
+			accountID := aws.StringValue(account.Id)
+			tags, error := automation.ListAccountTags(svc, accountID)
+			if error != nil {
+				return nil, nil, error
+			}
+			if !MatchesFilters(tags, filters) {
+				continue
+			}
+
+			payload := automation.PayloadFromTags(accountID, tags)
+			if payload.Name == "" {
+				payload.Name = aws.StringValue(account.Name)
+			}
+			matches = append(matches, AccountResponse{
+				AccountPayload: payload,
+				Email:          aws.StringValue(account.Email),
+				Status:         aws.StringValue(account.Status),
+				OUID:           parentID,
+			})
+		}
+
+		nextToken = pageToken
+		if nextToken == nil || len(matches) >= maxResults || scanned >= MaxScannedAccounts {
+			return matches, nextToken, nil
+		}
+	}
+}
+
+func (h *Handler) HandleListRequest(request events.APIGatewayProxyRequest) (*events.APIGatewayProxyResponse, error) {
+	svc := h.Org
+
+	log.Println("Reading filters and pagination from query string...")
+	filters := RetrieveFilters(request.QueryStringParameters)
+	nextToken, error := DecodeNextToken(request.QueryStringParameters["nextToken"])
+	if error != nil {
+		return automation.HandleErrors(error, 400)
+	}
+	maxResults := defaultMaxResults
+	if value, ok := request.QueryStringParameters["maxResults"]; ok {
+		maxResults, error = strconv.Atoi(value)
+		if error != nil || maxResults < 1 || maxResults > 100 {
+			return automation.HandleErrors(errors.New("error: maxResults must be a number between 1 and 100"), 400)
+		}
+	}
+
+	// Both lob and env pin down a single OU, so only the accounts directly under it need to be listed. Placement rules
+	// can also place accounts on their other attributes, so with PLACEMENT_RULES set the whole org is listed instead.
+	var parentID string
+	if filters["Lob"] != "" && filters["Env"] != "" && os.Getenv("PLACEMENT_RULES") == "" {
+		log.Println("Retrieving OU ID based on lob and env filters...")
+		var root string
+		root, parentID, error = automation.RetrieveOUs(svc, h.Tree, automation.AccountPayload{Lob: filters["Lob"], Env: filters["Env"]})
+		if error != nil {
+			return automation.HandleErrors(error, 500)
+		}
+		if parentID == "" {
+			log.Println("No OU found for lob and env under root ", root, ", no accounts can match")
+			return ListResponse(ListAccountsResponse{Accounts: []AccountResponse{}})
+		}
+	}
+
+	log.Println("Listing accounts...")
+	accounts, nextToken, error := FilterAccounts(svc, parentID, filters, nextToken, maxResults)
+	if error != nil {
+		return automation.HandleErrors(error, 500)
+	}
+
+	return ListResponse(ListAccountsResponse{Accounts: accounts, NextToken: EncodeNextToken(nextToken)})
+}
+
+func ListResponse(responseBody ListAccountsResponse) (*events.APIGatewayProxyResponse, error) {
+	log.Println("Stringifying response body...")
+	jsonResponseBody, error := json.Marshal(responseBody)
+	if error != nil {
+		return automation.HandleErrors(error, 500)
+	}
+	log.Println("Response accounts: ", len(responseBody.Accounts))
+
+	response := &events.APIGatewayProxyResponse{
+		StatusCode: 200,
+		Body:       string(jsonResponseBody),
+	}
+	return response, nil
+}
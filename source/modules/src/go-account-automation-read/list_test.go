@@ -0,0 +1,186 @@
+package readlambda
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/fakeorg"
+)
+
+// listClient returns an organization with a Workloads/DEV/{SEC,APP} tree holding five accounts, two accounts per
+// page, and the IDs of the accounts in the order they are listed.
+func listClient() (*fakeorg.Client, []string) {
+	svc := fakeorg.NewClient()
+	svc.PageSize = 2
+	svc.AddOU(fakeorg.RootID, "ou-abcd-11111111", "Workloads")
+	svc.AddOU("ou-abcd-11111111", "ou-abcd-22222222", "DEV")
+	svc.AddOU("ou-abcd-22222222", "ou-abcd-33333333", "SEC")
+	svc.AddOU("ou-abcd-22222222", "ou-abcd-44444444", "APP")
+
+	accounts := []struct {
+		parent, lob, env, costCenter string
+	}{
+		{"ou-abcd-33333333", "SEC", "DEV", "01234"},
+		{"ou-abcd-44444444", "APP", "DEV", "01234"},
+		{"ou-abcd-33333333", "SEC", "DEV", "56789"},
+		{fakeorg.RootID, "SEC", "PROD", "01234"},
+		{"ou-abcd-33333333", "SEC", "DEV", "01234"},
+	}
+	var accountIDs []string
+	for i, account := range accounts {
+		name := "aws_" + account.lob + "_list" + strconv.Itoa(i) + "_" + account.env
+		accountIDs = append(accountIDs, svc.AddAccount(account.parent, name, name+"@example.com", map[string]string{"Lob": account.lob, "Env": account.env, "CostCenter": account.costCenter}))
+	}
+	return svc, accountIDs
+}
+
+func listAll(t *testing.T, svc *fakeorg.Client, parentID string, filters map[string]string, maxResults int) []string {
+	var accountIDs []string
+	var nextToken *string
+	for {
+		accounts, pageToken, error := FilterAccounts(svc, parentID, filters, nextToken, maxResults)
+		if error != nil {
+			t.Fatal(error.Error())
+		}
+		if len(accounts) > maxResults {
+			t.Fatal("Page held more than maxResults accounts: ", len(accounts))
+		}
+		for _, account := range accounts {
+			accountIDs = append(accountIDs, account.AccountID)
+		}
+
+		//round trip the token the way a caller would
+		nextToken, error = DecodeNextToken(EncodeNextToken(pageToken))
+		if error != nil {
+			t.Fatal(error.Error())
+		}
+		if nextToken == nil {
+			return accountIDs
+		}
+	}
+}
+
+func TestRetrieveFilters(t *testing.T) {
+	filters := RetrieveFilters(map[string]string{"lob": "SEC", "costCenter": "01234", "maxResults": "5"})
+	expectedFilters := map[string]string{"Lob": "SEC", "CostCenter": "01234"}
+	if !cmp.Equal(filters, expectedFilters) {
+		t.Fatal("Filters were not as expected: ", filters)
+	}
+}
+
+func TestFilterAccounts(t *testing.T) {
+	svc, ids := listClient()
+
+	accountIDs := listAll(t, svc, "", map[string]string{"CostCenter": "01234"}, 1)
+	expectedIDs := []string{ids[0], ids[1], ids[3], ids[4]}
+	if !cmp.Equal(accountIDs, expectedIDs) {
+		t.Fatal("Accounts listed across pages were not as expected: ", accountIDs)
+	}
+
+	//test that filters are matched case-insensitively and combined
+	accountIDs = listAll(t, svc, "", map[string]string{"Lob": "sec", "Env": "dev"}, 20)
+	expectedIDs = []string{ids[0], ids[2], ids[4]}
+	if !cmp.Equal(accountIDs, expectedIDs) {
+		t.Fatal("Filtered accounts were not as expected: ", accountIDs)
+	}
+
+	//test listing only the accounts under an OU
+	accountIDs = listAll(t, svc, "ou-abcd-33333333", map[string]string{"CostCenter": "01234"}, 20)
+	expectedIDs = []string{ids[0], ids[4]}
+	if !cmp.Equal(accountIDs, expectedIDs) {
+		t.Fatal("Accounts listed under the OU were not as expected: ", accountIDs)
+	}
+}
+
+func TestFilterAccountsPageSize(t *testing.T) {
+	svc, ids := listClient()
+	svc.PageSize = 0
+
+	//test that each page of Organizations is no bigger than the matches still needed, so no page holds more than maxResults
+	accounts, nextToken, error := FilterAccounts(svc, "", map[string]string{"CostCenter": "01234"}, nil, 3)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if len(accounts) != 3 || nextToken == nil || *nextToken != "4" {
+		t.Fatal("Expected a page of three accounts ending at the third match: ", accounts, nextToken)
+	}
+	for _, maxResults := range []int{1, 2, 3} {
+		accountIDs := listAll(t, svc, "", map[string]string{"CostCenter": "01234"}, maxResults)
+		if !cmp.Equal(accountIDs, []string{ids[0], ids[1], ids[3], ids[4]}) {
+			t.Fatal("Accounts listed in pages of ", maxResults, " were not as expected: ", accountIDs)
+		}
+	}
+}
+
+func TestFilterAccountsScanLimit(t *testing.T) {
+	defer func(maxScannedAccounts int) { MaxScannedAccounts = maxScannedAccounts }(MaxScannedAccounts)
+	MaxScannedAccounts = 2
+	svc, ids := listClient()
+	svc.PageSize = 0
+
+	//test that a filter few accounts match stops after the scan limit with a token to carry on from
+	accounts, nextToken, error := FilterAccounts(svc, "", map[string]string{"CostCenter": "56789"}, nil, 20)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if len(accounts) != 0 || nextToken == nil || *nextToken != "2" {
+		t.Fatal("Only the first two accounts were expected to be scanned: ", accounts, nextToken)
+	}
+
+	//test that following the tokens still finds every match
+	accountIDs := listAll(t, svc, "", map[string]string{"CostCenter": "56789"}, 20)
+	if !cmp.Equal(accountIDs, []string{ids[2]}) {
+		t.Fatal("Accounts listed within the scan limit were not as expected: ", accountIDs)
+	}
+}
+
+func TestRetrieveOUs(t *testing.T) {
+	svc, _ := listClient()
+	root, ou, error := automation.RetrieveOUs(svc, nil, automation.AccountPayload{Lob: "APP", Env: "Dev"})
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if root != fakeorg.RootID || ou != "ou-abcd-44444444" {
+		t.Fatal("RetrieveOUs output not as expected: ", root, ou)
+	}
+
+	//test that OUs past the first page of ListOrganizationalUnitsForParent are found
+	svc.PageSize = 1
+	root, ou, error = automation.RetrieveOUs(svc, nil, automation.AccountPayload{Lob: "APP", Env: "Dev"})
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if root != fakeorg.RootID || ou != "ou-abcd-44444444" {
+		t.Fatal("RetrieveOUs output not as expected across pages: ", root, ou)
+	}
+
+	//test that the workload OU can be configured by path instead of ID
+	defer os.Setenv("WORKLOAD_OU", os.Getenv("WORKLOAD_OU"))
+	os.Setenv("WORKLOAD_OU", "")
+	os.Setenv("WORKLOAD_OU_PATH", "/workloads")
+	defer os.Unsetenv("WORKLOAD_OU_PATH")
+	root, ou, error = automation.RetrieveOUs(svc, nil, automation.AccountPayload{Lob: "app", Env: "dev"})
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if root != fakeorg.RootID || ou != "ou-abcd-44444444" {
+		t.Fatal("RetrieveOUs output not as expected by path: ", root, ou)
+	}
+
+	//test that a missing env OU is not an error
+	_, ou, error = automation.RetrieveOUs(svc, nil, automation.AccountPayload{Lob: "APP", Env: "Lab"})
+	if error != nil || ou != "" {
+		t.Fatal("RetrieveOUs was expected to find no OU: ", ou, error)
+	}
+}
+
+func TestDecodeNextToken(t *testing.T) {
+	_, error := DecodeNextToken("not base64!")
+	if error == nil {
+		t.Fatal("An invalid token was expected to fail but didn't")
+	}
+}
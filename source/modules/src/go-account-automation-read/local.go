@@ -0,0 +1,9 @@
+package readlambda
+
+import "go-account-automation/internal/automation"
+
+// LocalRoutes are the routes swagger.json sends to this Lambda.
+var LocalRoutes = []automation.LocalRoute{
+	{Method: "GET", Resource: "/accounts"},
+	{Method: "GET", Resource: "/accounts/{accountId}"},
+}
@@ -0,0 +1,41 @@
+package readlambda
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-account-automation/internal/automation"
+)
+
+func TestLocalServer(t *testing.T) {
+	h, accountID := simulatedHandler(t)
+	server := httptest.NewServer(automation.NewLocalServer(automation.LocalLambda{Name: "read", Handle: h.HandleRequest, Routes: LocalRoutes}))
+	defer server.Close()
+
+	response, error := http.Get(server.URL + "/v1/accounts/" + accountID)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	body, _ := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	var account AccountResponse
+	json.Unmarshal(body, &account)
+	if response.StatusCode != 200 || account.AccountID != accountID {
+		t.Fatal("Unexpected response: ", response.StatusCode, string(body))
+	}
+
+	response, error = http.Get(server.URL + "/v1/accounts?lob=SEC")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	body, _ = ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	var list ListAccountsResponse
+	json.Unmarshal(body, &list)
+	if response.StatusCode != 200 || len(list.Accounts) != 1 {
+		t.Fatal("Unexpected list response: ", response.StatusCode, string(body))
+	}
+}
@@ -0,0 +1,58 @@
+package readlambda
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/orgclient"
+)
+
+// simulatedHandler returns a simulated Handler whose organization holds a SEC account in the Workloads/Dev/SEC OU.
+func simulatedHandler(t *testing.T) (*Handler, string) {
+	org, error := orgclient.NewSimulated()
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	h := NewHandler(automation.ModeSimulated, org)
+	sec := org.AddOU(org.AddOU("ou-abcd-11111111", "", "Dev"), "", "SEC")
+	accountID := org.AddAccount(sec, "AWS_SEC_simulated_Dev", "AWS_SEC_simulated_Dev@example.com", map[string]string{"Lob": "SEC", "Env": "Dev"})
+	return h, accountID
+}
+
+func TestSimulatedHandler(t *testing.T) {
+	h, accountID := simulatedHandler(t)
+
+	request := events.APIGatewayProxyRequest{Resource: "/accounts/{accountId}", PathParameters: map[string]string{"accountId": accountID}}
+	response, _ := h.HandleRequest(request)
+	if response.StatusCode != 200 {
+		t.Fatal("Unexpected response: ", response.StatusCode, response.Body)
+	}
+	var account AccountResponse
+	error := json.Unmarshal([]byte(response.Body), &account)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if account.AccountID != accountID || account.Lob != "SEC" || account.OUPath != "/Workloads/Dev/SEC" {
+		t.Fatal("Unexpected simulated account: ", response.Body)
+	}
+
+	response, _ = h.HandleRequest(events.APIGatewayProxyRequest{Resource: "/accounts"})
+	var list ListAccountsResponse
+	error = json.Unmarshal([]byte(response.Body), &list)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if len(list.Accounts) != 1 || list.Accounts[0].AccountID != accountID {
+		t.Fatal("Unexpected simulated accounts: ", response.Body)
+	}
+
+	//test that an account the simulated organization does not hold is not found
+	request.PathParameters["accountId"] = "999999999999"
+	response, _ = h.HandleRequest(request)
+	if response.StatusCode != 404 {
+		t.Fatal("An unknown account was expected to be not found: ", response.StatusCode, response.Body)
+	}
+}
@@ -0,0 +1,86 @@
+package updatelambda
+
+import (
+	"errors"
+	"flag"
+
+	"go-account-automation/internal/automation"
+)
+
+// Commands are the operator commands the account-automation command runs with h for this Lambda's routes.
+func (h *Handler) Commands() map[string]automation.Command {
+	return map[string]automation.Command{
+		"update": h.UpdateCommand,
+		"move":   h.MoveCommand,
+	}
+}
+
+// UpdateCommand rewrites the account's tags from the payload, as the update API does, and returns the payload.
+// With -dry-run, or in dry-run mode, it returns the plan instead.
+func (h *Handler) UpdateCommand(flags *flag.FlagSet) func(args []string) (interface{}, error) {
+	accountID := flags.String("account-id", "", "ID of the account to update")
+	file := flags.String("f", "-", "file holding the account payload as JSON, - for stdin")
+	dryRun := flags.Bool("dry-run", false, "return the plan without making changes")
+
+	return func(args []string) (interface{}, error) {
+		if *accountID == "" {
+			return nil, errors.New("error: account id is required")
+		}
+		payload, error := automation.ReadCommandPayload(*file)
+		if error != nil {
+			return nil, error
+		}
+		error = ValidateUpdate(h.Org, *accountID, payload)
+		if error != nil {
+			return nil, error
+		}
+		keys, tags := automation.GenerateKeysAndTags(payload)
+
+		if h.Mode == automation.ModeDryRun || *dryRun {
+			plan, error := PlanUpdate(automation.ReadOnlyClient{OrganizationsAPI: h.Org}, *accountID, keys, tags)
+			if error != nil {
+				return nil, error
+			}
+			return plan, nil
+		}
+		error = automation.UntagAccount(h.Org, keys, *accountID)
+		if error != nil {
+			return nil, error
+		}
+		error = automation.TagAccount(h.Org, tags, *accountID)
+		if error != nil {
+			return nil, error
+		}
+		payload.AccountID = *accountID
+		return payload, nil
+	}
+}
+
+// MoveCommand moves the account to the OU for a new lob or env, as the move API does, and returns the move.
+// With -dry-run, or in dry-run mode, it returns the plan instead.
+func (h *Handler) MoveCommand(flags *flag.FlagSet) func(args []string) (interface{}, error) {
+	accountID := flags.String("account-id", "", "ID of the account to move")
+	var moveRequest MoveRequest
+	flags.StringVar(&moveRequest.Env, "env", "", "env to move the account to")
+	flags.StringVar(&moveRequest.Lob, "lob", "", "lob to move the account to")
+	dryRun := flags.Bool("dry-run", false, "return the plan without making changes")
+
+	return func(args []string) (interface{}, error) {
+		if *accountID == "" {
+			return nil, errors.New("error: account id is required")
+		}
+		if h.Mode == automation.ModeDryRun || *dryRun {
+			plan, _, error := PlanMove(automation.ReadOnlyClient{OrganizationsAPI: h.Org}, h.Tree, *accountID, moveRequest, "")
+			if error != nil {
+				return nil, error
+			}
+			plan.DryRun = true
+			return plan, nil
+		}
+		response, error := MoveAccountTo(h.Org, h.Tree, *accountID, moveRequest, "")
+		if error != nil {
+			return nil, error
+		}
+		return response, nil
+	}
+}
@@ -0,0 +1,70 @@
+package updatelambda
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/fakeorg"
+)
+
+func TestRunCommand(t *testing.T) {
+	svc := fakeorg.NewClient()
+	svc.AddOU(fakeorg.RootID, "ou-abcd-01234567", "Workloads")
+	lab := svc.AddOUPath("/Workloads/Lab/APP")
+	dev := svc.AddOUPath("/Workloads/Dev/APP")
+	accountID := svc.AddAccount(lab, "AWS_APP_test_Lab", "AWS_APP_test_Lab@example.com", map[string]string{"Name": "AWS_APP_test_Lab", "CostCenter": "01234", "Env": "Lab", "Lob": "APP"})
+	h := &Handler{Mode: automation.ModeLive, Org: svc}
+
+	file, error := ioutil.TempFile("", "payload-*.json")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	defer os.Remove(file.Name())
+	file.WriteString(`{"name":"AWS_APP_test_Lab","costCenter":"56789","accountPOC":"john.doe@example.com","applicationId":"00000000-0000-0000-0000-000000000000","env":"Lab","lob":"APP"}`)
+	file.Close()
+
+	testCases := []struct {
+		name           string
+		args           []string
+		expectedStatus int
+		expectedOutput string
+	}{
+		{"unknown command", []string{"show"}, 2, ""},
+		{"missing account id", []string{"move", "-env", "Dev"}, 1, ""},
+		{"update dry-run", []string{"update", "-account-id", accountID, "-dry-run", "-f", file.Name()}, 0, "tags.change.CostCenter.to: 56789"},
+		{"move dry-run", []string{"move", "-account-id", accountID, "-env", "Dev", "-dry-run"}, 0, "destinationOu.id: " + dev},
+		{"move without env or lob", []string{"move", "-account-id", accountID}, 1, ""},
+		{"update", []string{"update", "-account-id", accountID, "-f", file.Name()}, 0, "costCenter: 56789"},
+		{"move", []string{"move", "-account-id", accountID, "-env", "Dev"}, 0, "destinationOu: " + dev},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			status := automation.RunCommand(h.Commands(), testCase.args, &stdout, &stderr)
+			if status != testCase.expectedStatus {
+				t.Fatal("Unexpected exit status: ", status, stdout.String(), stderr.String())
+			}
+			if !strings.Contains(stdout.String(), testCase.expectedOutput) {
+				t.Fatal("Unexpected output: ", stdout.String(), stderr.String())
+			}
+		})
+	}
+	if svc.Parent(accountID) != dev || svc.Tags(accountID)["CostCenter"] != "56789" || svc.Tags(accountID)["Env"] != "Dev" {
+		t.Fatal("Account was not updated and moved: ", svc.Parent(accountID), svc.Tags(accountID))
+	}
+
+	//test that -json writes the result as JSON
+	var stdout bytes.Buffer
+	if automation.RunCommand(h.Commands(), []string{"move", "-json", "-account-id", accountID, "-lob", "APP", "-dry-run"}, &stdout, ioutil.Discard) != 0 {
+		t.Fatal("Move plan was expected to succeed")
+	}
+	var plan automation.Plan
+	if error := json.Unmarshal(stdout.Bytes(), &plan); error != nil || !plan.DryRun || plan.SourceOU != dev {
+		t.Fatal("Plan was not written as JSON: ", stdout.String())
+	}
+}
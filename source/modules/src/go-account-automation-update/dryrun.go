@@ -0,0 +1,55 @@
+package updatelambda
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+
+	"go-account-automation/internal/automation"
+)
+
+// PlanUpdate returns the changes untagging the keys and then tagging the account with tags would make.
+func PlanUpdate(svc organizationsiface.OrganizationsAPI, accountID string, keys []*string, tags []*organizations.Tag) (*automation.Plan, error) {
+	account, error := svc.DescribeAccount(&organizations.DescribeAccountInput{AccountId: &accountID})
+	if error != nil {
+		return nil, error
+	}
+	current, error := automation.ListAccountTags(svc, accountID)
+	if error != nil {
+		return nil, error
+	}
+
+	plan := &automation.Plan{
+		DryRun:    true,
+		AccountID: accountID,
+		Name:      aws.StringValue(account.Account.Name),
+		Email:     aws.StringValue(account.Account.Email),
+		Tags:      automation.DiffTags(current, aws.StringValueSlice(keys), automation.TagMap(tags)),
+	}
+	return plan, nil
+}
+
+func HandleUpdateDryRun(svc organizationsiface.OrganizationsAPI, accountID string, keys []*string, tags []*organizations.Tag) (*events.APIGatewayProxyResponse, error) {
+	log.Println("Dry run requested, planning the update without making changes...")
+	plan, error := PlanUpdate(svc, accountID, keys, tags)
+	if error != nil {
+		return automation.HandleErrors(error, 500)
+	}
+
+	log.Println("Stringifying response body...")
+	jsonResponseBody, error := json.Marshal(plan)
+	if error != nil {
+		return automation.HandleErrors(error, 500)
+	}
+	log.Println("Plan: ", string(jsonResponseBody))
+
+	response := &events.APIGatewayProxyResponse{
+		StatusCode: 200,
+		Body:       string(jsonResponseBody),
+	}
+	return response, nil
+}
@@ -0,0 +1,66 @@
+package updatelambda
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go-account-automation/internal/automation"
+)
+
+func TestPlanUpdate(t *testing.T) {
+	org, accountID := secOrg("AWS_SEC_test_Dev", map[string]string{"Name": "AWS_SEC_test_Dev", "CostCenter": "01234", "Env": "Dev", "Lob": "SEC", "Owner": "ops"})
+	svc := automation.ReadOnlyClient{OrganizationsAPI: org}
+	payload := automation.AccountPayload{Name: "AWS_SEC_test_Dev", CostCenter: "56789", AccountPOC: "john.doe@example.com", Env: "Dev", Lob: "SEC"}
+	keys, tags := automation.GenerateKeysAndTags(payload)
+
+	plan, error := PlanUpdate(svc, accountID, keys, tags)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	expectedPlan := &automation.Plan{
+		DryRun:    true,
+		AccountID: accountID,
+		Name:      "AWS_SEC_test_Dev",
+		Email:     "AWS_SEC_test_Dev@example.com",
+		Tags: automation.TagChanges{
+			Add:    map[string]string{"AccountPOC": "john.doe@example.com", "ApplicationID": ""},
+			Change: map[string]automation.TagChange{"CostCenter": {From: "01234", To: "56789"}},
+		},
+	}
+	if !cmp.Equal(plan, expectedPlan) {
+		t.Fatal("Unexpected plan: ", cmp.Diff(expectedPlan, plan))
+	}
+}
+
+func TestPlanMove(t *testing.T) {
+	org, accountID := secOrg("AWS_SEC_test_Lab", map[string]string{"Env": "Lab", "Lob": "SEC"})
+
+	plan, payload, error := PlanMove(automation.ReadOnlyClient{OrganizationsAPI: org}, nil, accountID, MoveRequest{Env: "Dev"}, "")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	expectedPlan := &automation.Plan{
+		AccountID:     accountID,
+		Name:          "AWS_SEC_test_Lab",
+		Email:         "AWS_SEC_test_Lab@example.com",
+		SourceOU:      "ou-abcd-22222222",
+		DestinationOU: &automation.Destination{ID: "ou-abcd-33333333", Path: "ou-abcd-11111111/Dev"},
+		Tags:          automation.TagChanges{Add: map[string]string{}, Change: map[string]automation.TagChange{"Env": {From: "Lab", To: "Dev"}}},
+	}
+	if !cmp.Equal(plan, expectedPlan) {
+		t.Fatal("Unexpected plan: ", cmp.Diff(expectedPlan, plan))
+	}
+	if payload.Env != "Dev" || payload.Lob != "SEC" {
+		t.Fatal("Unexpected payload: ", payload)
+	}
+	if org.Parent(accountID) != "ou-abcd-22222222" || org.Tags(accountID)["Env"] != "Lab" {
+		t.Fatal("Planning a move was not expected to change the account: ", org.Parent(accountID), org.Tags(accountID))
+	}
+
+	//test that the read-only client refuses to make the move
+	_, error = MoveAccountTo(automation.ReadOnlyClient{OrganizationsAPI: org}, nil, accountID, MoveRequest{Env: "Dev"}, "")
+	if error == nil || org.Calls("MoveAccount") != 0 || org.Parent(accountID) != "ou-abcd-22222222" {
+		t.Fatal("A move through the read-only client was expected to fail: ", error, org.Parent(accountID))
+	}
+}
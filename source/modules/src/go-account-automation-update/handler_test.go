@@ -1,14 +1,17 @@
-package main
+package updatelambda
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"log"
 	"os"
 	"strings"
 	"testing"
 
-	"github.com/aws/aws-sdk-go/service/organizations"
-	"github.com/google/go-cmp/cmp"
+	"github.com/aws/aws-lambda-go/events"
+
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/fakeorg"
 )
 
 func TestProcessRequestPayload(t *testing.T) {
@@ -24,12 +27,12 @@ func TestProcessRequestPayload(t *testing.T) {
 	}
 
 	//test
-	payload, error := ProcessRequestPayload(string(byteValue))
+	payload, error := automation.ProcessRequestPayload(string(byteValue))
 	if error != nil {
 		t.Fatal("Error serializing payload: ", error.Error())
 	}
 
-	if !strings.EqualFold(payload.Name, "aws_SEC_test_Dev") ||
+	if !strings.EqualFold(payload.Name, "AWS_SEC_test_Dev") ||
 		!strings.EqualFold(payload.CostCenter, "01234") ||
 		!strings.EqualFold(payload.AccountPOC, "john.doe@example.com") ||
 		!strings.EqualFold(payload.ApplicationID, "00000000-0000-0000-0000-000000000000") ||
@@ -40,89 +43,119 @@ func TestProcessRequestPayload(t *testing.T) {
 	}
 }
 
-func TestValidatePayload(t *testing.T) {
-	accountID := "999999999999"
-	svc := mockOrganizationsClient{
-		accountName: "aws_SEC_test_Dev",
-	}
-	testPayload := AccountPayload{
-		Name:          "aws_SEC_test_Dev",
-		AccountPOC:    "01234",
-		ApplicationID: "john.doe@example.com",
+// secOrg returns an organization with Lab and Dev OUs under the SEC OU, and the ID of an account named name in its
+// Lab OU, tagged with tags.
+func secOrg(name string, tags map[string]string) (*fakeorg.Client, string) {
+	svc := fakeorg.NewClient()
+	svc.AddOU(fakeorg.RootID, "ou-abcd-01234567", "Workloads")
+	svc.AddOU(fakeorg.RootID, "ou-abcd-11111111", "Security")
+	svc.AddOU("ou-abcd-11111111", "ou-abcd-22222222", "Lab")
+	svc.AddOU("ou-abcd-11111111", "ou-abcd-33333333", "Dev")
+	accountID := svc.AddAccount("ou-abcd-22222222", name, name+"@example.com", tags)
+	return svc, accountID
+}
+
+func TestValidateUpdate(t *testing.T) {
+	svc, accountID := secOrg("AWS_SEC_test_Dev", nil)
+	testPayload := automation.AccountPayload{
+		Name:          "AWS_SEC_test_Dev",
+		CostCenter:    "01234",
+		AccountPOC:    "john.doe@example.com",
+		ApplicationID: "00000000-0000-0000-0000-000000000000",
 		Env:           "Dev",
 		Lob:           "SEC",
 		AccountID:     "",
 	}
-	error := ValidatePayload(svc, accountID, testPayload)
+	error := ValidateUpdate(svc, accountID, testPayload)
 	if error != nil {
 		t.Fatal("Payload failed validation: ", error.Error())
 	}
 
 	//test for malformed payload catch
-	testPayload.Name = "aws_IS_test_Dev"
-	error = ValidatePayload(svc, accountID, testPayload)
+	testPayload.Name = "AWS_IS_test_Dev"
+	error = ValidateUpdate(svc, accountID, testPayload)
 	if error == nil {
 		t.Fatal("Payload was expected to fail but didn't ")
 	}
+
+	//test that a moved account is validated against its Env and Lob tags
+	error = automation.TagAccount(svc, GenerateMoveTags("SEC", "Lab"), accountID)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	testPayload.Name = "AWS_SEC_test_Dev"
+	error = ValidateUpdate(svc, accountID, testPayload)
+	if error == nil {
+		t.Fatal("Payload was expected to fail but didn't ")
+	}
+	testPayload.Env = "Lab"
+	error = ValidateUpdate(svc, accountID, testPayload)
+	if error != nil {
+		t.Fatal("Payload failed validation: ", error.Error())
+	}
+
+	//test that every field that is not valid is reported at once
+	testPayload.CostCenter, testPayload.ApplicationID, testPayload.Lob = "1234", "not-a-uuid", "IS"
+	error = ValidateUpdate(svc, accountID, testPayload)
+	validationError, ok := error.(*automation.ValidationError)
+	if !ok || len(validationError.Problems) != 3 {
+		t.Fatal("Payload was expected to fail with every problem: ", error)
+	}
+	if validationError.Problems[2].Field != "lob" || validationError.Problems[2].Code != "mismatch" {
+		t.Fatal("Unexpected problem: ", validationError.Problems[2])
+	}
 }
 
-func TestGenerateKeysAndTags(t *testing.T) {
-	payload := AccountPayload{
-		Name:          "aws_SEC_test_Dev",
+func TestHandleUpdateRequestEmailDomain(t *testing.T) {
+	defer os.Setenv("EMAIL_DOMAIN", os.Getenv("EMAIL_DOMAIN"))
+	os.Setenv("EMAIL_DOMAIN", "@corp.example.org")
+	svc := fakeorg.NewClient()
+	accountID := svc.AddAccount(fakeorg.RootID, "AWS_SEC_test_Dev", "AWS_SEC_test_Dev@corp.example.org", map[string]string{"Env": "Dev", "Lob": "SEC"})
+	h := &Handler{Mode: automation.ModeLive, Org: svc}
+
+	payload := automation.AccountPayload{
+		Name:          "AWS_SEC_test_Dev",
 		CostCenter:    "01234",
-		AccountPOC:    "john.doe@example.com",
+		AccountPOC:    "john.doe@corp.example.org",
 		ApplicationID: "00000000-0000-0000-0000-000000000000",
-		Env:           "DEV",
+		Env:           "Dev",
 		Lob:           "SEC",
 	}
-	name, costCenter, accountPOC, applicationID, env, lob := "Name", "CostCenter", "AccountPOC", "ApplicationID", "Env", "Lob"
-	expectedTags := []*organizations.Tag{
-		{
-			Key:   &name,
-			Value: &payload.Name,
-		},
-		{
-			Key:   &costCenter,
-			Value: &payload.CostCenter,
-		},
-		{
-			Key:   &accountPOC,
-			Value: &payload.AccountPOC,
-		},
-		{
-			Key:   &applicationID,
-			Value: &payload.ApplicationID,
-		},
-		{
-			Key:   &env,
-			Value: &payload.Env,
-		},
-		{
-			Key:   &lob,
-			Value: &payload.Lob,
-		},
-	}
-
-	expectedKeys := []*string{&name, &costCenter, &accountPOC, &applicationID, &env, &lob}
-	keys, tags := GenerateKeysAndTags(payload)
-	for i := range keys {
-		if !strings.EqualFold(*keys[i], *expectedKeys[i]) {
-			t.Fatal("Account tagging returned unexpected output for keys")
-
+	update := func(payload automation.AccountPayload) *events.APIGatewayProxyResponse {
+		body, _ := json.Marshal(payload)
+		response, error := h.HandleUpdateRequest(events.APIGatewayProxyRequest{Body: string(body), QueryStringParameters: map[string]string{"account-id": accountID}})
+		if error != nil {
+			t.Fatal(error.Error())
 		}
+		return response
+	}
+
+	//test that an accountPOC in the configured domain, rather than the default one, is accepted
+	response := update(payload)
+	if response.StatusCode != 200 || svc.Tags(accountID)["AccountPOC"] != payload.AccountPOC {
+		t.Fatal("Unexpected response for an accountPOC in EMAIL_DOMAIN: ", response.StatusCode, response.Body)
 	}
 
-	if !cmp.Equal(tags, expectedTags) {
-		t.Fatal("Account tagging returned unexpected output tags")
+	//test that an accountPOC in the default domain is rejected once another domain is configured
+	payload.AccountPOC = "john.doe@example.com"
+	response = update(payload)
+	var body automation.ValidationError
+	json.Unmarshal([]byte(response.Body), &body)
+	if response.StatusCode != 400 || len(body.Problems) != 1 || body.Problems[0].Field != "accountPOC" || body.Problems[0].Code != "invalid_format" {
+		t.Fatal("Unexpected response for an accountPOC outside EMAIL_DOMAIN: ", response.StatusCode, response.Body)
 	}
 }
 
 func TestMain(m *testing.M) {
-	err := os.Setenv("RUNTIME_ENV", "prod")
+	err := os.Setenv("WORKLOAD_OU", "ou-abcd-01234567")
+	if err != nil {
+		log.Panic("Issue setting env var")
+	}
+
+	err = os.Setenv("SEC_OU", "{\"SEC\":\"ou-abcd-11111111\"}")
 	if err != nil {
 		log.Panic("Issue setting env var")
 	}
-	_RUNTIME_ENV_ = os.Getenv("RUNTIME_ENV")
 
 	m.Run()
 }
@@ -0,0 +1,9 @@
+package updatelambda
+
+import "go-account-automation/internal/automation"
+
+// LocalRoutes are the routes swagger.json sends to this Lambda.
+var LocalRoutes = []automation.LocalRoute{
+	{Method: "PUT", Resource: "/accounts"},
+	{Method: "POST", Resource: "/accounts/{accountId}/move"},
+}
@@ -0,0 +1,41 @@
+package updatelambda
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-account-automation/internal/automation"
+)
+
+func TestLocalServer(t *testing.T) {
+	h, _, accountID := simulatedHandler(t)
+	server := httptest.NewServer(automation.NewLocalServer(automation.LocalLambda{Name: "update", Handle: h.HandleRequest, Routes: LocalRoutes}))
+	defer server.Close()
+
+	testCases := []struct {
+		method         string
+		target         string
+		body           string
+		expectedStatus int
+	}{
+		{"PUT", "/v1/accounts?account-id=" + accountID, `{"name":"AWS_SEC_simulated_Dev","costCenter":"01234","accountPOC":"john.doe@example.com","applicationId":"00000000-0000-0000-0000-000000000000","env":"Dev","lob":"SEC"}`, http.StatusOK},
+		{"POST", "/v1/accounts/" + accountID + "/move", `{"env":"Dev"}`, http.StatusOK},
+		{"POST", "/v1/accounts/" + accountID + "/move", `{}`, http.StatusBadRequest},
+		{"POST", "/v1/accounts", `{}`, http.StatusMethodNotAllowed},
+		{"PUT", "/v1/accounts/" + accountID, `{}`, http.StatusNotFound},
+	}
+	for _, testCase := range testCases {
+		request, _ := http.NewRequest(testCase.method, server.URL+testCase.target, bytes.NewBufferString(testCase.body))
+		request.Header.Set(automation.LocalCallerLobHeader, "SEC")
+		response, error := http.DefaultClient.Do(request)
+		if error != nil {
+			t.Fatal(error.Error())
+		}
+		response.Body.Close()
+		if response.StatusCode != testCase.expectedStatus {
+			t.Fatal("Unexpected status for ", testCase.method, " ", testCase.target, ": ", response.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package updatelambda
+
+import (
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+
+	"go-account-automation/internal/automation"
+)
+
+// Handler serves this Lambda's requests with the Organizations client of its Mode. Tree caches the OUs of that
+// organization; a Handler without one looks every OU up.
+type Handler struct {
+	Mode automation.Mode
+	Org  organizationsiface.OrganizationsAPI
+	Tree *automation.OrgTree
+}
+
+// NewHandler returns a Handler for mode that runs against org, the Organizations client of orgclient.New.
+func NewHandler(mode automation.Mode, org organizationsiface.OrganizationsAPI) *Handler {
+	return &Handler{Mode: mode, Org: org, Tree: automation.NewOrgTree(automation.RetrieveOrgTreeTTL())}
+}
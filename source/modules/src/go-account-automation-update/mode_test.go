@@ -0,0 +1,80 @@
+package updatelambda
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/fakeorg"
+	"go-account-automation/internal/automation/orgclient"
+)
+
+// simulatedHandler returns a simulated Handler whose organization holds a SEC account in the SEC Dev OU.
+func simulatedHandler(t *testing.T) (*Handler, *fakeorg.Client, string) {
+	org, error := orgclient.NewSimulated()
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	h := NewHandler(automation.ModeSimulated, org)
+	dev := org.AddOU("ou-abcd-11111111", "", "Dev")
+	accountID := org.AddAccount(dev, "AWS_SEC_simulated_Dev", "AWS_SEC_simulated_Dev@example.com", map[string]string{"Lob": "SEC", "Env": "Dev"})
+	return h, org, accountID
+}
+
+func TestSimulatedHandler(t *testing.T) {
+	h, org, accountID := simulatedHandler(t)
+
+	request := events.APIGatewayProxyRequest{
+		Resource:              "/accounts",
+		QueryStringParameters: map[string]string{"account-id": accountID},
+		Body:                  `{"name":"AWS_SEC_simulated_Dev","costCenter":"01234","accountPOC":"john.doe@example.com","applicationId":"00000000-0000-0000-0000-000000000000","env":"Dev","lob":"SEC"}`,
+	}
+	response, _ := h.HandleRequest(request)
+	if response.StatusCode != 200 || org.Tags(accountID)["CostCenter"] != "01234" {
+		t.Fatal("Unexpected response: ", response.StatusCode, response.Body, org.Tags(accountID))
+	}
+
+	//test that the move changes the simulated organization
+	prod := org.AddOU("ou-abcd-11111111", "", "Prod")
+	request = events.APIGatewayProxyRequest{
+		Resource:       "/accounts/{accountId}/move",
+		PathParameters: map[string]string{"accountId": accountID},
+		Body:           `{"env":"Prod"}`,
+		RequestContext: events.APIGatewayProxyRequestContext{Authorizer: map[string]interface{}{"lob": "SEC"}},
+	}
+	response, _ = h.HandleRequest(request)
+	if response.StatusCode != 200 || org.Parent(accountID) != prod || org.Tags(accountID)["Env"] != "Prod" {
+		t.Fatal("Unexpected response: ", response.StatusCode, response.Body)
+	}
+
+	//test that an account the simulated organization does not hold is not found
+	request.PathParameters["accountId"] = "999999999999"
+	response, _ = h.HandleRequest(request)
+	if response.StatusCode != 404 {
+		t.Fatal("An unknown account was expected to be not found: ", response.StatusCode, response.Body)
+	}
+}
+func TestDryRunModeHandler(t *testing.T) {
+	svc, accountID := secOrg("AWS_SEC_test_Dev", nil)
+	h := &Handler{Mode: automation.ModeDryRun, Org: automation.ReadOnlyClient{OrganizationsAPI: svc}}
+
+	request := events.APIGatewayProxyRequest{
+		Resource:              "/accounts",
+		QueryStringParameters: map[string]string{"account-id": accountID},
+		Body:                  `{"name":"AWS_SEC_test_Dev","costCenter":"01234","accountPOC":"john.doe@example.com","applicationId":"00000000-0000-0000-0000-000000000000","env":"Dev","lob":"SEC"}`,
+	}
+	response, _ := h.HandleRequest(request)
+	if response.StatusCode != 200 {
+		t.Fatal("Unexpected response: ", response.StatusCode, response.Body)
+	}
+	var plan automation.Plan
+	error := json.Unmarshal([]byte(response.Body), &plan)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if !plan.DryRun || plan.Tags.Add["CostCenter"] != "01234" || len(svc.Tags(accountID)) != 0 {
+		t.Fatal("A dry-run handler was expected to plan the update without tagging the account: ", response.Body)
+	}
+}
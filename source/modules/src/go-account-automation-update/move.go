@@ -0,0 +1,223 @@
+package updatelambda
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+
+	"go-account-automation/internal/automation"
+)
+
+// MoveRequest is the body of POST /accounts/{accountId}/move. A field left empty keeps the account's current value.
+type MoveRequest struct {
+	Env string `json:"env"`
+	Lob string `json:"lob"`
+}
+
+type MoveAccountResponse struct {
+	AccountID     string `json:"accountId"`
+	Name          string `json:"name"`
+	Env           string `json:"env"`
+	Lob           string `json:"lob"`
+	SourceOU      string `json:"sourceOu"`
+	DestinationOU string `json:"destinationOu"`
+}
+
+// RetrieveAccountPayload rebuilds the account's payload from its tags. The account name records the lob and env
+// the account was created with, so it is only used for them when the Lob and Env tags, which a move rewrites, are missing.
+func RetrieveAccountPayload(svc organizationsiface.OrganizationsAPI, accountID string, accountName string) (automation.AccountPayload, error) {
+	tags, error := automation.ListAccountTags(svc, accountID)
+	if error != nil {
+		return automation.AccountPayload{}, error
+	}
+	return automation.PayloadFromAccount(accountID, accountName, tags)
+}
+
+// GenerateMoveTags returns the tags that record the account's new lob and env. TagResource overwrites
+// existing tags with the same keys, so the account's other tags are left as they are.
+func GenerateMoveTags(lob string, env string) []*organizations.Tag {
+	return []*organizations.Tag{
+		{Key: aws.String("Env"), Value: aws.String(env)},
+		{Key: aws.String("Lob"), Value: aws.String(lob)},
+	}
+}
+
+// PlanMove works out where moveRequest takes the account and how it rewrites the account's Env and Lob tags,
+// without changing anything. It also returns the account's payload with the new lob and env. A move that cannot
+// be made is returned as an *automation.APIError whose code says why, and an env or lob that is not valid as a
+// *ValidationError.
+//
+// The delete Lambda authorizes a closure by the Lob tag a move rewrites, so callerLob must be both the account's
+// current lob and the lob it is moved to, or the move is refused with a 403. Operator commands, which do not come
+// through the API GW authorizer, pass an empty callerLob.
+func PlanMove(svc organizationsiface.OrganizationsAPI, tree *automation.OrgTree, accountID string, moveRequest MoveRequest, callerLob string) (*automation.Plan, automation.AccountPayload, error) {
+	if moveRequest.Env == "" && moveRequest.Lob == "" {
+		return nil, automation.AccountPayload{}, &automation.APIError{StatusCode: 400, Code: "move_target_required", Message: "error: env or lob is required"}
+	}
+	var problems []automation.FieldError
+	if fieldError := automation.ValidateEnv(moveRequest.Env); moveRequest.Env != "" && fieldError != nil {
+		problems = append(problems, *fieldError)
+	}
+	if fieldError := automation.ValidateLob(moveRequest.Lob); moveRequest.Lob != "" && fieldError != nil {
+		problems = append(problems, *fieldError)
+	}
+	if error := automation.NewValidationError(problems); error != nil {
+		return nil, automation.AccountPayload{}, error
+	}
+
+	log.Println("Describing account")
+	account, error := svc.DescribeAccount(&organizations.DescribeAccountInput{AccountId: &accountID})
+	if error != nil {
+		return nil, automation.AccountPayload{}, error
+	}
+	accountName := aws.StringValue(account.Account.Name)
+
+	log.Println("Retrieving current payload from tags")
+	tags, error := automation.ListAccountTags(svc, accountID)
+	if error != nil {
+		return nil, automation.AccountPayload{}, error
+	}
+	payload, error := automation.PayloadFromAccount(accountID, accountName, tags)
+	if error != nil {
+		return nil, automation.AccountPayload{}, error
+	}
+	if callerLob != "" {
+		targetLob := payload.Lob
+		if moveRequest.Lob != "" {
+			targetLob = moveRequest.Lob
+		}
+		if !strings.EqualFold(callerLob, payload.Lob) || !strings.EqualFold(callerLob, targetLob) {
+			return nil, automation.AccountPayload{}, &automation.APIError{StatusCode: 403, Code: "lob_mismatch", Message: "error: account " + accountID + " in line of business " + payload.Lob + " cannot be moved to " + targetLob + " by " + callerLob}
+		}
+	}
+	if moveRequest.Lob != "" {
+		payload.Lob = moveRequest.Lob
+	}
+	if moveRequest.Env != "" {
+		payload.Env = moveRequest.Env
+	}
+	lob, env := payload.Lob, payload.Env
+
+	log.Println("Retrieving destination OU ID based on lob and env")
+	_, ou, error := automation.RetrieveOUs(svc, tree, payload)
+	if error != nil {
+		return nil, automation.AccountPayload{}, error
+	}
+	if ou == "" {
+		return nil, automation.AccountPayload{}, &automation.APIError{StatusCode: 400, Code: "ou_not_found", Message: "error: no OU found for lob " + lob + " and env " + env}
+	}
+	path, error := automation.DestinationPath(payload)
+	if error != nil {
+		return nil, automation.AccountPayload{}, error
+	}
+
+	log.Println("Retrieving current parent")
+	parent, error := automation.RetrieveParent(svc, accountID)
+	if error != nil {
+		return nil, automation.AccountPayload{}, error
+	}
+
+	plan := &automation.Plan{
+		AccountID:     accountID,
+		Name:          accountName,
+		Email:         aws.StringValue(account.Account.Email),
+		SourceOU:      parent,
+		DestinationOU: &automation.Destination{ID: ou, Path: path},
+		Tags:          automation.DiffTags(tags, nil, automation.TagMap(GenerateMoveTags(lob, env))),
+	}
+	return plan, payload, nil
+}
+
+// MoveAccountTo moves the account to the OU for the lob and env in moveRequest and rewrites its Env and Lob tags.
+// A move that cannot be made, or that callerLob may not make, is returned as an *automation.APIError.
+func MoveAccountTo(svc organizationsiface.OrganizationsAPI, tree *automation.OrgTree, accountID string, moveRequest MoveRequest, callerLob string) (*MoveAccountResponse, error) {
+	plan, payload, error := PlanMove(svc, tree, accountID, moveRequest, callerLob)
+	if error != nil {
+		return nil, error
+	}
+	parent, ou := plan.SourceOU, plan.DestinationOU.ID
+
+	log.Println("Moving account to destination OU")
+	error = automation.MoveAccount(svc, accountID, ou)
+	if error != nil {
+		return nil, error
+	}
+
+	log.Println("Rewriting Env and Lob tags")
+	error = automation.TagAccount(svc, GenerateMoveTags(payload.Lob, payload.Env), accountID)
+	if error != nil {
+		return nil, error
+	}
+
+	response := &MoveAccountResponse{
+		AccountID:     accountID,
+		Name:          plan.Name,
+		Env:           payload.Env,
+		Lob:           payload.Lob,
+		SourceOU:      parent,
+		DestinationOU: ou,
+	}
+	return response, nil
+}
+
+func (h *Handler) HandleMoveRequest(request events.APIGatewayProxyRequest) (*events.APIGatewayProxyResponse, error) {
+	svc := h.Org
+
+	accountID := request.PathParameters["accountId"]
+	if accountID == "" {
+		return automation.HandleErrors(errors.New("error: account id is required"), 400)
+	}
+
+	callerLob := automation.RetrieveCallerLob(request)
+	if callerLob == "" {
+		return automation.HandleErrors(&automation.APIError{StatusCode: 403, Code: "caller_lob_unknown", Message: "error: the caller's line of business could not be determined"}, 403)
+	}
+
+	log.Println("Serializing Payload")
+	var moveRequest MoveRequest
+	error := json.Unmarshal([]byte(request.Body), &moveRequest)
+	if error != nil {
+		return automation.HandleErrors(error, 400)
+	}
+
+	var responseBody interface{}
+	if h.Mode == automation.ModeDryRun || automation.IsDryRun(request) {
+		log.Println("Dry run requested, planning the move without making changes...")
+		var plan *automation.Plan
+		plan, _, error = PlanMove(automation.ReadOnlyClient{OrganizationsAPI: svc}, h.Tree, accountID, moveRequest, callerLob)
+		if plan != nil {
+			plan.DryRun = true
+		}
+		responseBody = plan
+	} else {
+		responseBody, error = MoveAccountTo(svc, h.Tree, accountID, moveRequest, callerLob)
+	}
+	if namingError, ok := error.(*automation.NamingError); ok {
+		return automation.HandleNamingError(namingError)
+	}
+	if validationError, ok := error.(*automation.ValidationError); ok {
+		return automation.HandleValidationError(validationError)
+	}
+	if error != nil {
+		return automation.HandleErrors(error, 500)
+	}
+
+	log.Println("Stringifying response body...")
+	jsonResponseBody, error := json.Marshal(responseBody)
+	if error != nil {
+		return automation.HandleErrors(error, 500)
+	}
+	log.Println("Response payload: ", string(jsonResponseBody))
+
+	response := &events.APIGatewayProxyResponse{
+		StatusCode: 200,
+		Body:       string(jsonResponseBody),
+	}
+	return response, nil
+}
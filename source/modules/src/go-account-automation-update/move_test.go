@@ -0,0 +1,228 @@
+package updatelambda
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/google/go-cmp/cmp"
+
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/fakeorg"
+)
+
+func TestRetrieveAccountPayload(t *testing.T) {
+	svc, accountID := secOrg("AWS_SEC_test_Dev", nil)
+	payload, error := RetrieveAccountPayload(svc, accountID, "AWS_SEC_test_Dev")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if payload.Lob != "SEC" || payload.Env != "Dev" {
+		t.Fatal("Lob and env were not read from the account name: ", payload)
+	}
+
+	//test that tags written by a move take precedence over the account name
+	svc, accountID = secOrg("AWS_SEC_test_Dev", map[string]string{"Env": "Lab", "Lob": "IS", "CostCenter": "01234"})
+	payload, error = RetrieveAccountPayload(svc, accountID, "AWS_SEC_test_Dev")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if payload.Lob != "IS" || payload.Env != "Lab" || payload.CostCenter != "01234" || payload.AccountID != accountID {
+		t.Fatal("Payload was not read from the account tags: ", payload)
+	}
+
+	_, error = RetrieveAccountPayload(svc, accountID, "AWS_SEC_Dev")
+	if error == nil {
+		t.Fatal("Malformed account name was expected to fail but didn't")
+	}
+}
+func TestMoveAccountTo(t *testing.T) {
+	svc, accountID := secOrg("AWS_SEC_test_Lab", nil)
+
+	response, error := MoveAccountTo(svc, nil, accountID, MoveRequest{Env: "Dev"}, "")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	expectedResponse := &MoveAccountResponse{
+		AccountID:     accountID,
+		Name:          "AWS_SEC_test_Lab",
+		Env:           "Dev",
+		Lob:           "SEC",
+		SourceOU:      "ou-abcd-22222222",
+		DestinationOU: "ou-abcd-33333333",
+	}
+	if !cmp.Equal(response, expectedResponse) {
+		t.Fatal("Move returned unexpected response: ", cmp.Diff(expectedResponse, response))
+	}
+	if svc.Calls("MoveAccount") != 1 || svc.Parent(accountID) != "ou-abcd-33333333" {
+		t.Fatal("Account was not moved as expected: ", svc.Parent(accountID))
+	}
+	if !cmp.Equal(svc.Tags(accountID), map[string]string{"Env": "Dev", "Lob": "SEC"}) {
+		t.Fatal("Env and Lob tags were not rewritten: ", svc.Tags(accountID))
+	}
+
+	//test that an account already in the destination OU is only retagged
+	_, error = MoveAccountTo(svc, nil, accountID, MoveRequest{Env: "Dev"}, "")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if svc.Calls("MoveAccount") != 1 {
+		t.Fatal("MoveAccount was called for an account already in the destination OU")
+	}
+
+	//test for empty and unresolvable moves
+	for moveRequest, expectedCode := range map[MoveRequest]string{{}: "move_target_required", {Env: "Prod"}: "ou_not_found"} {
+		_, error = MoveAccountTo(svc, nil, accountID, moveRequest, "")
+		if apiError, ok := error.(*automation.APIError); !ok || apiError.StatusCode != 400 || apiError.Code != expectedCode {
+			t.Fatal("Move was expected to fail with 400 ", expectedCode, ", got: ", error)
+		}
+	}
+
+	//test that an env and lob that are not valid are both reported
+	_, error = MoveAccountTo(svc, nil, accountID, MoveRequest{Env: "Staging", Lob: "sec"}, "")
+	if validationError, ok := error.(*automation.ValidationError); !ok || len(validationError.Problems) != 2 {
+		t.Fatal("Move was expected to fail validation, got: ", error)
+	}
+}
+
+func TestHandleMoveRequest(t *testing.T) {
+	request := events.APIGatewayProxyRequest{
+		Resource: "/accounts/{accountId}/move",
+		Body:     `{"env":"Dev"}`,
+	}
+	h := &Handler{Mode: automation.ModeLive, Org: fakeorg.NewClient()}
+	response, error := h.HandleRequest(request)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if response.StatusCode != 400 {
+		t.Fatal("Move request without an account id was expected to return 400, got: ", response.StatusCode)
+	}
+}
+func TestMoveAgainstFakeOrganization(t *testing.T) {
+	svc := fakeorg.NewClient()
+	svc.PageSize = 1
+	workloads := svc.AddOU(fakeorg.RootID, "ou-abcd-01234567", "Workloads")
+	lab := svc.AddOUPath("/Workloads/Lab/APP")
+	dev := svc.AddOUPath("/Workloads/Dev/APP")
+	accountID := svc.AddAccount(lab, "AWS_APP_test_Lab", "AWS_APP_test_Lab@example.com", map[string]string{"Name": "AWS_APP_test_Lab", "CostCenter": "01234", "Env": "Lab", "Lob": "APP"})
+	h := &Handler{Mode: automation.ModeLive, Org: svc}
+	request := events.APIGatewayProxyRequest{
+		Resource:       "/accounts/{accountId}/move",
+		PathParameters: map[string]string{"accountId": accountID},
+		Body:           `{"env":"Dev"}`,
+		RequestContext: events.APIGatewayProxyRequestContext{Authorizer: map[string]interface{}{"lob": "APP"}},
+	}
+	if svc.Parent(svc.Parent(dev)) != workloads {
+		t.Fatal("Unexpected tree: ", svc.Parent(dev))
+	}
+
+	//test that a caller can only move the accounts of its own lob, and only within it
+	for _, forbidden := range []struct {
+		callerLob    string
+		body         string
+		expectedCode string
+	}{
+		{"", `{"env":"Dev"}`, "caller_lob_unknown"},
+		{"SEC", `{"env":"Dev"}`, "lob_mismatch"},
+		{"APP", `{"env":"Dev","lob":"SEC"}`, "lob_mismatch"},
+	} {
+		forbiddenRequest := request
+		forbiddenRequest.Body = forbidden.body
+		forbiddenRequest.RequestContext = events.APIGatewayProxyRequestContext{Authorizer: map[string]interface{}{"lob": forbidden.callerLob}}
+		response, _ := h.HandleRequest(forbiddenRequest)
+		var body automation.APIError
+		if response.StatusCode != 403 || json.Unmarshal([]byte(response.Body), &body) != nil || body.Code != forbidden.expectedCode || svc.Parent(accountID) != lab || svc.Tags(accountID)["Lob"] != "APP" {
+			t.Fatal("Move was expected to be forbidden: ", forbidden.callerLob, forbidden.body, response.StatusCode, response.Body)
+		}
+	}
+
+	//test that a failed move leaves the account where it is
+	svc.Fault("MoveAccount", errors.New("injected failure"))
+	response, _ := h.HandleRequest(request)
+	if response.StatusCode != 500 || svc.Parent(accountID) != lab || svc.Tags(accountID)["Env"] != "Lab" {
+		t.Fatal("A failed move was expected to leave the account as it was: ", response.StatusCode, svc.Parent(accountID))
+	}
+
+	svc.Fault("MoveAccount", nil)
+	response, _ = h.HandleRequest(request)
+	if response.StatusCode != 200 {
+		t.Fatal("Unexpected response: ", response.StatusCode, response.Body)
+	}
+	expectedTags := map[string]string{"Name": "AWS_APP_test_Lab", "CostCenter": "01234", "Env": "Dev", "Lob": "APP"}
+	if svc.Parent(accountID) != dev || !cmp.Equal(svc.Tags(accountID), expectedTags) {
+		t.Fatal("Account was not moved and retagged: ", svc.Parent(accountID), svc.Tags(accountID))
+	}
+
+	//test that the moved account can then be updated under its new env
+	request = events.APIGatewayProxyRequest{
+		Resource:              "/accounts",
+		QueryStringParameters: map[string]string{"account-id": accountID},
+		Body:                  `{"name":"AWS_APP_test_Lab","costCenter":"56789","accountPOC":"john.doe@example.com","applicationId":"00000000-0000-0000-0000-000000000000","env":"Dev","lob":"APP"}`,
+	}
+	response, _ = h.HandleRequest(request)
+	if response.StatusCode != 200 || svc.Tags(accountID)["CostCenter"] != "56789" || svc.Tags(accountID)["Env"] != "Dev" {
+		t.Fatal("Account was not updated: ", response.StatusCode, response.Body, svc.Tags(accountID))
+	}
+
+	//test that Organizations errors are reported with their own status and code
+	var body automation.APIError
+	svc.Fault("TagResource", awserr.New(organizations.ErrCodeConcurrentModificationException, "busy", nil))
+	response, _ = h.HandleRequest(request)
+	if response.StatusCode != 409 || json.Unmarshal([]byte(response.Body), &body) != nil || body.Code != "concurrent_modification" || !body.Retryable {
+		t.Fatal("Unexpected response: ", response.StatusCode, response.Body)
+	}
+	svc.Fault("TagResource", nil)
+	request.QueryStringParameters["account-id"] = "111111111111"
+	response, _ = h.HandleRequest(request)
+	if response.StatusCode != 404 || json.Unmarshal([]byte(response.Body), &body) != nil || body.Code != "account_not_found" {
+		t.Fatal("Unexpected response: ", response.StatusCode, response.Body)
+	}
+}
+
+func TestRetrieveOUsAcrossPages(t *testing.T) {
+	svc := fakeorg.NewClient()
+	svc.AddOU(fakeorg.RootID, "ou-abcd-01234567", "Workloads")
+	svc.AddOU("ou-abcd-01234567", "ou-abcd-22222222", "Lab")
+	svc.AddOU("ou-abcd-01234567", "ou-abcd-33333333", "Test")
+	svc.AddOU("ou-abcd-01234567", "ou-abcd-44444444", "Dev")
+	svc.AddOU("ou-abcd-44444444", "ou-abcd-55555555", "OPS")
+	svc.AddOU("ou-abcd-44444444", "ou-abcd-66666666", "APP")
+	svc.PageSize = 1
+
+	root, ou, error := automation.RetrieveOUs(svc, nil, automation.AccountPayload{Env: "Dev", Lob: "APP"})
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if root != fakeorg.RootID || ou != "ou-abcd-66666666" {
+		t.Fatal("RetrieveOUs did not find OUs past the first page: ", root, ou)
+	}
+}
+func TestRetrieveOUsWithPlacementRules(t *testing.T) {
+	error := os.Setenv("PLACEMENT_RULES", `{rules: [{name: sandbox, match: {env: Lab}, path: "Sandbox/{lob}"}], default: {path: "Workloads/{env}/{lob}"}}`)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	defer os.Unsetenv("PLACEMENT_RULES")
+
+	svc := fakeorg.NewClient()
+	svc.AddOU(fakeorg.RootID, "ou-abcd-22222222", "Sandbox")
+	svc.AddOU("ou-abcd-22222222", "ou-abcd-33333333", "APP")
+	root, ou, error := automation.RetrieveOUs(svc, nil, automation.AccountPayload{Env: "Lab", Lob: "APP"})
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if root != fakeorg.RootID || ou != "ou-abcd-33333333" {
+		t.Fatal("RetrieveOUs did not follow the placement rules: ", root, ou)
+	}
+
+	//test that an OU missing from the tree is reported as empty
+	_, ou, error = automation.RetrieveOUs(svc, nil, automation.AccountPayload{Env: "Dev", Lob: "APP"})
+	if error != nil || ou != "" {
+		t.Fatal("RetrieveOUs was expected to find no OU: ", ou, error)
+	}
+}
@@ -0,0 +1,12 @@
+module go-account-automation
+
+go 1.21
+
+require (
+	github.com/aws/aws-lambda-go v1.47.0
+	github.com/aws/aws-sdk-go v1.55.5
+	github.com/google/go-cmp v0.6.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require github.com/jmespath/go-jmespath v0.4.0 // indirect
@@ -0,0 +1,108 @@
+package automation
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/organizations"
+)
+
+// APIError is an error as it is reported to the caller: the status it is returned with, a stable code the caller
+// can act on, and whether the same request may succeed when it is retried.
+type APIError struct {
+	StatusCode int    `json:"-"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	Retryable  bool   `json:"retryable"`
+	// Reason is the reason Organizations gave for a constraint violation, such as ACCOUNT_NUMBER_LIMIT_EXCEEDED.
+	Reason string `json:"reason,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// organizationsErrors classifies the Organizations error codes the Lambdas can get back by the status, code and
+// retryable hint they are reported to the caller with. Codes not listed are reported with the handler's status.
+var organizationsErrors = map[string]APIError{
+	organizations.ErrCodeInvalidInputException: {StatusCode: 400, Code: "invalid_input"},
+
+	organizations.ErrCodeAccountNotFoundException:             {StatusCode: 404, Code: "account_not_found"},
+	organizations.ErrCodeCreateAccountStatusNotFoundException: {StatusCode: 404, Code: "request_not_found"},
+	organizations.ErrCodeOrganizationalUnitNotFoundException:  {StatusCode: 404, Code: "ou_not_found"},
+	organizations.ErrCodeParentNotFoundException:              {StatusCode: 404, Code: "ou_not_found"},
+	organizations.ErrCodeSourceParentNotFoundException:        {StatusCode: 404, Code: "ou_not_found"},
+	organizations.ErrCodeDestinationParentNotFoundException:   {StatusCode: 404, Code: "ou_not_found"},
+	organizations.ErrCodeChildNotFoundException:               {StatusCode: 404, Code: "account_not_found"},
+	organizations.ErrCodeTargetNotFoundException:              {StatusCode: 404, Code: "account_not_found"},
+
+	organizations.ErrCodeDuplicateAccountException:            {StatusCode: 409, Code: "duplicate_account"},
+	organizations.ErrCodeDuplicateOrganizationalUnitException: {StatusCode: 409, Code: "duplicate_ou"},
+	organizations.ErrCodeAccountAlreadyClosedException:        {StatusCode: 409, Code: "account_already_closed"},
+	organizations.ErrCodeConstraintViolationException:         {StatusCode: 409, Code: "constraint_violation"},
+	organizations.ErrCodeConcurrentModificationException:      {StatusCode: 409, Code: "concurrent_modification", Retryable: true},
+	organizations.ErrCodeConflictException:                    {StatusCode: 409, Code: "conflict", Retryable: true},
+
+	organizations.ErrCodeTooManyRequestsException: {StatusCode: 429, Code: "too_many_requests", Retryable: true},
+
+	organizations.ErrCodeServiceException:                {StatusCode: 503, Code: "service_unavailable", Retryable: true},
+	organizations.ErrCodeFinalizingOrganizationException: {StatusCode: 503, Code: "organization_finalizing", Retryable: true},
+	request.ErrCodeRequestError:                          {StatusCode: 503, Code: "service_unavailable", Retryable: true},
+}
+
+// statusCodes are the codes errors that are not classified by their own code are reported with, by status.
+var statusCodes = map[int]string{
+	400: "bad_request",
+	403: "forbidden",
+	404: "not_found",
+	405: "method_not_allowed",
+	409: "conflict",
+	429: "too_many_requests",
+	500: "internal_error",
+	503: "service_unavailable",
+}
+
+// ClassifyError returns error as it is reported to the caller. An *APIError is returned as it is, an Organizations
+// error is classified by its code, a request body that is not valid JSON is a 400, and anything else is reported
+// with statusCode.
+func ClassifyError(error error, statusCode int) *APIError {
+	if apiError, ok := error.(*APIError); ok {
+		return apiError
+	}
+
+	apiError := &APIError{StatusCode: statusCode, Message: error.Error()}
+	switch typedError := error.(type) {
+	case *json.SyntaxError, *json.UnmarshalTypeError:
+		apiError.StatusCode, apiError.Code = 400, "malformed_body"
+		return apiError
+	case *organizations.ConstraintViolationException:
+		apiError.Reason = aws.StringValue(typedError.Reason)
+	}
+
+	if aerr, ok := error.(awserr.Error); ok {
+		if classified, ok := organizationsErrors[aerr.Code()]; ok {
+			apiError.StatusCode, apiError.Code, apiError.Retryable = classified.StatusCode, classified.Code, classified.Retryable
+			return apiError
+		}
+		if strings.Contains(aerr.Code(), "Throttl") {
+			apiError.StatusCode, apiError.Code, apiError.Retryable = 429, "too_many_requests", true
+			return apiError
+		}
+		// AWS failed the request itself, so the same request may well succeed later
+		if failure, ok := error.(awserr.RequestFailure); ok && failure.StatusCode() >= 500 {
+			apiError.StatusCode, apiError.Code, apiError.Retryable = 503, "service_unavailable", true
+			return apiError
+		}
+	}
+
+	apiError.Code = statusCodes[statusCode]
+	if apiError.Code == "" {
+		apiError.Code = strings.ToLower(strings.ReplaceAll(http.StatusText(statusCode), " ", "_"))
+	}
+	apiError.Retryable = statusCode == 429 || statusCode == 503
+	return apiError
+}
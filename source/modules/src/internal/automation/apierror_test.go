@@ -0,0 +1,80 @@
+package automation
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClassifyError(t *testing.T) {
+	testCases := []struct {
+		name       string
+		error      error
+		statusCode int
+		expected   APIError
+	}{
+		{"duplicate account", awserr.New(organizations.ErrCodeDuplicateAccountException, "duplicate", nil), 500, APIError{StatusCode: 409, Code: "duplicate_account", Message: "DuplicateAccountException: duplicate"}},
+		{"account not found", awserr.New(organizations.ErrCodeAccountNotFoundException, "not found", nil), 500, APIError{StatusCode: 404, Code: "account_not_found", Message: "AccountNotFoundException: not found"}},
+		{"too many requests", awserr.New(organizations.ErrCodeTooManyRequestsException, "slow down", nil), 500, APIError{StatusCode: 429, Code: "too_many_requests", Message: "TooManyRequestsException: slow down", Retryable: true}},
+		{"concurrent modification", awserr.New(organizations.ErrCodeConcurrentModificationException, "busy", nil), 500, APIError{StatusCode: 409, Code: "concurrent_modification", Message: "ConcurrentModificationException: busy", Retryable: true}},
+		{"service", awserr.New(organizations.ErrCodeServiceException, "down", nil), 500, APIError{StatusCode: 503, Code: "service_unavailable", Message: "ServiceException: down", Retryable: true}},
+		{"throttled", awserr.New("ThrottlingException", "rate exceeded", nil), 500, APIError{StatusCode: 429, Code: "too_many_requests", Message: "ThrottlingException: rate exceeded", Retryable: true}},
+		{"AWS server failure", awserr.NewRequestFailure(awserr.New("InternalFailure", "oops", nil), 500, "request-1"), 500, APIError{StatusCode: 503, Code: "service_unavailable", Message: "InternalFailure: oops\n\tstatus code: 500, request id: request-1", Retryable: true}},
+		{"unknown AWS error", awserr.New(organizations.ErrCodeAccessDeniedException, "denied", nil), 500, APIError{StatusCode: 500, Code: "internal_error", Message: "AccessDeniedException: denied"}},
+		{"other error", errors.New("error: Destination OU not found"), 400, APIError{StatusCode: 400, Code: "bad_request", Message: "error: Destination OU not found"}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			apiError := ClassifyError(testCase.error, testCase.statusCode)
+			if !cmp.Equal(*apiError, testCase.expected) {
+				t.Fatal("Unexpected classification: ", cmp.Diff(testCase.expected, *apiError))
+			}
+		})
+	}
+
+	//test that the reason for a constraint violation is passed on
+	constraintViolation := &organizations.ConstraintViolationException{Message_: aws.String("too many accounts"), Reason: aws.String(organizations.ConstraintViolationExceptionReasonAccountNumberLimitExceeded)}
+	apiError := ClassifyError(constraintViolation, 500)
+	if apiError.StatusCode != 409 || apiError.Code != "constraint_violation" || apiError.Reason != "ACCOUNT_NUMBER_LIMIT_EXCEEDED" {
+		t.Fatal("Unexpected classification: ", apiError)
+	}
+
+	//test that a request body that is not JSON is the caller's fault
+	var payload AccountPayload
+	apiError = ClassifyError(json.Unmarshal([]byte(`{"name":`), &payload), 500)
+	if apiError.StatusCode != 400 || apiError.Code != "malformed_body" {
+		t.Fatal("Unexpected classification: ", apiError)
+	}
+}
+
+func TestHandleErrors(t *testing.T) {
+	response, error := HandleErrors(awserr.New(organizations.ErrCodeTooManyRequestsException, "slow down", nil), 500)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	var body APIError
+	error = json.Unmarshal([]byte(response.Body), &body)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if response.StatusCode != 429 || body.Code != "too_many_requests" || !body.Retryable {
+		t.Fatal("Unexpected response: ", response.StatusCode, response.Body)
+	}
+
+	//test that a validation error has the same envelope, with its problems under it
+	response, _ = HandleValidationError(NewValidationError([]FieldError{{Field: "env", Code: "required", Message: "error: env is required"}}).(*ValidationError))
+	var validationBody map[string]interface{}
+	error = json.Unmarshal([]byte(response.Body), &validationBody)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	problems, _ := validationBody["problems"].([]interface{})
+	if response.StatusCode != 400 || validationBody["code"] != "invalid_request" || validationBody["retryable"] != false || validationBody["message"] == nil || len(problems) != 1 {
+		t.Fatal("Unexpected response: ", response.StatusCode, response.Body)
+	}
+}
@@ -0,0 +1,125 @@
+package automation
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+)
+
+// ManagedByTag is the tag put on the OUs created by this automation.
+const ManagedByTag = "ManagedBy"
+const ManagedByValue = "go-account-automation"
+
+// plannedOUPrefix marks the IDs given to the OUs a dry run would create.
+const plannedOUPrefix = "planned:"
+
+// OUAutoCreation creates the env and lob OUs missing from an account's placement path, so the first account
+// of a new line of business is not left in the root. OUs are only created directly under one of the allowed
+// parents or under an OU created by the same placement.
+type OUAutoCreation struct {
+	// DryRun plans the OUs that would be created, giving them placeholder IDs, without creating them
+	DryRun bool
+	// Created are the OUs created, or planned when DryRun is set, in the order they were
+	Created []CreatedOU
+
+	allowed map[string]bool
+	planned map[string]bool
+}
+
+// CreatedOU is an OU created, or planned, by an OUAutoCreation.
+type CreatedOU struct {
+	ParentID string `json:"parentId"`
+	Name     string `json:"name"`
+	ID       string `json:"id"`
+}
+
+// LoadOUAutoCreation resolves the AUTO_CREATE_OU_PARENTS environment variable, a JSON list of the OU IDs or
+// paths under which OUs may be created. It returns nil, turning auto-creation off, when the list is empty.
+func LoadOUAutoCreation(svc organizationsiface.OrganizationsAPI, tree *OrgTree) (*OUAutoCreation, error) {
+	jsonList := os.Getenv("AUTO_CREATE_OU_PARENTS")
+	if strings.TrimSpace(jsonList) == "" {
+		return nil, nil
+	}
+	// Serialize json to slice
+	var parents []string
+	error := json.Unmarshal([]byte(jsonList), &parents)
+	if error != nil {
+		return nil, error
+	}
+	if len(parents) == 0 {
+		return nil, nil
+	}
+
+	autoCreate := &OUAutoCreation{allowed: map[string]bool{}, planned: map[string]bool{}}
+	for _, parent := range parents {
+		parentID, error := ResolveOURef(svc, tree, parent)
+		if error != nil {
+			return nil, error
+		}
+		autoCreate.allowed[parentID] = true
+	}
+	return autoCreate, nil
+}
+
+// Child returns the ID of the OU named name directly under parentID, looked up through tree, creating the OU when
+// it is missing and may be created. A nil OUAutoCreation only looks the OU up.
+func (a *OUAutoCreation) Child(svc organizationsiface.OrganizationsAPI, tree *OrgTree, parentID string, name string) (string, error) {
+	if a != nil && a.planned[parentID] {
+		// nothing exists yet under an OU that has only been planned
+		return a.CreateOU(svc, tree, parentID, name)
+	}
+	ou, error := tree.Child(svc, parentID, name)
+	if ou != "" || error != nil || a == nil {
+		return ou, error
+	}
+	return a.CreateOU(svc, tree, parentID, name)
+}
+
+// CreateOU creates the OU named name under parentID, adding it to tree, and returns its ID. It returns an empty ID,
+// leaving the OU missing, when parentID is not allowed to have OUs created under it.
+func (a *OUAutoCreation) CreateOU(svc organizationsiface.OrganizationsAPI, tree *OrgTree, parentID string, name string) (string, error) {
+	if !a.allowed[parentID] {
+		log.Println("OU ", name, " is missing under ", parentID, ", which is not allowed to have OUs created under it")
+		return "", nil
+	}
+
+	if a.DryRun {
+		id := parentID + "/" + name
+		if !a.planned[parentID] {
+			id = plannedOUPrefix + id
+		}
+		log.Println("Dry run - OU ", name, " would be created under ", parentID)
+		a.allowed[id], a.planned[id] = true, true
+		a.Created = append(a.Created, CreatedOU{ParentID: parentID, Name: name, ID: id})
+		return id, nil
+	}
+
+	log.Println("Creating missing OU ", name, " under ", parentID)
+	input := &organizations.CreateOrganizationalUnitInput{
+		Name:     &name,
+		ParentId: &parentID,
+		Tags:     []*organizations.Tag{{Key: aws.String(ManagedByTag), Value: aws.String(ManagedByValue)}},
+	}
+	output, error := svc.CreateOrganizationalUnit(input)
+	if aerr, ok := error.(awserr.Error); ok && aerr.Code() == organizations.ErrCodeDuplicateOrganizationalUnitException {
+		// another request created it first, so the cached children are out of date
+		log.Println("OU ", name, " was created by another request, looking it up...")
+		return tree.Child(svc, parentID, name)
+	}
+	if error != nil {
+		return "", error
+	}
+
+	ou := output.OrganizationalUnit
+	tree.Add(parentID, ou)
+	// the rest of the path can be created under the new OU
+	a.allowed[*ou.Id] = true
+	a.Created = append(a.Created, CreatedOU{ParentID: parentID, Name: name, ID: *ou.Id})
+	return *ou.Id, nil
+}
@@ -0,0 +1,13 @@
+package automation
+
+import (
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// RetrieveCallerLob returns the line of business the API GW authorizer has put in the request context.
+func RetrieveCallerLob(request events.APIGatewayProxyRequest) string {
+	if lob, ok := request.RequestContext.Authorizer["lob"].(string); ok {
+		return lob
+	}
+	return ""
+}
@@ -0,0 +1,21 @@
+package automation
+
+import (
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func TestRetrieveCallerLob(t *testing.T) {
+	request := events.APIGatewayProxyRequest{
+		RequestContext: events.APIGatewayProxyRequestContext{
+			Authorizer: map[string]interface{}{"lob": "SEC"},
+		},
+	}
+	if RetrieveCallerLob(request) != "SEC" {
+		t.Fatal("Caller lob was not read from the authorizer context")
+	}
+	if RetrieveCallerLob(events.APIGatewayProxyRequest{}) != "" {
+		t.Fatal("Caller lob was expected to be empty without an authorizer context")
+	}
+}
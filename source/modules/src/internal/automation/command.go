@@ -0,0 +1,145 @@
+package automation
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Command adds an operator command's flags to flags, which already holds -json, and returns the function that runs
+// the command once they have been parsed, with the arguments left after them.
+type Command func(flags *flag.FlagSet) func(args []string) (interface{}, error)
+
+// CommandMode is the mode operator commands run in: the one named by EXECUTION_MODE, or live when it is not set,
+// as an operator runs them with their own credentials rather than in a deployed environment.
+func CommandMode() (Mode, error) {
+	if value := os.Getenv("EXECUTION_MODE"); value != "" {
+		return ParseMode(value)
+	}
+	return ModeLive, nil
+}
+
+// RunCommand runs the one of commands named in args, e.g. "create -f payload.json", writing its result to stdout
+// and its errors to stderr. It returns the exit status: 0 when the command succeeded, 1 when it failed and 2 when
+// it was not understood.
+func RunCommand(commands map[string]Command, args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 || commands[args[0]] == nil {
+		names := []string{}
+		for name := range commands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintln(stderr, "usage: "+os.Args[0]+" <"+strings.Join(names, "|")+"> [flags]")
+		return 2
+	}
+
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	asJSON := flags.Bool("json", false, "write the result as JSON")
+	run := commands[args[0]](flags)
+	error := flags.Parse(args[1:])
+	if error == flag.ErrHelp {
+		return 0
+	}
+	if error != nil {
+		// the flag package has already reported the error and the usage
+		return 2
+	}
+
+	result, error := run(flags.Args())
+	if result != nil {
+		if writeError := WriteResult(stdout, result, *asJSON); writeError != nil {
+			fmt.Fprintln(stderr, writeError.Error())
+			return 1
+		}
+	}
+	if error != nil {
+		fmt.Fprintln(stderr, error.Error())
+		return 1
+	}
+	return 0
+}
+
+// WriteResult writes result to out as indented JSON, or as one "field: value" line per value when asJSON is not set.
+func WriteResult(out io.Writer, result interface{}, asJSON bool) error {
+	body, error := json.MarshalIndent(result, "", "  ")
+	if error != nil {
+		return error
+	}
+	if asJSON {
+		_, error = fmt.Fprintln(out, string(body))
+		return error
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	error = decoder.Decode(&value)
+	if error != nil {
+		return error
+	}
+	for _, line := range resultLines("", value) {
+		_, error = fmt.Fprintln(out, line)
+		if error != nil {
+			return error
+		}
+	}
+	return nil
+}
+
+// resultLines flattens value into "field: value" lines, naming nested fields by their path, e.g. tags.add.Name.
+func resultLines(field string, value interface{}) []string {
+	var lines []string
+	switch value := value.(type) {
+	case map[string]interface{}:
+		keys := []string{}
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			name := key
+			if field != "" {
+				name = field + "." + key
+			}
+			lines = append(lines, resultLines(name, value[key])...)
+		}
+	case []interface{}:
+		for i, item := range value {
+			lines = append(lines, resultLines(field+"["+strconv.Itoa(i)+"]", item)...)
+		}
+	case nil:
+		lines = append(lines, field+":")
+	default:
+		lines = append(lines, field+": "+fmt.Sprint(value))
+	}
+	return lines
+}
+
+// ReadCommandFile reads file, or stdin when file is "-".
+func ReadCommandFile(file string) (string, error) {
+	var body []byte
+	var error error
+	if file == "-" {
+		body, error = ioutil.ReadAll(os.Stdin)
+	} else {
+		body, error = ioutil.ReadFile(file)
+	}
+	return string(body), error
+}
+
+// ReadCommandPayload reads the account payload from file, or from stdin when file is "-".
+func ReadCommandPayload(file string) (AccountPayload, error) {
+	body, error := ReadCommandFile(file)
+	if error != nil {
+		return AccountPayload{}, error
+	}
+	return ProcessRequestPayload(body)
+}
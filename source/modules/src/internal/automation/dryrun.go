@@ -0,0 +1,165 @@
+package automation
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+)
+
+const DryRunHeader = "X-Dry-Run"
+
+// Plan is returned instead of making any changes when a request is a dry run.
+// AccountID and SourceOU are only set for an account that already exists, and DestinationOU when the account would move.
+type Plan struct {
+	DryRun        bool         `json:"dryRun"`
+	AccountID     string       `json:"accountId,omitempty"`
+	Name          string       `json:"name"`
+	Email         string       `json:"email"`
+	SourceOU      string       `json:"sourceOu,omitempty"`
+	DestinationOU *Destination `json:"destinationOu,omitempty"`
+	Tags          TagChanges   `json:"tags"`
+}
+
+// Destination is the OU an account would be moved to, and the OUs that would be created along the way.
+// The ID of an OU that would be created starts with "planned:".
+type Destination struct {
+	ID          string      `json:"id"`
+	Path        string      `json:"path"`
+	OUsToCreate []CreatedOU `json:"ousToCreate,omitempty"`
+}
+
+// TagChanges are the tags a request would add, change and remove, keyed by tag key.
+type TagChanges struct {
+	Add    map[string]string    `json:"add,omitempty"`
+	Change map[string]TagChange `json:"change,omitempty"`
+	Remove []string             `json:"remove,omitempty"`
+}
+
+type TagChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// IsDryRun reports whether the request asks for a plan rather than changes, with a dryRun query string parameter
+// or an X-Dry-Run header set to true.
+func IsDryRun(request events.APIGatewayProxyRequest) bool {
+	values := []string{request.QueryStringParameters["dryRun"]}
+	for header, value := range request.Headers {
+		if strings.EqualFold(header, DryRunHeader) {
+			values = append(values, value)
+		}
+	}
+	for _, value := range values {
+		if dryRun, _ := strconv.ParseBool(value); dryRun {
+			return true
+		}
+	}
+	return false
+}
+
+func TagMap(tags []*organizations.Tag) map[string]string {
+	tagMap := map[string]string{}
+	for _, tag := range tags {
+		tagMap[*tag.Key] = *tag.Value
+	}
+	return tagMap
+}
+
+// DiffTags returns the changes made to the current tags by untagging the untag keys and then tagging the account with tags.
+func DiffTags(current map[string]string, untag []string, tags map[string]string) TagChanges {
+	changes := TagChanges{Add: map[string]string{}, Change: map[string]TagChange{}}
+	for key, value := range tags {
+		if from, ok := current[key]; !ok {
+			changes.Add[key] = value
+		} else if from != value {
+			changes.Change[key] = TagChange{From: from, To: value}
+		}
+	}
+	for _, key := range untag {
+		if _, ok := tags[key]; !ok {
+			if _, ok := current[key]; ok {
+				changes.Remove = append(changes.Remove, key)
+			}
+		}
+	}
+	return changes
+}
+
+// DestinationPath returns the path of the OU the account is placed in, starting with the configured
+// Workload or Security OU when that is given as an ID.
+func DestinationPath(payload AccountPayload) (string, error) {
+	rules, error := LoadPlacementRules()
+	if error != nil {
+		return "", error
+	}
+	if rules != nil {
+		_, path, error := rules.Evaluate(PlacementAttributes(payload))
+		if error != nil {
+			return "", error
+		}
+		return "/" + path, nil
+	}
+
+	infraSecOUs, error := RetrieveInfraSecOUs()
+	if error != nil {
+		return "", error
+	}
+	path := []string{strings.TrimRight(RetrieveParentOU(infraSecOUs, RetrieveWorkloadOURef(), payload.Lob), "/"), payload.Env}
+	if _, ok := LookupLob(infraSecOUs, payload.Lob); !ok {
+		path = append(path, payload.Lob)
+	}
+	return strings.Join(path, "/"), nil
+}
+
+//go:generate go run gen_readonly.go
+
+// ReadOnlyClient lets a dry run make the Describe and List calls the planners need and fails every other
+// Organizations call, including any added to the SDK later, without reaching OrganizationsAPI.
+type ReadOnlyClient struct {
+	refusedOrganizations
+	OrganizationsAPI organizationsiface.OrganizationsAPI
+}
+
+func readOnlyError(operation string) error {
+	return errors.New("error: " + operation + " cannot be called during a dry run")
+}
+
+func (c ReadOnlyClient) DescribeAccount(input *organizations.DescribeAccountInput) (*organizations.DescribeAccountOutput, error) {
+	return c.OrganizationsAPI.DescribeAccount(input)
+}
+
+func (c ReadOnlyClient) DescribeCreateAccountStatus(input *organizations.DescribeCreateAccountStatusInput) (*organizations.DescribeCreateAccountStatusOutput, error) {
+	return c.OrganizationsAPI.DescribeCreateAccountStatus(input)
+}
+
+func (c ReadOnlyClient) DescribeOrganizationalUnit(input *organizations.DescribeOrganizationalUnitInput) (*organizations.DescribeOrganizationalUnitOutput, error) {
+	return c.OrganizationsAPI.DescribeOrganizationalUnit(input)
+}
+
+func (c ReadOnlyClient) ListAccounts(input *organizations.ListAccountsInput) (*organizations.ListAccountsOutput, error) {
+	return c.OrganizationsAPI.ListAccounts(input)
+}
+
+func (c ReadOnlyClient) ListAccountsForParent(input *organizations.ListAccountsForParentInput) (*organizations.ListAccountsForParentOutput, error) {
+	return c.OrganizationsAPI.ListAccountsForParent(input)
+}
+
+func (c ReadOnlyClient) ListOrganizationalUnitsForParent(input *organizations.ListOrganizationalUnitsForParentInput) (*organizations.ListOrganizationalUnitsForParentOutput, error) {
+	return c.OrganizationsAPI.ListOrganizationalUnitsForParent(input)
+}
+
+func (c ReadOnlyClient) ListParents(input *organizations.ListParentsInput) (*organizations.ListParentsOutput, error) {
+	return c.OrganizationsAPI.ListParents(input)
+}
+
+func (c ReadOnlyClient) ListRoots(input *organizations.ListRootsInput) (*organizations.ListRootsOutput, error) {
+	return c.OrganizationsAPI.ListRoots(input)
+}
+
+func (c ReadOnlyClient) ListTagsForResource(input *organizations.ListTagsForResourceInput) (*organizations.ListTagsForResourceOutput, error) {
+	return c.OrganizationsAPI.ListTagsForResource(input)
+}
@@ -0,0 +1,73 @@
+package automation
+
+import (
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/google/go-cmp/cmp"
+
+	"go-account-automation/internal/automation/fakeorg"
+)
+
+func TestIsDryRun(t *testing.T) {
+	testCases := []struct {
+		name     string
+		request  events.APIGatewayProxyRequest
+		expected bool
+	}{
+		{"no flag", events.APIGatewayProxyRequest{}, false},
+		{"query string", events.APIGatewayProxyRequest{QueryStringParameters: map[string]string{"dryRun": "true"}}, true},
+		{"header", events.APIGatewayProxyRequest{Headers: map[string]string{"x-dry-run": "1"}}, true},
+		{"false", events.APIGatewayProxyRequest{QueryStringParameters: map[string]string{"dryRun": "false"}}, false},
+		{"not a bool", events.APIGatewayProxyRequest{Headers: map[string]string{"X-Dry-Run": "yes please"}}, false},
+	}
+	for _, testCase := range testCases {
+		if IsDryRun(testCase.request) != testCase.expected {
+			t.Fatal("Unexpected dry run flag for ", testCase.name)
+		}
+	}
+}
+
+func TestDiffTags(t *testing.T) {
+	current := map[string]string{"Env": "Dev", "Lob": "SEC", "Owner": "ops", "Stale": "x"}
+	changes := DiffTags(current, []string{"Env", "Lob", "Stale"}, map[string]string{"Env": "Prod", "Lob": "SEC", "CostCenter": "01234"})
+	expected := TagChanges{
+		Add:    map[string]string{"CostCenter": "01234"},
+		Change: map[string]TagChange{"Env": {From: "Dev", To: "Prod"}},
+		Remove: []string{"Stale"},
+	}
+	if !cmp.Equal(changes, expected) {
+		t.Fatal("Unexpected tag changes: ", cmp.Diff(expected, changes))
+	}
+}
+
+func TestReadOnlyClient(t *testing.T) {
+	org := fakeorg.NewClient()
+	svc := ReadOnlyClient{OrganizationsAPI: org}
+
+	//test that the calls the planners make reach the organization
+	roots, error := svc.ListRoots(&organizations.ListRootsInput{})
+	if error != nil || len(roots.Roots) != 1 || org.Calls("ListRoots") != 1 {
+		t.Fatal("ListRoots was expected to be allowed: ", roots, error)
+	}
+
+	//test that every other call is refused, whether or not it would change the organization
+	_, error = svc.CreateAccount(&organizations.CreateAccountInput{AccountName: aws.String("AWS_SEC_test_Dev")})
+	if error == nil || org.Calls("CreateAccount") != 0 {
+		t.Fatal("CreateAccount was expected to be refused")
+	}
+	_, error = svc.DescribeOrganization(&organizations.DescribeOrganizationInput{})
+	if error == nil {
+		t.Fatal("DescribeOrganization was expected to be refused")
+	}
+	request, _ := svc.ListRootsRequest(&organizations.ListRootsInput{})
+	if request.Send() == nil {
+		t.Fatal("ListRootsRequest was expected to be refused")
+	}
+	error = svc.ListAccountsPages(&organizations.ListAccountsInput{}, func(*organizations.ListAccountsOutput, bool) bool { return true })
+	if error == nil || org.Calls("ListAccounts") != 0 {
+		t.Fatal("ListAccountsPages was expected to be refused")
+	}
+}
@@ -0,0 +1,599 @@
+// Package fakeorg is an in-memory AWS Organizations. Only tests and orgclient, which builds the organization of
+// the Lambdas' simulated mode, import it.
+package fakeorg
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+)
+
+// RootID is the ID of the root of every Client.
+const RootID = "r-fake"
+
+// Client is an in-memory organization that keeps the state the calls made to it change: a root,
+// nested OUs, accounts and their tags. It checks the parents and IDs it is given the way Organizations does and
+// returns the same error codes. Calls that are not implemented panic through the embedded nil interface.
+//
+// CreateAccount requests start out IN_PROGRESS and are settled by DescribeCreateAccountStatus, so the account only
+// appears in the root once its request has succeeded. A request fails instead when its email is already in use or
+// its name was passed to FailAccountCreation.
+type Client struct {
+	organizationsiface.OrganizationsAPI
+
+	// PageSize splits list responses into pages of that size when it is set, or of the MaxResults asked for when
+	// that is smaller
+	PageSize int
+	// CreatePolls is how many DescribeCreateAccountStatus calls report a new request IN_PROGRESS before it is settled
+	CreatePolls int
+
+	mu       sync.Mutex
+	nodes    map[string]*fakeNode
+	order    []string
+	requests map[string]*fakeCreateRequest
+	failures map[string]string
+	faults   map[string]error
+	calls    map[string]int
+	nextID   int
+}
+
+// fakeNode is the root, an OU or an account of a Client.
+type fakeNode struct {
+	parentID string
+	ou       *organizations.OrganizationalUnit
+	account  *organizations.Account
+	tags     map[string]string
+}
+
+type fakeCreateRequest struct {
+	status organizations.CreateAccountStatus
+	email  string
+	polls  int
+}
+
+func NewClient() *Client {
+	c := &Client{
+		nodes:    map[string]*fakeNode{},
+		requests: map[string]*fakeCreateRequest{},
+		failures: map[string]string{},
+		faults:   map[string]error{},
+		calls:    map[string]int{},
+	}
+	c.add(RootID, &fakeNode{})
+	return c
+}
+
+func (c *Client) add(id string, node *fakeNode) {
+	node.tags = map[string]string{}
+	c.nodes[id] = node
+	c.order = append(c.order, id)
+}
+
+func (c *Client) id(format string) string {
+	c.nextID++
+	return fmt.Sprintf(format, c.nextID)
+}
+
+// AddOU adds an OU named name under parentID and returns its ID. An empty id is replaced by a generated one, and
+// an OU that is already in the organization is left as it is.
+func (c *Client) AddOU(parentID string, id string, name string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.addOU(parentID, id, name)
+}
+
+// AddOUPath adds the OUs on path, such as /Workloads/Dev, that are not in the organization yet and returns the ID
+// of the last one.
+func (c *Client) AddOUPath(path string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id := RootID
+	for _, name := range strings.Split(strings.Trim(path, "/"), "/") {
+		parentID := id
+		id = ""
+		for _, child := range c.children(parentID, false) {
+			if aws.StringValue(c.nodes[child].ou.Name) == name {
+				id = child
+			}
+		}
+		if id == "" {
+			id = c.addOU(parentID, "", name)
+		}
+	}
+	return id
+}
+
+func (c *Client) addOU(parentID string, id string, name string) string {
+	if id == "" {
+		id = c.id("ou-fake-%08d")
+	}
+	if _, ok := c.nodes[id]; !ok {
+		c.add(id, &fakeNode{parentID: parentID, ou: &organizations.OrganizationalUnit{Id: aws.String(id), Name: aws.String(name)}})
+	}
+	return id
+}
+
+// AddAccount adds an ACTIVE account under parentID, tagged with tags, and returns its ID.
+func (c *Client) AddAccount(parentID string, name string, email string, tags map[string]string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id := c.addAccount(parentID, name, email)
+	for key, value := range tags {
+		c.nodes[id].tags[key] = value
+	}
+	return id
+}
+
+func (c *Client) addAccount(parentID string, name string, email string) string {
+	id := c.id("%012d")
+	account := &organizations.Account{
+		Id:     aws.String(id),
+		Arn:    aws.String("arn:aws:organizations::000000000000:account/o-fake/" + id),
+		Name:   aws.String(name),
+		Email:  aws.String(email),
+		Status: aws.String(organizations.AccountStatusActive),
+	}
+	c.add(id, &fakeNode{parentID: parentID, account: account})
+	return id
+}
+
+// FailAccountCreation makes the requests that create an account named name fail with reason.
+func (c *Client) FailAccountCreation(name string, reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.failures[name] = reason
+}
+
+// Fault makes every call to operation, e.g. "MoveAccount", return err until it is cleared with a nil err.
+func (c *Client) Fault(operation string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err == nil {
+		delete(c.faults, operation)
+		return
+	}
+	c.faults[operation] = err
+}
+
+// Calls returns how many times operation has been called, including the calls that failed.
+func (c *Client) Calls(operation string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.calls[operation]
+}
+
+// Parent returns the ID of the parent of the OU or account id, empty when there is no such OU or account.
+func (c *Client) Parent(id string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if node, ok := c.nodes[id]; ok {
+		return node.parentID
+	}
+	return ""
+}
+
+// Tags returns a copy of the tags of the OU or account id.
+func (c *Client) Tags(id string) map[string]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tags := map[string]string{}
+	if node, ok := c.nodes[id]; ok {
+		for key, value := range node.tags {
+			tags[key] = value
+		}
+	}
+	return tags
+}
+
+// fault counts a call to operation and returns the fault injected for it.
+func (c *Client) fault(operation string) error {
+	c.calls[operation]++
+	return c.faults[operation]
+}
+
+// children returns the IDs of the OUs, or the accounts, directly under parentID in the order they were added.
+func (c *Client) children(parentID string, accounts bool) []string {
+	var ids []string
+	for _, id := range c.order {
+		node := c.nodes[id]
+		if node.parentID == parentID && id != RootID && (node.account != nil) == accounts {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// page returns the bounds of the page of n items starting at nextToken and the token for the page after it.
+func (c *Client) page(n int, nextToken *string, maxResults *int64) (int, int, *string, error) {
+	start := 0
+	if nextToken != nil {
+		var error error
+		start, error = strconv.Atoi(*nextToken)
+		if error != nil || start < 0 || start > n {
+			return 0, 0, nil, awserr.New(organizations.ErrCodeInvalidInputException, "invalid NextToken", nil)
+		}
+	}
+	pageSize := c.PageSize
+	if maxResults != nil && (pageSize == 0 || int(*maxResults) < pageSize) {
+		pageSize = int(*maxResults)
+	}
+	if pageSize == 0 || start+pageSize >= n {
+		return start, n, nil, nil
+	}
+	token := strconv.Itoa(start + pageSize)
+	return start, start + pageSize, &token, nil
+}
+
+// isParent reports whether id is the root or an OU.
+func (c *Client) isParent(id string) bool {
+	node, ok := c.nodes[id]
+	return ok && node.account == nil
+}
+
+func (c *Client) ListRoots(input *organizations.ListRootsInput) (*organizations.ListRootsOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if error := c.fault("ListRoots"); error != nil {
+		return nil, error
+	}
+
+	root := &organizations.Root{Id: aws.String(RootID), Name: aws.String("Root")}
+	output := &organizations.ListRootsOutput{Roots: []*organizations.Root{root}}
+	return output, nil
+}
+
+func (c *Client) ListOrganizationalUnitsForParent(input *organizations.ListOrganizationalUnitsForParentInput) (*organizations.ListOrganizationalUnitsForParentOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if error := c.fault("ListOrganizationalUnitsForParent"); error != nil {
+		return nil, error
+	}
+
+	parentID := aws.StringValue(input.ParentId)
+	if !c.isParent(parentID) {
+		return nil, awserr.New(organizations.ErrCodeParentNotFoundException, "no root or OU with ID "+parentID, nil)
+	}
+	ids := c.children(parentID, false)
+	start, end, nextToken, error := c.page(len(ids), input.NextToken, input.MaxResults)
+	if error != nil {
+		return nil, error
+	}
+	output := &organizations.ListOrganizationalUnitsForParentOutput{NextToken: nextToken}
+	for _, id := range ids[start:end] {
+		ou := *c.nodes[id].ou
+		output.OrganizationalUnits = append(output.OrganizationalUnits, &ou)
+	}
+	return output, nil
+}
+
+func (c *Client) DescribeOrganizationalUnit(input *organizations.DescribeOrganizationalUnitInput) (*organizations.DescribeOrganizationalUnitOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if error := c.fault("DescribeOrganizationalUnit"); error != nil {
+		return nil, error
+	}
+
+	node, ok := c.nodes[aws.StringValue(input.OrganizationalUnitId)]
+	if !ok || node.ou == nil {
+		return nil, awserr.New(organizations.ErrCodeOrganizationalUnitNotFoundException, "no OU with ID "+aws.StringValue(input.OrganizationalUnitId), nil)
+	}
+	ou := *node.ou
+	output := &organizations.DescribeOrganizationalUnitOutput{OrganizationalUnit: &ou}
+	return output, nil
+}
+
+func (c *Client) CreateOrganizationalUnit(input *organizations.CreateOrganizationalUnitInput) (*organizations.CreateOrganizationalUnitOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if error := c.fault("CreateOrganizationalUnit"); error != nil {
+		return nil, error
+	}
+
+	parentID, name := aws.StringValue(input.ParentId), aws.StringValue(input.Name)
+	if !c.isParent(parentID) {
+		return nil, awserr.New(organizations.ErrCodeParentNotFoundException, "no root or OU with ID "+parentID, nil)
+	}
+	for _, id := range c.children(parentID, false) {
+		if aws.StringValue(c.nodes[id].ou.Name) == name {
+			return nil, awserr.New(organizations.ErrCodeDuplicateOrganizationalUnitException, "an OU named "+name+" already exists", nil)
+		}
+	}
+
+	id := c.addOU(parentID, "", name)
+	ou := c.nodes[id].ou
+	for _, tag := range input.Tags {
+		c.nodes[id].tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	created := *ou
+	output := &organizations.CreateOrganizationalUnitOutput{OrganizationalUnit: &created}
+	return output, nil
+}
+
+func (c *Client) ListAccounts(input *organizations.ListAccountsInput) (*organizations.ListAccountsOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if error := c.fault("ListAccounts"); error != nil {
+		return nil, error
+	}
+
+	var ids []string
+	for _, id := range c.order {
+		if c.nodes[id].account != nil {
+			ids = append(ids, id)
+		}
+	}
+	start, end, nextToken, error := c.page(len(ids), input.NextToken, input.MaxResults)
+	if error != nil {
+		return nil, error
+	}
+	output := &organizations.ListAccountsOutput{NextToken: nextToken}
+	for _, id := range ids[start:end] {
+		account := *c.nodes[id].account
+		output.Accounts = append(output.Accounts, &account)
+	}
+	return output, nil
+}
+
+func (c *Client) ListAccountsForParent(input *organizations.ListAccountsForParentInput) (*organizations.ListAccountsForParentOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if error := c.fault("ListAccountsForParent"); error != nil {
+		return nil, error
+	}
+
+	parentID := aws.StringValue(input.ParentId)
+	if !c.isParent(parentID) {
+		return nil, awserr.New(organizations.ErrCodeParentNotFoundException, "no root or OU with ID "+parentID, nil)
+	}
+	ids := c.children(parentID, true)
+	start, end, nextToken, error := c.page(len(ids), input.NextToken, input.MaxResults)
+	if error != nil {
+		return nil, error
+	}
+	output := &organizations.ListAccountsForParentOutput{NextToken: nextToken}
+	for _, id := range ids[start:end] {
+		account := *c.nodes[id].account
+		output.Accounts = append(output.Accounts, &account)
+	}
+	return output, nil
+}
+
+func (c *Client) DescribeAccount(input *organizations.DescribeAccountInput) (*organizations.DescribeAccountOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if error := c.fault("DescribeAccount"); error != nil {
+		return nil, error
+	}
+
+	node, ok := c.nodes[aws.StringValue(input.AccountId)]
+	if !ok || node.account == nil {
+		return nil, awserr.New(organizations.ErrCodeAccountNotFoundException, "no account with ID "+aws.StringValue(input.AccountId), nil)
+	}
+	account := *node.account
+	output := &organizations.DescribeAccountOutput{Account: &account}
+	return output, nil
+}
+
+func (c *Client) CreateAccount(input *organizations.CreateAccountInput) (*organizations.CreateAccountOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if error := c.fault("CreateAccount"); error != nil {
+		return nil, error
+	}
+
+	request := &fakeCreateRequest{
+		status: organizations.CreateAccountStatus{
+			Id:          aws.String(c.id("car-fake%08d")),
+			AccountName: input.AccountName,
+			State:       aws.String(organizations.CreateAccountStateInProgress),
+		},
+		email: aws.StringValue(input.Email),
+		polls: c.CreatePolls,
+	}
+	c.requests[*request.status.Id] = request
+	status := request.status
+	output := &organizations.CreateAccountOutput{CreateAccountStatus: &status}
+	return output, nil
+}
+
+func (c *Client) DescribeCreateAccountStatus(input *organizations.DescribeCreateAccountStatusInput) (*organizations.DescribeCreateAccountStatusOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if error := c.fault("DescribeCreateAccountStatus"); error != nil {
+		return nil, error
+	}
+
+	request, ok := c.requests[aws.StringValue(input.CreateAccountRequestId)]
+	if !ok {
+		return nil, awserr.New(organizations.ErrCodeCreateAccountStatusNotFoundException, "no create account request with ID "+aws.StringValue(input.CreateAccountRequestId), nil)
+	}
+	if *request.status.State == organizations.CreateAccountStateInProgress {
+		if request.polls > 0 {
+			request.polls--
+		} else {
+			c.settle(request)
+		}
+	}
+	status := request.status
+	output := &organizations.DescribeCreateAccountStatusOutput{CreateAccountStatus: &status}
+	return output, nil
+}
+
+// settle ends an IN_PROGRESS request, creating its account in the root unless it fails.
+func (c *Client) settle(request *fakeCreateRequest) {
+	name := aws.StringValue(request.status.AccountName)
+	reason, ok := c.failures[name]
+	for _, id := range c.order {
+		if account := c.nodes[id].account; account != nil && strings.EqualFold(aws.StringValue(account.Email), request.email) {
+			reason, ok = organizations.CreateAccountFailureReasonEmailAlreadyExists, true
+		}
+	}
+	if ok {
+		request.status.State = aws.String(organizations.CreateAccountStateFailed)
+		request.status.FailureReason = aws.String(reason)
+		return
+	}
+	request.status.State = aws.String(organizations.CreateAccountStateSucceeded)
+	request.status.AccountId = aws.String(c.addAccount(RootID, name, request.email))
+}
+
+func (c *Client) ListParents(input *organizations.ListParentsInput) (*organizations.ListParentsOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if error := c.fault("ListParents"); error != nil {
+		return nil, error
+	}
+
+	node, ok := c.nodes[aws.StringValue(input.ChildId)]
+	if !ok || aws.StringValue(input.ChildId) == RootID {
+		return nil, awserr.New(organizations.ErrCodeChildNotFoundException, "no account or OU with ID "+aws.StringValue(input.ChildId), nil)
+	}
+	parentType := organizations.ParentTypeOrganizationalUnit
+	if node.parentID == RootID {
+		parentType = organizations.ParentTypeRoot
+	}
+	parent := &organizations.Parent{Id: aws.String(node.parentID), Type: aws.String(parentType)}
+	output := &organizations.ListParentsOutput{Parents: []*organizations.Parent{parent}}
+	return output, nil
+}
+
+// MoveAccount moves the account when SourceParentId is the parent it is in now and DestinationParentId exists.
+func (c *Client) MoveAccount(input *organizations.MoveAccountInput) (*organizations.MoveAccountOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if error := c.fault("MoveAccount"); error != nil {
+		return nil, error
+	}
+
+	accountID := aws.StringValue(input.AccountId)
+	source, destination := aws.StringValue(input.SourceParentId), aws.StringValue(input.DestinationParentId)
+	node, ok := c.nodes[accountID]
+	if !ok || node.account == nil {
+		return nil, awserr.New(organizations.ErrCodeAccountNotFoundException, "no account with ID "+accountID, nil)
+	}
+	if !c.isParent(source) {
+		return nil, awserr.New(organizations.ErrCodeSourceParentNotFoundException, "no root or OU with ID "+source, nil)
+	}
+	if !c.isParent(destination) {
+		return nil, awserr.New(organizations.ErrCodeDestinationParentNotFoundException, "no root or OU with ID "+destination, nil)
+	}
+	if node.parentID == destination {
+		return nil, awserr.New(organizations.ErrCodeDuplicateAccountException, "the account is already in "+destination, nil)
+	}
+	if node.parentID != source {
+		return nil, awserr.New(organizations.ErrCodeAccountNotFoundException, "the account is not in "+source, nil)
+	}
+	node.parentID = destination
+	return &organizations.MoveAccountOutput{}, nil
+}
+
+// CloseAccount leaves an ACTIVE account PENDING_CLOSURE, where Organizations keeps it until it is SUSPENDED.
+func (c *Client) CloseAccount(input *organizations.CloseAccountInput) (*organizations.CloseAccountOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if error := c.fault("CloseAccount"); error != nil {
+		return nil, error
+	}
+
+	accountID := aws.StringValue(input.AccountId)
+	node, ok := c.nodes[accountID]
+	if !ok || node.account == nil {
+		return nil, awserr.New(organizations.ErrCodeAccountNotFoundException, "no account with ID "+accountID, nil)
+	}
+	if aws.StringValue(node.account.Status) != organizations.AccountStatusActive {
+		return nil, awserr.New(organizations.ErrCodeAccountAlreadyClosedException, "the account is "+aws.StringValue(node.account.Status), nil)
+	}
+	node.account.Status = aws.String(organizations.AccountStatusPendingClosure)
+	return &organizations.CloseAccountOutput{}, nil
+}
+
+func (c *Client) ListTagsForResource(input *organizations.ListTagsForResourceInput) (*organizations.ListTagsForResourceOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if error := c.fault("ListTagsForResource"); error != nil {
+		return nil, error
+	}
+
+	node, ok := c.nodes[aws.StringValue(input.ResourceId)]
+	if !ok {
+		return nil, awserr.New(organizations.ErrCodeTargetNotFoundException, "no resource with ID "+aws.StringValue(input.ResourceId), nil)
+	}
+	var keys []string
+	for key := range node.tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	start, end, nextToken, error := c.page(len(keys), input.NextToken, nil)
+	if error != nil {
+		return nil, error
+	}
+	output := &organizations.ListTagsForResourceOutput{NextToken: nextToken}
+	for _, key := range keys[start:end] {
+		output.Tags = append(output.Tags, &organizations.Tag{Key: aws.String(key), Value: aws.String(node.tags[key])})
+	}
+	return output, nil
+}
+
+func (c *Client) TagResource(input *organizations.TagResourceInput) (*organizations.TagResourceOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if error := c.fault("TagResource"); error != nil {
+		return nil, error
+	}
+
+	node, ok := c.nodes[aws.StringValue(input.ResourceId)]
+	if !ok {
+		return nil, awserr.New(organizations.ErrCodeTargetNotFoundException, "no resource with ID "+aws.StringValue(input.ResourceId), nil)
+	}
+	for _, tag := range input.Tags {
+		node.tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return &organizations.TagResourceOutput{}, nil
+}
+
+func (c *Client) UntagResource(input *organizations.UntagResourceInput) (*organizations.UntagResourceOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if error := c.fault("UntagResource"); error != nil {
+		return nil, error
+	}
+
+	node, ok := c.nodes[aws.StringValue(input.ResourceId)]
+	if !ok {
+		return nil, awserr.New(organizations.ErrCodeTargetNotFoundException, "no resource with ID "+aws.StringValue(input.ResourceId), nil)
+	}
+	for _, key := range input.TagKeys {
+		delete(node.tags, aws.StringValue(key))
+	}
+	return &organizations.UntagResourceOutput{}, nil
+}
@@ -0,0 +1,167 @@
+package fakeorg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/organizations"
+
+	"go-account-automation/internal/automation"
+)
+
+// awsErrorCode returns the Organizations error code of error, empty when it has none.
+func awsErrorCode(error error) string {
+	if aerr, ok := error.(awserr.Error); ok {
+		return aerr.Code()
+	}
+	return ""
+}
+
+func TestCreateAccount(t *testing.T) {
+	svc := NewClient()
+	svc.CreatePolls = 1
+	svc.AddAccount(RootID, "AWS_SEC_existing_Dev", "AWS_SEC_existing_Dev@example.com", nil)
+	svc.FailAccountCreation("AWS_SEC_broken_Dev", organizations.CreateAccountFailureReasonInternalFailure)
+
+	testCases := []struct {
+		name                  string
+		expectedState         string
+		expectedFailureReason string
+	}{
+		{"AWS_SEC_test_Dev", organizations.CreateAccountStateSucceeded, ""},
+		{"AWS_SEC_broken_Dev", organizations.CreateAccountStateFailed, organizations.CreateAccountFailureReasonInternalFailure},
+		{"AWS_SEC_existing_Dev", organizations.CreateAccountStateFailed, organizations.CreateAccountFailureReasonEmailAlreadyExists},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			output, error := svc.CreateAccount(&organizations.CreateAccountInput{AccountName: aws.String(testCase.name), Email: aws.String(testCase.name + "@example.com")})
+			if error != nil {
+				t.Fatal(error.Error())
+			}
+			input := &organizations.DescribeCreateAccountStatusInput{CreateAccountRequestId: output.CreateAccountStatus.Id}
+
+			//test that the request stays in progress for CreatePolls checks before it is settled
+			for _, expectedState := range []string{organizations.CreateAccountStateInProgress, testCase.expectedState, testCase.expectedState} {
+				status, error := svc.DescribeCreateAccountStatus(input)
+				if error != nil {
+					t.Fatal(error.Error())
+				}
+				if aws.StringValue(status.CreateAccountStatus.State) != expectedState {
+					t.Fatal("Unexpected state: ", status.CreateAccountStatus)
+				}
+				if expectedState == organizations.CreateAccountStateInProgress && status.CreateAccountStatus.AccountId != nil {
+					t.Fatal("An account ID was not expected before the request succeeded")
+				}
+			}
+
+			status, _ := svc.DescribeCreateAccountStatus(input)
+			if aws.StringValue(status.CreateAccountStatus.FailureReason) != testCase.expectedFailureReason {
+				t.Fatal("Unexpected failure reason: ", status.CreateAccountStatus)
+			}
+			if testCase.expectedFailureReason == "" && svc.Parent(aws.StringValue(status.CreateAccountStatus.AccountId)) != RootID {
+				t.Fatal("A created account was expected to start out in the root")
+			}
+		})
+	}
+
+	_, error := svc.DescribeCreateAccountStatus(&organizations.DescribeCreateAccountStatusInput{CreateAccountRequestId: aws.String("car-unknown")})
+	if awsErrorCode(error) != organizations.ErrCodeCreateAccountStatusNotFoundException {
+		t.Fatal("An unknown request was expected to be not found: ", error)
+	}
+}
+
+func TestMoveAccount(t *testing.T) {
+	svc := NewClient()
+	workloads := svc.AddOU(RootID, "", "Workloads")
+	dev := svc.AddOUPath("/Workloads/Dev")
+	accountID := svc.AddAccount(workloads, "AWS_SEC_test_Dev", "AWS_SEC_test_Dev@example.com", nil)
+
+	testCases := []struct {
+		source       string
+		destination  string
+		accountID    string
+		expectedCode string
+	}{
+		{RootID, dev, accountID, organizations.ErrCodeAccountNotFoundException},
+		{"ou-missing", dev, accountID, organizations.ErrCodeSourceParentNotFoundException},
+		{workloads, "ou-missing", accountID, organizations.ErrCodeDestinationParentNotFoundException},
+		{workloads, workloads, accountID, organizations.ErrCodeDuplicateAccountException},
+		{workloads, dev, "000000000000", organizations.ErrCodeAccountNotFoundException},
+		{workloads, dev, accountID, ""},
+	}
+	for _, testCase := range testCases {
+		input := &organizations.MoveAccountInput{AccountId: &testCase.accountID, SourceParentId: &testCase.source, DestinationParentId: &testCase.destination}
+		_, error := svc.MoveAccount(input)
+		if awsErrorCode(error) != testCase.expectedCode || (testCase.expectedCode == "" && error != nil) {
+			t.Fatal("Unexpected error moving ", testCase.accountID, " from ", testCase.source, " to ", testCase.destination, ": ", error)
+		}
+	}
+	if svc.Parent(accountID) != dev {
+		t.Fatal("Account was not moved: ", svc.Parent(accountID))
+	}
+}
+
+func TestListOrganizationalUnitsForParent(t *testing.T) {
+	svc := NewClient()
+	svc.PageSize = 2
+	workloads := svc.AddOU(RootID, "", "Workloads")
+	for _, env := range []string{"Dev", "Test", "Prod"} {
+		svc.AddOU(workloads, "", env)
+	}
+
+	ous, error := automation.ListOrganizationalUnits(svc, workloads)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if len(ous) != 3 || aws.StringValue(ous[2].Name) != "Prod" || svc.Calls("ListOrganizationalUnitsForParent") != 2 {
+		t.Fatal("OUs were not listed across pages: ", ous)
+	}
+
+	_, error = svc.CreateOrganizationalUnit(&organizations.CreateOrganizationalUnitInput{ParentId: &workloads, Name: aws.String("Dev")})
+	if awsErrorCode(error) != organizations.ErrCodeDuplicateOrganizationalUnitException {
+		t.Fatal("A second OU with the same name was expected to fail: ", error)
+	}
+	_, error = automation.ListOrganizationalUnits(svc, "ou-missing")
+	if awsErrorCode(error) != organizations.ErrCodeParentNotFoundException {
+		t.Fatal("Listing a missing parent was expected to fail: ", error)
+	}
+
+	//test that an injected fault is returned until it is cleared
+	svc.Fault("ListOrganizationalUnitsForParent", errors.New("injected failure"))
+	_, error = automation.ListOrganizationalUnits(svc, workloads)
+	if error == nil || error.Error() != "injected failure" {
+		t.Fatal("The injected fault was not returned: ", error)
+	}
+	svc.Fault("ListOrganizationalUnitsForParent", nil)
+	_, error = automation.ListOrganizationalUnits(svc, workloads)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+}
+
+func TestCloseAccount(t *testing.T) {
+	svc := NewClient()
+	accountID := svc.AddAccount(RootID, "AWS_SEC_test_Dev", "AWS_SEC_test_Dev@example.com", nil)
+
+	//test that a closed account is left PENDING_CLOSURE and cannot be closed again
+	testCases := []struct {
+		accountID    string
+		expectedCode string
+	}{
+		{"000000000000", organizations.ErrCodeAccountNotFoundException},
+		{accountID, ""},
+		{accountID, organizations.ErrCodeAccountAlreadyClosedException},
+	}
+	for _, testCase := range testCases {
+		_, error := svc.CloseAccount(&organizations.CloseAccountInput{AccountId: &testCase.accountID})
+		if awsErrorCode(error) != testCase.expectedCode || (testCase.expectedCode == "" && error != nil) {
+			t.Fatal("Unexpected error closing ", testCase.accountID, ": ", error)
+		}
+	}
+	output, error := svc.DescribeAccount(&organizations.DescribeAccountInput{AccountId: &accountID})
+	if error != nil || aws.StringValue(output.Account.Status) != organizations.AccountStatusPendingClosure {
+		t.Fatal("Account was not closed: ", output, error)
+	}
+}
@@ -0,0 +1,76 @@
+//go:build ignore
+
+// gen_readonly writes readonly_organizations.go, which refuses every Organizations operation so ReadOnlyClient only
+// makes the calls it allows. Run it with go generate after upgrading aws-sdk-go.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+)
+
+func main() {
+	var body bytes.Buffer
+	body.WriteString(`// Code generated by gen_readonly.go; DO NOT EDIT.
+
+package automation
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/organizations"
+)
+
+// refusedOrganizations refuses every Organizations operation. ReadOnlyClient embeds it, so any operation it does not
+// allow fails rather than reaching Organizations.
+type refusedOrganizations struct{}
+`)
+
+	api := reflect.TypeOf((*organizationsiface.OrganizationsAPI)(nil)).Elem()
+	for i := 0; i < api.NumMethod(); i++ {
+		method := api.Method(i)
+		var parameters, results, zeros []string
+		for j := 0; j < method.Type.NumIn(); j++ {
+			parameter := method.Type.In(j).String()
+			if method.Type.IsVariadic() && j == method.Type.NumIn()-1 {
+				parameter = "..." + strings.TrimPrefix(parameter, "[]")
+			}
+			parameters = append(parameters, fmt.Sprintf("_ %s", parameter))
+		}
+
+		// a request is refused when it is sent, and an operation that only returns an error is refused outright
+		var statement string
+		for j := 0; j < method.Type.NumOut(); j++ {
+			results = append(results, method.Type.Out(j).String())
+			zeros = append(zeros, "nil")
+		}
+		switch {
+		case method.Type.NumOut() == 1:
+			statement = fmt.Sprintf("return readOnlyError(%q)", method.Name)
+		case results[0] == "*request.Request":
+			zeros[0] = fmt.Sprintf("&request.Request{Error: readOnlyError(%q)}", method.Name)
+			statement = "return " + strings.Join(zeros, ", ")
+		default:
+			zeros[len(zeros)-1] = fmt.Sprintf("readOnlyError(%q)", method.Name)
+			statement = "return " + strings.Join(zeros, ", ")
+		}
+		fmt.Fprintf(&body, "\nfunc (refusedOrganizations) %s(%s) (%s) {\n\t%s\n}\n", method.Name, strings.Join(parameters, ", "), strings.Join(results, ", "), statement)
+	}
+
+	source, error := format.Source(body.Bytes())
+	if error != nil {
+		log.Fatal(error.Error())
+	}
+	error = ioutil.WriteFile("readonly_organizations.go", source, 0644)
+	if error != nil {
+		log.Fatal(error.Error())
+	}
+}
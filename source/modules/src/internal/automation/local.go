@@ -0,0 +1,172 @@
+package automation
+
+import (
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// LocalStage is the API GW stage the local server serves its routes under, as in swagger.json's basePath.
+const LocalStage = "v1"
+
+// LocalCallerLobHeader stands in for the lob the API GW authorizer puts in the request context, which the local
+// server has no authorizer to provide.
+const LocalCallerLobHeader = "X-Local-Caller-Lob"
+
+// LocalRoute is an API GW method and resource served by a Lambda.
+type LocalRoute struct {
+	Method   string
+	Resource string
+}
+
+// LocalHandler handles an API GW proxy request the way the Lambda handler does.
+type LocalHandler func(request events.APIGatewayProxyRequest) (*events.APIGatewayProxyResponse, error)
+
+// LocalLambda is a Lambda's handler and the routes swagger.json sends to it.
+type LocalLambda struct {
+	Name   string
+	Handle LocalHandler
+	Routes []LocalRoute
+}
+
+// ServeLocal serves the routes of every one of lambdas on addr over HTTP, so the API can be tried without deploying
+// the Lambdas behind API GW.
+func ServeLocal(addr string, lambdas ...LocalLambda) error {
+	for _, lambda := range lambdas {
+		log.Println("Serving ", len(lambda.Routes), " ", lambda.Name, " routes locally on ", addr, " under /", LocalStage, "...")
+	}
+	return http.ListenAndServe(addr, NewLocalServer(lambdas...))
+}
+
+// NewLocalServer returns an http.Handler that translates each request for one of the routes of lambdas into the
+// events.APIGatewayProxyRequest API GW would send, and writes back the response the Lambda serving the route returns.
+func NewLocalServer(lambdas ...LocalLambda) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, httpRequest *http.Request) {
+		// a path that one Lambda serves with another method is not allowed rather than not found
+		var request events.APIGatewayProxyRequest
+		var handle LocalHandler
+		status := http.StatusNotFound
+		for _, lambda := range lambdas {
+			var lambdaStatus int
+			request, lambdaStatus = LocalRequest(httpRequest, lambda.Routes)
+			if lambdaStatus != http.StatusNotFound && lambdaStatus != http.StatusMethodNotAllowed {
+				handle, status = lambda.Handle, lambdaStatus
+				break
+			}
+			if lambdaStatus == http.StatusMethodNotAllowed {
+				status = lambdaStatus
+			}
+		}
+
+		var response *events.APIGatewayProxyResponse
+		switch status {
+		case http.StatusOK:
+			var error error
+			response, error = handle(request)
+			if error != nil {
+				// API GW reports a Lambda that returns an error as a bad gateway
+				log.Println("ERROR: ", error.Error())
+				response = &events.APIGatewayProxyResponse{StatusCode: http.StatusBadGateway, Body: `{"message": "Internal server error"}`}
+			}
+		case http.StatusBadRequest:
+			response, _ = HandleErrors(errors.New("error: the request body could not be read"), status)
+		case http.StatusMethodNotAllowed:
+			response, _ = HandleErrors(errors.New("error: method "+httpRequest.Method+" is not allowed for "+httpRequest.URL.Path), status)
+		default:
+			response, _ = HandleErrors(errors.New("error: no route for "+httpRequest.URL.Path), status)
+		}
+		WriteLocalResponse(writer, response)
+	})
+}
+
+// LocalRequest matches httpRequest against routes and builds the events.APIGatewayProxyRequest for it. The status
+// is http.StatusOK when a route matched, http.StatusMethodNotAllowed when only the path did, and
+// http.StatusNotFound otherwise.
+func LocalRequest(httpRequest *http.Request, routes []LocalRoute) (events.APIGatewayProxyRequest, int) {
+	path := strings.TrimPrefix(httpRequest.URL.Path, "/"+LocalStage)
+	if path == httpRequest.URL.Path {
+		return events.APIGatewayProxyRequest{}, http.StatusNotFound
+	}
+
+	status := http.StatusNotFound
+	for _, route := range routes {
+		pathParameters, ok := matchResource(route.Resource, path)
+		if !ok {
+			continue
+		}
+		if !strings.EqualFold(route.Method, httpRequest.Method) {
+			status = http.StatusMethodNotAllowed
+			continue
+		}
+
+		body, error := ioutil.ReadAll(httpRequest.Body)
+		if error != nil {
+			return events.APIGatewayProxyRequest{}, http.StatusBadRequest
+		}
+		request := events.APIGatewayProxyRequest{
+			Resource:                        route.Resource,
+			Path:                            path,
+			HTTPMethod:                      route.Method,
+			Headers:                         map[string]string{},
+			MultiValueHeaders:               map[string][]string(httpRequest.Header),
+			QueryStringParameters:           map[string]string{},
+			MultiValueQueryStringParameters: map[string][]string(httpRequest.URL.Query()),
+			PathParameters:                  pathParameters,
+			RequestContext: events.APIGatewayProxyRequestContext{
+				Stage:        LocalStage,
+				ResourcePath: route.Resource,
+				HTTPMethod:   route.Method,
+				Authorizer:   map[string]interface{}{},
+			},
+			Body: string(body),
+		}
+		for header := range httpRequest.Header {
+			request.Headers[header] = httpRequest.Header.Get(header)
+		}
+		for name, values := range request.MultiValueQueryStringParameters {
+			request.QueryStringParameters[name] = values[0]
+		}
+		if lob := httpRequest.Header.Get(LocalCallerLobHeader); lob != "" {
+			request.RequestContext.Authorizer["lob"] = lob
+		}
+		return request, http.StatusOK
+	}
+	return events.APIGatewayProxyRequest{}, status
+}
+
+// matchResource matches path against a resource such as /accounts/{accountId}, returning the path parameters.
+func matchResource(resource string, path string) (map[string]string, bool) {
+	resourceSegments := strings.Split(strings.Trim(resource, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(resourceSegments) != len(pathSegments) {
+		return nil, false
+	}
+
+	pathParameters := map[string]string{}
+	for i, segment := range resourceSegments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") && pathSegments[i] != "" {
+			pathParameters[strings.Trim(segment, "{}")] = pathSegments[i]
+		} else if segment != pathSegments[i] {
+			return nil, false
+		}
+	}
+	return pathParameters, true
+}
+
+// WriteLocalResponse writes response the way API GW would send it back.
+func WriteLocalResponse(writer http.ResponseWriter, response *events.APIGatewayProxyResponse) {
+	for header, values := range response.MultiValueHeaders {
+		for _, value := range values {
+			writer.Header().Add(header, value)
+		}
+	}
+	for header, value := range response.Headers {
+		writer.Header().Set(header, value)
+	}
+	writer.WriteHeader(response.StatusCode)
+	writer.Write([]byte(response.Body))
+}
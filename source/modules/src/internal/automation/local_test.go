@@ -0,0 +1,45 @@
+package automation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func localLambda(name string, routes ...LocalRoute) LocalLambda {
+	handle := func(request events.APIGatewayProxyRequest) (*events.APIGatewayProxyResponse, error) {
+		return &events.APIGatewayProxyResponse{StatusCode: 200, Body: name + " " + request.Resource}, nil
+	}
+	return LocalLambda{Name: name, Handle: handle, Routes: routes}
+}
+
+func TestNewLocalServer(t *testing.T) {
+	server := NewLocalServer(
+		localLambda("read", LocalRoute{Method: "GET", Resource: "/accounts"}, LocalRoute{Method: "GET", Resource: "/accounts/{accountId}"}),
+		localLambda("update", LocalRoute{Method: "PUT", Resource: "/accounts"}),
+		localLambda("delete", LocalRoute{Method: "DELETE", Resource: "/accounts/{accountId}"}),
+	)
+
+	//test that each route is served by the Lambda it belongs to, whichever Lambda is mounted first
+	testCases := []struct {
+		method         string
+		target         string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"GET", "/v1/accounts", http.StatusOK, "read /accounts"},
+		{"PUT", "/v1/accounts", http.StatusOK, "update /accounts"},
+		{"DELETE", "/v1/accounts/111111111111", http.StatusOK, "delete /accounts/{accountId}"},
+		{"POST", "/v1/accounts", http.StatusMethodNotAllowed, ""},
+		{"GET", "/v1/accounts/111111111111/move", http.StatusNotFound, ""},
+	}
+	for _, testCase := range testCases {
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(testCase.method, testCase.target, nil))
+		if recorder.Code != testCase.expectedStatus || (testCase.expectedBody != "" && recorder.Body.String() != testCase.expectedBody) {
+			t.Fatal("Unexpected response for ", testCase.method, " ", testCase.target, ": ", recorder.Code, " ", recorder.Body.String())
+		}
+	}
+}
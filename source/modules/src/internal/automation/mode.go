@@ -0,0 +1,55 @@
+package automation
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// Mode is how a Lambda's Handler carries out the requests it is given.
+type Mode string
+
+const (
+	// ModeLive calls AWS for real.
+	ModeLive Mode = "live"
+	// ModeDryRun makes the read-only calls for real and answers every request that would change the organization
+	// with a plan, as if it asked for a dry run.
+	ModeDryRun Mode = "dry-run"
+	// ModeSimulated makes no AWS calls at all, running requests against a simulated organization.
+	ModeSimulated Mode = "simulated"
+)
+
+// ParseMode returns the Mode named by value, ignoring case.
+func ParseMode(value string) (Mode, error) {
+	for _, mode := range []Mode{ModeLive, ModeDryRun, ModeSimulated} {
+		if strings.EqualFold(value, string(mode)) {
+			return mode, nil
+		}
+	}
+	return "", errors.New("error: unknown execution mode " + value + ", expected live, dry-run or simulated")
+}
+
+// ModeFromEnv returns the mode named by the EXECUTION_MODE environment variable. When it is not set, production
+// (RUNTIME_ENV=prod) runs live and every other environment is simulated.
+func ModeFromEnv() (Mode, error) {
+	if value := os.Getenv("EXECUTION_MODE"); value != "" {
+		return ParseMode(value)
+	}
+	if strings.EqualFold(os.Getenv("RUNTIME_ENV"), "prod") {
+		return ModeLive, nil
+	}
+	return ModeSimulated, nil
+}
+
+// OrganizationsConfig assumes the ASSUME_ROLE_ARN role when it is set, and otherwise uses the session's own
+// credentials, as when an operator runs a command.
+func OrganizationsConfig(sess *session.Session) *aws.Config {
+	if roleARN := os.Getenv("ASSUME_ROLE_ARN"); roleARN != "" {
+		return &aws.Config{Credentials: stscreds.NewCredentials(sess, roleARN)}
+	}
+	return &aws.Config{}
+}
@@ -0,0 +1,42 @@
+package automation
+
+import (
+	"os"
+	"testing"
+)
+
+func TestModeFromEnv(t *testing.T) {
+	defer os.Setenv("RUNTIME_ENV", os.Getenv("RUNTIME_ENV"))
+	defer os.Setenv("EXECUTION_MODE", os.Getenv("EXECUTION_MODE"))
+
+	testCases := []struct {
+		runtimeEnv    string
+		executionMode string
+		expectedMode  Mode
+	}{
+		{"prod", "", ModeLive},
+		{"PROD", "", ModeLive},
+		{"dev", "", ModeSimulated},
+		{"", "", ModeSimulated},
+		{"prod", "dry-run", ModeDryRun},
+		{"dev", "Live", ModeLive},
+	}
+	for _, testCase := range testCases {
+		os.Setenv("RUNTIME_ENV", testCase.runtimeEnv)
+		os.Setenv("EXECUTION_MODE", testCase.executionMode)
+		mode, error := ModeFromEnv()
+		if error != nil {
+			t.Fatal(error.Error())
+		}
+		if mode != testCase.expectedMode {
+			t.Fatal("Unexpected mode for ", testCase.runtimeEnv, "/", testCase.executionMode, ": ", mode)
+		}
+	}
+
+	//test that an unknown mode is an error rather than falling back to live
+	os.Setenv("EXECUTION_MODE", "mock")
+	_, error := ModeFromEnv()
+	if error == nil {
+		t.Fatal("An unknown execution mode was expected to fail but didn't")
+	}
+}
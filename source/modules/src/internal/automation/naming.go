@@ -0,0 +1,253 @@
+package automation
+
+import (
+	"errors"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultNamingPattern is the account naming convention used when ACCOUNT_NAMING_CONVENTION does not give one.
+const DefaultNamingPattern = "{prefix}_{lob}_{app}_{env}"
+
+// DefaultNamingSegments are the segments every convention knows, as swagger.json's name pattern
+// ^AWS_[A-Z]+_[a-zA-Z0-9]+_(Lab|Dev|Test|Prod)$ describes them, so a name API GW accepts parses and a generated
+// name is one API GW accepts. Keep the two the same when you edit either.
+var DefaultNamingSegments = map[string]NamingSegment{
+	"prefix": {Regex: "AWS", Default: "AWS"},
+	"lob":    {Regex: "[A-Z]+"},
+	"app":    {Regex: "[a-zA-Z0-9]+"},
+	"env":    {Regex: "Lab|Dev|Test|Prod"},
+}
+
+// segmentNamePattern matches the names a {segment} placeholder may have, which name its group in the compiled pattern.
+var segmentNamePattern = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// NamingSegment describes one {segment} of a naming convention: the regex its value must match in full, and the
+// value it is given when a name is generated without one.
+type NamingSegment struct {
+	Regex   string `json:"regex" yaml:"regex"`
+	Default string `json:"default" yaml:"default"`
+}
+
+// NamingConvention parses and generates account names following Pattern, a template such as
+// "{prefix}_{lob}_{app}_{env}" whose {segment} placeholders are separated by literal delimiters.
+type NamingConvention struct {
+	Pattern  string                   `json:"pattern" yaml:"pattern"`
+	Segments map[string]NamingSegment `json:"segments" yaml:"segments"`
+
+	parts    []namingPart
+	regexps  map[string]*regexp.Regexp
+	compiled *regexp.Regexp
+}
+
+// namingPart is either a literal delimiter or a segment of a naming convention's pattern.
+type namingPart struct {
+	literal string
+	segment string
+}
+
+// NamingError is an account name that does not follow the naming convention, reported to the caller as a 400
+// with the segment that is wrong.
+type NamingError struct {
+	Message    string `json:"message"`
+	Name       string `json:"name"`
+	Convention string `json:"convention"`
+	Segment    string `json:"segment,omitempty"`
+	// Code is one of too_few_segments, too_many_segments, invalid_segment, malformed_name and missing_segment.
+	Code string `json:"code"`
+	// Retryable is always false, and is there for the body to have the same code, message and retryable as an APIError.
+	Retryable bool `json:"retryable"`
+}
+
+func (e *NamingError) Error() string {
+	return e.Message
+}
+
+// ParseNamingConvention reads a JSON or YAML naming convention document. Segments it leaves out, and the pattern
+// when it has none, are taken from the defaults.
+func ParseNamingConvention(document []byte) (*NamingConvention, error) {
+	var convention NamingConvention
+	error := yaml.Unmarshal(document, &convention)
+	if error != nil {
+		return nil, errors.New("error: naming convention could not be parsed: " + error.Error())
+	}
+	return NewNamingConvention(convention.Pattern, convention.Segments)
+}
+
+// NewNamingConvention checks pattern and compiles the regexes of its segments, taking any segment not in
+// segments from DefaultNamingSegments.
+func NewNamingConvention(pattern string, segments map[string]NamingSegment) (*NamingConvention, error) {
+	if pattern == "" {
+		pattern = DefaultNamingPattern
+	}
+	convention := &NamingConvention{Pattern: pattern, Segments: map[string]NamingSegment{}, regexps: map[string]*regexp.Regexp{}}
+
+	placeholders := placeholderPattern.FindAllStringSubmatchIndex(pattern, -1)
+	if strings.Count(pattern, "{") != len(placeholders) || strings.Count(pattern, "}") != len(placeholders) {
+		return nil, errors.New("error: naming convention has a malformed pattern " + pattern)
+	}
+
+	full := "^"
+	last := 0
+	for _, placeholder := range placeholders {
+		if literal := pattern[last:placeholder[0]]; literal != "" {
+			convention.parts = append(convention.parts, namingPart{literal: literal})
+			full += regexp.QuoteMeta(literal)
+		} else if last > 0 {
+			return nil, errors.New("error: naming convention pattern " + pattern + " needs a delimiter between each segment")
+		}
+		last = placeholder[1]
+
+		name := strings.ToLower(pattern[placeholder[2]:placeholder[3]])
+		if !segmentNamePattern.MatchString(name) {
+			return nil, errors.New("error: naming convention pattern " + pattern + " has an invalid segment name " + name)
+		}
+		if _, ok := convention.regexps[name]; ok {
+			return nil, errors.New("error: naming convention pattern " + pattern + " uses segment " + name + " more than once")
+		}
+		segment, ok := segments[name]
+		if !ok {
+			segment, ok = DefaultNamingSegments[name]
+		}
+		if !ok || segment.Regex == "" {
+			return nil, errors.New("error: naming convention segment " + name + " has no regex")
+		}
+		segmentRegexp, error := regexp.Compile("^(?:" + segment.Regex + ")$")
+		if error != nil {
+			return nil, errors.New("error: naming convention segment " + name + " has an invalid regex: " + error.Error())
+		}
+		convention.parts = append(convention.parts, namingPart{segment: name})
+		convention.Segments[name], convention.regexps[name] = segment, segmentRegexp
+		full += "(?P<" + name + ">" + segment.Regex + ")"
+	}
+	if literal := pattern[last:]; literal != "" {
+		convention.parts = append(convention.parts, namingPart{literal: literal})
+		full += regexp.QuoteMeta(literal)
+	}
+
+	for _, name := range []string{"lob", "env"} {
+		if _, ok := convention.regexps[name]; !ok {
+			return nil, errors.New("error: naming convention pattern " + pattern + " must have a {" + name + "} segment")
+		}
+	}
+	compiled, error := regexp.Compile(full + "$")
+	if error != nil {
+		return nil, errors.New("error: naming convention pattern " + pattern + " could not be compiled: " + error.Error())
+	}
+	convention.compiled = compiled
+	return convention, nil
+}
+
+// LoadNamingConvention parses the naming convention document in the ACCOUNT_NAMING_CONVENTION environment variable,
+// or returns the default AWS_LOB_name_Env convention when there is none.
+func LoadNamingConvention() (*NamingConvention, error) {
+	document := os.Getenv("ACCOUNT_NAMING_CONVENTION")
+	if strings.TrimSpace(document) == "" {
+		return NewNamingConvention(DefaultNamingPattern, nil)
+	}
+	return ParseNamingConvention([]byte(document))
+}
+
+// nameError returns a *NamingError for name with message added to the convention it breaks.
+func (c *NamingConvention) nameError(name string, segment string, code string, message string) *NamingError {
+	return &NamingError{
+		Message:    "error: account name " + name + " does not follow the " + c.Pattern + " convention: " + message,
+		Name:       name,
+		Convention: c.Pattern,
+		Segment:    segment,
+		Code:       code,
+	}
+}
+
+// hasDelimiter reports whether value holds one of the pattern's delimiters, i.e. more than one segment.
+func (c *NamingConvention) hasDelimiter(value string) bool {
+	for _, part := range c.parts {
+		if part.literal != "" && strings.Contains(value, part.literal) {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse returns the value of each segment of name, keyed by segment name. A name that does not follow the
+// convention is returned as a *NamingError.
+func (c *NamingConvention) Parse(name string) (map[string]string, error) {
+	values := map[string]string{}
+	if match := c.compiled.FindStringSubmatch(name); match != nil {
+		for segment := range c.regexps {
+			values[segment] = match[c.compiled.SubexpIndex(segment)]
+		}
+		return values, nil
+	}
+
+	// walk the pattern to report which segment the name breaks
+	rest := name
+	for i, part := range c.parts {
+		if part.segment == "" {
+			if !strings.HasPrefix(rest, part.literal) {
+				return nil, c.nameError(name, "", "malformed_name", "expected "+strconv.Quote(part.literal)+" at "+strconv.Quote(rest))
+			}
+			rest = rest[len(part.literal):]
+			continue
+		}
+
+		value := rest
+		if i+1 < len(c.parts) {
+			end := strings.Index(rest, c.parts[i+1].literal)
+			if end < 0 {
+				return nil, c.nameError(name, part.segment, "too_few_segments", "it has fewer segments than the convention, ending at {"+part.segment+"}")
+			}
+			value = rest[:end]
+		} else if c.hasDelimiter(value) {
+			return nil, c.nameError(name, part.segment, "too_many_segments", "it has more segments than the convention after {"+part.segment+"}")
+		}
+		if !c.regexps[part.segment].MatchString(value) {
+			return nil, c.nameError(name, part.segment, "invalid_segment", "segment {"+part.segment+"} is "+strconv.Quote(value)+", which does not match "+c.Segments[part.segment].Regex)
+		}
+		values[part.segment] = value
+		rest = rest[len(value):]
+	}
+	if rest != "" {
+		return nil, c.nameError(name, "", "too_many_segments", "it has more segments than the convention")
+	}
+	return nil, c.nameError(name, "", "malformed_name", "it does not match the segment regexes")
+}
+
+// Generate builds the account name from values, keyed by segment name, taking a segment's default when values does
+// not have it. A value that cannot be used is returned as a *NamingError.
+func (c *NamingConvention) Generate(values map[string]string) (string, error) {
+	name := ""
+	for _, part := range c.parts {
+		if part.segment == "" {
+			name += part.literal
+			continue
+		}
+
+		value, _ := attribute(values, part.segment)
+		if value == "" {
+			value = c.Segments[part.segment].Default
+		}
+		if value == "" {
+			return "", &NamingError{
+				Message:    "error: account name cannot be generated for the " + c.Pattern + " convention without a value for {" + part.segment + "}",
+				Convention: c.Pattern,
+				Segment:    part.segment,
+				Code:       "missing_segment",
+			}
+		}
+		if c.hasDelimiter(value) || !c.regexps[part.segment].MatchString(value) {
+			return "", &NamingError{
+				Message:    "error: account name cannot be generated for the " + c.Pattern + " convention: " + part.segment + " " + strconv.Quote(value) + " does not match " + c.Segments[part.segment].Regex,
+				Convention: c.Pattern,
+				Segment:    part.segment,
+				Code:       "invalid_segment",
+			}
+		}
+		name += value
+	}
+	return name, nil
+}
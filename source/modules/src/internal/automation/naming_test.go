@@ -0,0 +1,158 @@
+package automation
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNamingConventionParse(t *testing.T) {
+	convention, error := NewNamingConvention("", nil)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	values, error := convention.Parse("AWS_SEC_test_Dev")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if !cmp.Equal(values, map[string]string{"prefix": "AWS", "lob": "SEC", "app": "test", "env": "Dev"}) {
+		t.Fatal("Account name was not parsed as expected: ", values)
+	}
+
+	testCases := []struct {
+		name            string
+		accountName     string
+		expectedCode    string
+		expectedSegment string
+	}{
+		{"too few segments", "AWS_SEC_Dev", "too_few_segments", "app"},
+		{"too many segments", "AWS_SEC_my_app_Dev", "too_many_segments", "env"},
+		{"unknown env", "AWS_SEC_test_Staging", "invalid_segment", "env"},
+		{"other prefix", "gcp_SEC_test_Dev", "invalid_segment", "prefix"},
+		{"lower case prefix", "aws_SEC_test_Dev", "invalid_segment", "prefix"},
+		{"lower case lob", "AWS_sec_test_Dev", "invalid_segment", "lob"},
+		{"upper case env", "AWS_SEC_test_DEV", "invalid_segment", "env"},
+		{"empty", "", "too_few_segments", "prefix"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, error := convention.Parse(testCase.accountName)
+			namingError, ok := error.(*NamingError)
+			if !ok || namingError.Code != testCase.expectedCode || namingError.Segment != testCase.expectedSegment {
+				t.Fatal("Unexpected naming error: ", error)
+			}
+			if namingError.Name != testCase.accountName || namingError.Convention != DefaultNamingPattern {
+				t.Fatal("Naming error does not name the account and convention: ", namingError)
+			}
+		})
+	}
+
+	//test that delimiters other than _ and segment regexes that allow them are used
+	convention, error = NewNamingConvention("{lob}-{app}.{env}", map[string]NamingSegment{"app": {Regex: "[a-z]+(-[a-z]+)*"}})
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	values, error = convention.Parse("SEC-my-app.Prod")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if !cmp.Equal(values, map[string]string{"lob": "SEC", "app": "my-app", "env": "Prod"}) {
+		t.Fatal("Account name was not parsed as expected: ", values)
+	}
+}
+
+func TestDefaultNamingConventionMatchesSwagger(t *testing.T) {
+	document, error := os.ReadFile("../../../json/swagger.json")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	patterns := regexp.MustCompile(`"name": \{\s*"type": "string",\s*"pattern": "([^"]+)"`).FindAllSubmatch(document, -1)
+	if len(patterns) == 0 {
+		t.Fatal("swagger.json has no name pattern")
+	}
+	convention, _ := NewNamingConvention("", nil)
+	for _, pattern := range patterns {
+		swaggerPattern := regexp.MustCompile(strings.ReplaceAll(string(pattern[1]), `\\`, `\`))
+		for _, name := range []string{"AWS_SEC_test_Dev", "AWS_APP_billing2_Prod", "aws_SEC_test_Dev", "AWS_sec_test_Dev", "AWS_SEC_test_DEV", "AWS_SEC_my_app_Dev", "AWS_SEC_test_Staging"} {
+			_, error := convention.Parse(name)
+			if swaggerPattern.MatchString(name) != (error == nil) {
+				t.Fatal("swagger.json's name pattern ", swaggerPattern, " and the default naming convention disagree on ", name, ": ", error)
+			}
+		}
+	}
+}
+
+func TestNamingConventionGenerate(t *testing.T) {
+	convention, error := NewNamingConvention("", nil)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	name, error := convention.Generate(map[string]string{"Lob": "SEC", "App": "billing", "Env": "Prod"})
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if name != "AWS_SEC_billing_Prod" {
+		t.Fatal("Unexpected account name: ", name)
+	}
+
+	testCases := []struct {
+		name         string
+		values       map[string]string
+		expectedCode string
+	}{
+		{"missing app", map[string]string{"lob": "SEC", "env": "Prod"}, "missing_segment"},
+		{"delimiter in app", map[string]string{"lob": "SEC", "app": "my_app", "env": "Prod"}, "invalid_segment"},
+		{"unknown env", map[string]string{"lob": "SEC", "app": "billing", "env": "Staging"}, "invalid_segment"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, error := convention.Generate(testCase.values)
+			if namingError, ok := error.(*NamingError); !ok || namingError.Code != testCase.expectedCode {
+				t.Fatal("Unexpected naming error: ", error)
+			}
+		})
+	}
+}
+
+func TestParseNamingConvention(t *testing.T) {
+	convention, error := ParseNamingConvention([]byte(`{pattern: "acme-{lob}-{app}-{env}", segments: {env: {regex: "dev|prod"}}}`))
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	lobAndEnv, error := convention.Parse("acme-SEC-billing-prod")
+	if error != nil || lobAndEnv["lob"] != "SEC" || lobAndEnv["env"] != "prod" {
+		t.Fatal("Account name was not parsed with the configured convention: ", lobAndEnv, error)
+	}
+
+	testCases := []struct {
+		name     string
+		document string
+	}{
+		{"invalid document", `pattern: [`},
+		{"no env", `pattern: "{prefix}_{lob}_{app}"`},
+		{"no delimiter", `pattern: "{prefix}_{lob}{app}_{env}"`},
+		{"repeated segment", `pattern: "{lob}_{lob}_{env}"`},
+		{"unknown segment", `pattern: "{lob}_{region}_{env}"`},
+		{"invalid regex", `segments: {lob: {regex: "[A-Z"}}`},
+		{"malformed pattern", `pattern: "{lob}_{env"`},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, error := ParseNamingConvention([]byte(testCase.document))
+			if error == nil {
+				t.Fatal("Naming convention was expected to fail but didn't")
+			}
+		})
+	}
+
+	//test that ParseAccountName follows the convention in ACCOUNT_NAMING_CONVENTION
+	defer os.Setenv("ACCOUNT_NAMING_CONVENTION", os.Getenv("ACCOUNT_NAMING_CONVENTION"))
+	os.Setenv("ACCOUNT_NAMING_CONVENTION", `pattern: "{lob}.{app}.{env}"`)
+	lob, env, error := ParseAccountName("SEC.billing.Prod")
+	if error != nil || lob != "SEC" || env != "Prod" {
+		t.Fatal("Account name was not parsed with the configured convention: ", lob, env, error)
+	}
+}
@@ -0,0 +1,63 @@
+// Package orgclient builds the Organizations client the Lambdas' handlers run against in each execution mode.
+package orgclient
+
+import (
+	"log"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/fakeorg"
+)
+
+// New returns the Organizations client of mode. Dry runs get a read-only client and simulated handlers the
+// organization of NewSimulated. Handlers given the same client share the organization it stands for.
+func New(mode automation.Mode) (organizationsiface.OrganizationsAPI, error) {
+	if mode == automation.ModeSimulated {
+		log.Println("Simulated mode, setting up in-memory organization...")
+		return NewSimulated()
+	}
+
+	log.Println("Setting up session, assume role, and org client...")
+	sess := session.Must(session.NewSession())
+	org := organizations.New(sess, automation.OrganizationsConfig(sess))
+	if mode == automation.ModeDryRun {
+		return automation.ReadOnlyClient{OrganizationsAPI: org}, nil
+	}
+	return org, nil
+}
+
+// NewSimulated returns the organization simulated handlers run against, a fakeorg.Client holding the configured
+// Workload and Security OUs, under the root, and nothing else. Accounts are only in it once they have been created.
+func NewSimulated() (*fakeorg.Client, error) {
+	org := fakeorg.NewClient()
+	infraSecOUs, error := automation.RetrieveInfraSecOUs()
+	if error != nil {
+		return nil, error
+	}
+	names := []string{"Workloads"}
+	refs := map[string]string{"Workloads": automation.RetrieveWorkloadOURef()}
+	for lob, ref := range infraSecOUs {
+		names = append(names, lob)
+		refs[lob] = ref
+	}
+	// seed in a fixed order, so the generated OU IDs are the same every time
+	sort.Strings(names[1:])
+
+	for _, name := range names {
+		ref := refs[name]
+		if ref == "" {
+			continue
+		}
+		if automation.IsOUID(ref) {
+			org.AddOU(fakeorg.RootID, ref, name)
+		} else {
+			org.AddOUPath(ref)
+		}
+	}
+	log.Println("Simulated - seeded the organization with the configured OUs ", refs)
+	return org, nil
+}
@@ -0,0 +1,59 @@
+package orgclient
+
+import (
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/google/go-cmp/cmp"
+
+	"go-account-automation/internal/automation"
+	"go-account-automation/internal/automation/fakeorg"
+)
+
+// ouNames returns the names of the OUs directly under parentID, keyed by ID.
+func ouNames(t *testing.T, org *fakeorg.Client, parentID string) map[string]string {
+	ous, error := automation.ListOrganizationalUnits(org, parentID)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	names := map[string]string{}
+	for _, ou := range ous {
+		names[aws.StringValue(ou.Id)] = aws.StringValue(ou.Name)
+	}
+	return names
+}
+
+func TestNewSimulated(t *testing.T) {
+	for variable, value := range map[string]string{
+		"WORKLOAD_OU":      "",
+		"WORKLOAD_OU_PATH": "/Workloads",
+		"SEC_OU":           `{"SEC":"ou-abcd-12345678"}`,
+		"SEC_OU_PATHS":     `{"IS":"/Security/IS"}`,
+	} {
+		defer os.Setenv(variable, os.Getenv(variable))
+		os.Setenv(variable, value)
+	}
+
+	//test that the configured OUs are seeded under the root, by ID or by path
+	org, error := NewSimulated()
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	expectedNames := map[string]string{"ou-fake-00000001": "Workloads", "ou-fake-00000002": "Security", "ou-abcd-12345678": "SEC"}
+	if names := ouNames(t, org, fakeorg.RootID); !cmp.Equal(names, expectedNames) {
+		t.Fatal("Unexpected OUs under the root: ", names)
+	}
+	if names := ouNames(t, org, "ou-fake-00000002"); !cmp.Equal(names, map[string]string{"ou-fake-00000003": "IS"}) {
+		t.Fatal("Unexpected OUs under the Security OU: ", names)
+	}
+
+	//test that the same IDs are generated every time
+	again, error := NewSimulated()
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if names := ouNames(t, again, fakeorg.RootID); !cmp.Equal(names, expectedNames) {
+		t.Fatal("Unexpected OUs under the root of the second organization: ", names)
+	}
+}
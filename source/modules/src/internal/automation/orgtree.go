@@ -0,0 +1,174 @@
+package automation
+
+import (
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+)
+
+const defaultOrgTreeTTL = 5 * time.Minute
+
+// OrgTree is an in-memory model of one organization's root and OUs. The children of each parent are listed
+// the first time they are looked up and again once they are older than the TTL, or when a lookup misses,
+// so a warm Lambda container answers repeated OU lookups without calling Organizations. Each Handler keeps its own
+// tree of the organization it runs against. A nil *OrgTree caches nothing, so every lookup calls Organizations.
+type OrgTree struct {
+	mu  sync.Mutex
+	ttl time.Duration
+	now func() time.Time
+
+	root         string
+	rootLoadedAt time.Time
+	children     map[string]orgTreeEntry
+	// parents maps each loaded OU to the parent it was listed under
+	parents map[string]string
+
+	hits   int
+	misses int
+}
+
+type orgTreeEntry struct {
+	ous      []*organizations.OrganizationalUnit
+	loadedAt time.Time
+}
+
+// OrgTreeStats counts the lookups an OrgTree answered from memory and the ones that had to call Organizations.
+type OrgTreeStats struct {
+	Hits   int
+	Misses int
+}
+
+// NewOrgTree returns an empty tree whose entries are refreshed once they are older than ttl.
+// A ttl of zero turns caching off, so every lookup calls Organizations.
+func NewOrgTree(ttl time.Duration) *OrgTree {
+	return &OrgTree{
+		ttl:      ttl,
+		now:      time.Now,
+		children: map[string]orgTreeEntry{},
+		parents:  map[string]string{},
+	}
+}
+
+func (t *OrgTree) fresh(loadedAt time.Time) bool {
+	return !loadedAt.IsZero() && t.now().Sub(loadedAt) < t.ttl
+}
+
+// Root returns the ID of the organization's root.
+func (t *OrgTree) Root(svc organizationsiface.OrganizationsAPI) (string, error) {
+	if t == nil {
+		return RetrieveRoot(svc)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.root != "" && t.fresh(t.rootLoadedAt) {
+		t.hits++
+		return t.root, nil
+	}
+
+	t.misses++
+	root, error := RetrieveRoot(svc)
+	if error != nil {
+		return "", error
+	}
+	t.root, t.rootLoadedAt = root, t.now()
+	return root, nil
+}
+
+// Child returns the ID of the OU directly under parentID whose name matches name, ignoring case,
+// or an empty ID when there is none. A name that is not among the cached children is looked up again
+// in case the OU has been created since they were listed.
+func (t *OrgTree) Child(svc organizationsiface.OrganizationsAPI, parentID string, name string) (string, error) {
+	if t == nil {
+		ous, error := ListOrganizationalUnits(svc, parentID)
+		if error != nil {
+			return "", error
+		}
+		return findOU(ous, name), nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if entry, ok := t.children[parentID]; ok && t.fresh(entry.loadedAt) {
+		if id := findOU(entry.ous, name); id != "" {
+			t.hits++
+			return id, nil
+		}
+	}
+
+	t.misses++
+	ous, error := ListOrganizationalUnits(svc, parentID)
+	if error != nil {
+		return "", error
+	}
+	t.children[parentID] = orgTreeEntry{ous: ous, loadedAt: t.now()}
+	for _, ou := range ous {
+		t.parents[*ou.Id] = parentID
+	}
+	return findOU(ous, name), nil
+}
+
+// Add records an OU created under parentID, so it is found without listing the parent's children again.
+func (t *OrgTree) Add(parentID string, ou *organizations.OrganizationalUnit) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if entry, ok := t.children[parentID]; ok {
+		entry.ous = append(entry.ous, ou)
+		t.children[parentID] = entry
+	}
+	t.parents[*ou.Id] = parentID
+}
+
+// Parent returns the parent a loaded OU was listed under.
+func (t *OrgTree) Parent(ouID string) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	parentID, ok := t.parents[ouID]
+	return parentID, ok
+}
+
+func (t *OrgTree) Stats() OrgTreeStats {
+	if t == nil {
+		return OrgTreeStats{}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return OrgTreeStats{Hits: t.hits, Misses: t.misses}
+}
+
+func findOU(ous []*organizations.OrganizationalUnit, name string) string {
+	for _, ou := range ous {
+		if strings.EqualFold(*ou.Name, name) {
+			return *ou.Id
+		}
+	}
+	return ""
+}
+
+// RetrieveOrgTreeTTL reads the ORG_TREE_TTL environment variable, a duration such as "5m".
+func RetrieveOrgTreeTTL() time.Duration {
+	value := os.Getenv("ORG_TREE_TTL")
+	if value == "" {
+		return defaultOrgTreeTTL
+	}
+	ttl, error := time.ParseDuration(value)
+	if error != nil || ttl < 0 {
+		log.Println("ERROR: ORG_TREE_TTL ", value, " is not a valid duration, using ", defaultOrgTreeTTL)
+		return defaultOrgTreeTTL
+	}
+	return ttl
+}
@@ -0,0 +1,180 @@
+package automation
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+
+	"go-account-automation/internal/automation/fakeorg"
+)
+
+// treeOrg is an organization with Workloads/Dev and Workloads/Prod under its root.
+func treeOrg() *fakeorg.Client {
+	svc := fakeorg.NewClient()
+	svc.AddOU(fakeorg.RootID, "ou-abcd-workload", "Workloads")
+	svc.AddOU("ou-abcd-workload", "ou-abcd-wkdev", "Dev")
+	svc.AddOU("ou-abcd-workload", "ou-abcd-wkprod", "Prod")
+	return svc
+}
+
+func TestOrgTree(t *testing.T) {
+	svc := treeOrg()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tree := NewOrgTree(time.Minute)
+	tree.now = func() time.Time { return now }
+
+	lookup := func(parentID string, name string, expectedOU string) {
+		t.Helper()
+		ou, error := tree.Child(svc, parentID, name)
+		if error != nil {
+			t.Fatal(error.Error())
+		}
+		if ou != expectedOU {
+			t.Fatal("Unexpected OU for ", name, ": ", ou)
+		}
+	}
+
+	root, error := tree.Root(svc)
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if root != fakeorg.RootID {
+		t.Fatal("Unexpected root: ", root)
+	}
+	lookup("ou-abcd-workload", "Dev", "ou-abcd-wkdev")
+
+	//test that repeated lookups are served from memory
+	tree.Root(svc)
+	lookup("ou-abcd-workload", "dev", "ou-abcd-wkdev")
+	lookup("ou-abcd-workload", "Prod", "ou-abcd-wkprod")
+	if svc.Calls("ListRoots") != 1 || svc.Calls("ListOrganizationalUnitsForParent") != 1 {
+		t.Fatal("Unexpected calls to Organizations: ", svc.Calls("ListRoots"), svc.Calls("ListOrganizationalUnitsForParent"))
+	}
+	if stats := tree.Stats(); stats.Hits != 3 || stats.Misses != 2 {
+		t.Fatal("Unexpected cache stats: ", stats)
+	}
+	if parent, ok := tree.Parent("ou-abcd-wkprod"); !ok || parent != "ou-abcd-workload" {
+		t.Fatal("Unexpected parent: ", parent)
+	}
+
+	//test that an OU created since the children were listed is found by refreshing them
+	svc.AddOU("ou-abcd-workload", "ou-abcd-wklab", "Lab")
+	lookup("ou-abcd-workload", "Lab", "ou-abcd-wklab")
+	if svc.Calls("ListOrganizationalUnitsForParent") != 2 {
+		t.Fatal("A lookup miss was expected to refresh the children: ", svc.Calls("ListOrganizationalUnitsForParent"))
+	}
+
+	//test that a missing OU is looked up every time rather than cached
+	lookup("ou-abcd-workload", "Test", "")
+	lookup("ou-abcd-workload", "Test", "")
+	if svc.Calls("ListOrganizationalUnitsForParent") != 4 {
+		t.Fatal("A missing OU was expected to be looked up again: ", svc.Calls("ListOrganizationalUnitsForParent"))
+	}
+
+	//test that entries older than the TTL are refreshed
+	now = now.Add(time.Minute)
+	tree.Root(svc)
+	lookup("ou-abcd-workload", "Dev", "ou-abcd-wkdev")
+	if svc.Calls("ListRoots") != 2 || svc.Calls("ListOrganizationalUnitsForParent") != 5 {
+		t.Fatal("Expired entries were expected to be refreshed: ", svc.Calls("ListRoots"), svc.Calls("ListOrganizationalUnitsForParent"))
+	}
+}
+
+func TestOrgTreeWithoutTTL(t *testing.T) {
+	svc := treeOrg()
+
+	tree := NewOrgTree(0)
+	for i := 0; i < 2; i++ {
+		root, error := tree.Root(svc)
+		if error != nil {
+			t.Fatal(error.Error())
+		}
+		ou, error := tree.Child(svc, root, "Workloads")
+		if error != nil {
+			t.Fatal(error.Error())
+		}
+		if ou != "ou-abcd-workload" {
+			t.Fatal("Unexpected OU: ", ou)
+		}
+	}
+	if svc.Calls("ListRoots") != 2 || svc.Calls("ListOrganizationalUnitsForParent") != 2 {
+		t.Fatal("A tree without a TTL was expected to call Organizations every time: ", svc.Calls("ListRoots"), svc.Calls("ListOrganizationalUnitsForParent"))
+	}
+}
+
+func TestRetrieveOrgTreeTTL(t *testing.T) {
+	defer os.Setenv("ORG_TREE_TTL", os.Getenv("ORG_TREE_TTL"))
+
+	testCases := map[string]time.Duration{
+		"":        defaultOrgTreeTTL,
+		"90s":     90 * time.Second,
+		"0":       0,
+		"-1m":     defaultOrgTreeTTL,
+		"invalid": defaultOrgTreeTTL,
+	}
+	for value, expectedTTL := range testCases {
+		os.Setenv("ORG_TREE_TTL", value)
+		if ttl := RetrieveOrgTreeTTL(); ttl != expectedTTL {
+			t.Fatal("Unexpected TTL for ", value, ": ", ttl)
+		}
+	}
+}
+
+func TestNilOrgTree(t *testing.T) {
+	svc := treeOrg()
+
+	//test that a nil tree looks every OU up in Organizations and remembers nothing
+	var tree *OrgTree
+	for i := 0; i < 2; i++ {
+		root, error := tree.Root(svc)
+		if error != nil {
+			t.Fatal(error.Error())
+		}
+		ou, error := tree.Child(svc, root, "Workloads")
+		if error != nil {
+			t.Fatal(error.Error())
+		}
+		if ou != "ou-abcd-workload" {
+			t.Fatal("Unexpected OU: ", ou)
+		}
+		tree.Add(ou, &organizations.OrganizationalUnit{Id: aws.String("ou-abcd-added"), Name: aws.String("Added")})
+	}
+	if svc.Calls("ListRoots") != 2 || svc.Calls("ListOrganizationalUnitsForParent") != 2 {
+		t.Fatal("A nil tree was expected to call Organizations every time: ", svc.Calls("ListRoots"), svc.Calls("ListOrganizationalUnitsForParent"))
+	}
+	if _, found := tree.Parent("ou-abcd-added"); found || tree.Stats() != (OrgTreeStats{}) {
+		t.Fatal("A nil tree was not expected to remember anything")
+	}
+}
+
+func TestOrgTreePerOrganization(t *testing.T) {
+	//test that two trees, like two handlers against two organizations, never answer with each other's OUs
+	first := treeOrg()
+	second := fakeorg.NewClient()
+	second.AddOU(fakeorg.RootID, "ou-abcd-other", "Workloads")
+
+	firstTree := NewOrgTree(time.Hour)
+	secondTree := NewOrgTree(time.Hour)
+	for i := 0; i < 2; i++ {
+		for _, testCase := range []struct {
+			svc        *fakeorg.Client
+			tree       *OrgTree
+			expectedOU string
+		}{
+			{first, firstTree, "ou-abcd-workload"},
+			{second, secondTree, "ou-abcd-other"},
+		} {
+			ou, error := testCase.tree.Child(testCase.svc, fakeorg.RootID, "Workloads")
+			if error != nil {
+				t.Fatal(error.Error())
+			}
+			if ou != testCase.expectedOU {
+				t.Fatal("Unexpected OU: ", ou, " expected ", testCase.expectedOU)
+			}
+		}
+	}
+}
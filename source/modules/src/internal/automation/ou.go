@@ -0,0 +1,223 @@
+package automation
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+)
+
+// RetrieveOUs returns the root ID and the ID of the OU the account belongs in, placed by the PLACEMENT_RULES
+// document when there is one and by the Workloads/Security walk otherwise. The OU ID is empty when there is no such OU.
+func RetrieveOUs(svc organizationsiface.OrganizationsAPI, tree *OrgTree, payload AccountPayload) (string, string, error) {
+	return ResolveOUs(svc, tree, payload, nil)
+}
+
+// ResolveOUs places the account like RetrieveOUs, creating the OUs missing from its path where autoCreate allows
+// when it is not nil. The OUs are looked up through tree, the caller's model of the organization svc calls.
+func ResolveOUs(svc organizationsiface.OrganizationsAPI, tree *OrgTree, payload AccountPayload, autoCreate *OUAutoCreation) (string, string, error) {
+	rules, error := LoadPlacementRules()
+	if error != nil {
+		return "", "", error
+	}
+	if rules != nil {
+		return PlaceAccount(svc, tree, rules, payload, autoCreate)
+	}
+	return RetrieveWorkloadOU(svc, tree, payload, autoCreate)
+}
+
+// RetrieveWorkloadOU places the account in <Security OU>/<env> when its lob has a Security OU,
+// and in <Workload OU>/<env>/<lob> otherwise. Missing env and lob OUs are created where autoCreate
+// allows when it is not nil.
+func RetrieveWorkloadOU(svc organizationsiface.OrganizationsAPI, tree *OrgTree, payload AccountPayload, autoCreate *OUAutoCreation) (string, string, error) {
+	infraSecOUs, error := RetrieveInfraSecOUs()
+	if error != nil {
+		return "", "", error
+	}
+
+	parentOU, error := ResolveOURef(svc, tree, RetrieveParentOU(infraSecOUs, RetrieveWorkloadOURef(), payload.Lob))
+	if error != nil {
+		return "", "", error
+	}
+	// log.Println("parentOU:", parentOU)
+
+	envOU, root, error := RetrieveEnvOU(svc, tree, infraSecOUs, parentOU, payload.Env)
+	if envOU == "" && error == nil && autoCreate != nil {
+		envOU, error = autoCreate.CreateOU(svc, tree, parentOU, payload.Env)
+	}
+	if error != nil {
+		return "", "", error
+	}
+	// log.Println("envOU,root:", envOU, ",", root)
+	if envOU == "" {
+		// a missing env OU has no lob OUs under it to look up
+		return root, "", nil
+	}
+
+	var ou string
+	if _, ok := LookupLob(infraSecOUs, payload.Lob); ok || autoCreate == nil {
+		ou, error = DetermineDestinationOU(svc, tree, infraSecOUs, envOU, payload.Lob)
+	} else {
+		ou, error = autoCreate.Child(svc, tree, envOU, payload.Lob)
+	}
+	if error != nil {
+		return "", "", error
+	}
+	return root, ou, nil
+}
+
+// RetrieveWorkloadOURef returns the WORKLOAD_OU ID when it is set and the WORKLOAD_OU_PATH path otherwise.
+func RetrieveWorkloadOURef() string {
+	if workloadOU := os.Getenv("WORKLOAD_OU"); workloadOU != "" {
+		return workloadOU
+	}
+	return os.Getenv("WORKLOAD_OU_PATH")
+}
+
+// RetrieveInfraSecOUs returns the Security OU of each lob that has one, as an OU ID or a path of OU names.
+// The IDs in SEC_OU override the paths in SEC_OU_PATHS.
+func RetrieveInfraSecOUs() (map[string]string, error) {
+	infraSecOUs := map[string]string{}
+	for _, variable := range []string{"SEC_OU_PATHS", "SEC_OU"} {
+		jsonMap := os.Getenv(variable)
+		if jsonMap == "" {
+			continue
+		}
+		// Serialize json to map
+		var ous map[string]string
+		error := json.Unmarshal([]byte(jsonMap), &ous)
+		if error != nil {
+			return nil, error
+		}
+		for lob, ou := range ous {
+			if existing, ok := LookupLob(infraSecOUs, lob); ok {
+				delete(infraSecOUs, existing)
+			}
+			infraSecOUs[lob] = ou
+		}
+	}
+	return infraSecOUs, nil
+}
+
+// LookupLob finds the key for lob in infraSecOUs, ignoring case, so lobs are matched the same way as OU names.
+func LookupLob(infraSecOUs map[string]string, lob string) (string, bool) {
+	for key := range infraSecOUs {
+		if strings.EqualFold(key, lob) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// RetrieveParentOU returns the Security OU of the lob when it has one, and workloadOU otherwise.
+func RetrieveParentOU(infraSecOUs map[string]string, workloadOU string, lob string) string {
+	if key, ok := LookupLob(infraSecOUs, lob); ok {
+		return infraSecOUs[key]
+	} else {
+		return workloadOU
+	}
+}
+
+// RetrieveRoot returns the ID of the organization's root.
+func RetrieveRoot(svc organizationsiface.OrganizationsAPI) (string, error) {
+	input := &organizations.ListRootsInput{}
+	for {
+		output, error := svc.ListRoots(input)
+		if error != nil {
+			return "", error
+		}
+		if len(output.Roots) > 0 {
+			return aws.StringValue(output.Roots[0].Id), nil
+		}
+
+		// Organizations can return empty pages before the last one
+		if output.NextToken == nil {
+			return "", errors.New("error: no root found for the organization")
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// ListOrganizationalUnits returns every OU directly under parentID, across all pages of ListOrganizationalUnitsForParent.
+func ListOrganizationalUnits(svc organizationsiface.OrganizationsAPI, parentID string) ([]*organizations.OrganizationalUnit, error) {
+	var ous []*organizations.OrganizationalUnit
+	input := &organizations.ListOrganizationalUnitsForParentInput{ParentId: &parentID}
+	for {
+		output, error := svc.ListOrganizationalUnitsForParent(input)
+		if error != nil {
+			return nil, error
+		}
+		ous = append(ous, output.OrganizationalUnits...)
+
+		if output.NextToken == nil {
+			return ous, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+func RetrieveEnvOU(svc organizationsiface.OrganizationsAPI, tree *OrgTree, infraSecOUs map[string]string, parentOU string, env string) (string, string, error) {
+	root, error := tree.Root(svc)
+	if error != nil {
+		return "", "", error
+	}
+
+	envOU, error := tree.Child(svc, parentOU, env)
+	if error != nil {
+		return "", "", error
+	}
+
+	return envOU, root, nil
+}
+
+func DetermineDestinationOU(svc organizationsiface.OrganizationsAPI, tree *OrgTree, infraSecOUs map[string]string, envOU string, lob string) (string, error) {
+	if _, ok := LookupLob(infraSecOUs, lob); ok {
+		return envOU, nil
+	}
+
+	return tree.Child(svc, envOU, lob)
+}
+
+// MoveAccount moves the account into ou from whichever parent it is in now. An account that is already in ou
+// is left where it is, so a move can be replayed safely after it has succeeded.
+func MoveAccount(svc organizationsiface.OrganizationsAPI, accountID string, ou string) error {
+	source, error := RetrieveParent(svc, accountID)
+	if error != nil {
+		return error
+	}
+	if source == ou {
+		log.Println("Account is already in the destination OU, skipping move...")
+		return nil
+	}
+
+	input := organizations.MoveAccountInput{
+		AccountId:           &accountID,
+		SourceParentId:      &source,
+		DestinationParentId: &ou,
+	}
+	_, error = svc.MoveAccount(&input)
+	// Another attempt may have moved the account since ListParents
+	if aerr, ok := error.(awserr.Error); ok && aerr.Code() == organizations.ErrCodeDuplicateAccountException {
+		log.Println("Account is already in the destination OU: ", aerr.Message())
+		return nil
+	}
+	return error
+}
+
+// RetrieveParent returns the ID of the root or OU the account is in now.
+func RetrieveParent(svc organizationsiface.OrganizationsAPI, accountID string) (string, error) {
+	parents, error := svc.ListParents(&organizations.ListParentsInput{ChildId: &accountID})
+	if error != nil {
+		return "", error
+	}
+	if len(parents.Parents) == 0 {
+		return "", errors.New("error: no parent found for account " + accountID)
+	}
+	return aws.StringValue(parents.Parents[0].Id), nil
+}
@@ -0,0 +1,71 @@
+package automation
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+)
+
+// IsOUID reports whether ref is an OU or root ID rather than a path of OU names.
+func IsOUID(ref string) bool {
+	return strings.HasPrefix(ref, "ou-") || strings.HasPrefix(ref, "r-")
+}
+
+// ResolveOUPath walks a path of OU names such as "/Workloads/DEV/SEC" down from the root, matching each name
+// case-insensitively, and returns the root ID and the ID of the OU at the end of the path. The first segment may
+// instead be an OU ID to start the walk from. The OU ID is empty when an OU along the path does not exist.
+func ResolveOUPath(svc organizationsiface.OrganizationsAPI, tree *OrgTree, path string) (string, string, error) {
+	return WalkOUPath(svc, tree, path, nil)
+}
+
+// WalkOUPath resolves path like ResolveOUPath, creating the OUs missing along it where autoCreate allows
+// when autoCreate is not nil. The OUs are looked up through tree, the caller's model of the organization svc calls.
+func WalkOUPath(svc organizationsiface.OrganizationsAPI, tree *OrgTree, path string, autoCreate *OUAutoCreation) (string, string, error) {
+	root, error := tree.Root(svc)
+	if error != nil {
+		return "", "", error
+	}
+
+	parent := root
+	names := strings.Split(strings.Trim(path, "/"), "/")
+	if IsOUID(names[0]) {
+		parent, names = names[0], names[1:]
+	}
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		next, error := autoCreate.Child(svc, tree, parent, name)
+		if error != nil {
+			return "", "", error
+		}
+		if next == "" {
+			log.Println("No OU named ", name, " found under ", parent)
+			return root, "", nil
+		}
+		parent = next
+	}
+	return root, parent, nil
+}
+
+// ResolveOURef returns the ID of the configured OU ref, which is either an OU ID, used as it is, or a path of OU names.
+// Configured OUs are expected to exist, so a path that does not resolve is an error.
+func ResolveOURef(svc organizationsiface.OrganizationsAPI, tree *OrgTree, ref string) (string, error) {
+	if ref == "" {
+		return "", errors.New("error: no OU configured")
+	}
+	if IsOUID(ref) {
+		return ref, nil
+	}
+
+	_, ou, error := ResolveOUPath(svc, tree, ref)
+	if error != nil {
+		return "", error
+	}
+	if ou == "" {
+		return "", errors.New("error: configured OU " + ref + " not found")
+	}
+	return ou, nil
+}
@@ -0,0 +1,95 @@
+// Package automation holds what the account automation Lambdas share: the account payload and its validation,
+// tagging, OU resolution and placement, dry-run plans, execution modes, API GW responses, the local server and
+// the operator command runner. Each Lambda is a thin entrypoint over it.
+package automation
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+type AccountPayload struct {
+	Name          string `json:"name"`
+	CostCenter    string `json:"costCenter"`
+	AccountPOC    string `json:"accountPOC"`
+	ApplicationID string `json:"applicationId"`
+	Env           string `json:"env"`
+	Lob           string `json:"lob"`
+	AccountID     string `json:"accountId"`
+}
+
+func ProcessRequestPayload(request string) (AccountPayload, error) {
+	var payload AccountPayload
+	error := json.Unmarshal([]byte(request), &payload)
+	return payload, error
+}
+
+// ParseAccountName returns the lob and env from an account name following the naming convention, by default
+// AWS_LOB_name_Env. A name that does not follow it is returned as a *NamingError.
+func ParseAccountName(accountName string) (string, string, error) {
+	convention, error := LoadNamingConvention()
+	if error != nil {
+		return "", "", error
+	}
+	values, error := convention.Parse(accountName)
+	if error != nil {
+		return "", "", error
+	}
+	return values["lob"], values["env"], nil
+}
+
+// ValidatePayload checks every field of payload as ValidateFields does, and that the lob and env agree with those
+// in the account name. It returns the problems it found as a *ValidationError.
+func ValidatePayload(payload AccountPayload) error {
+	problems, error := ValidateFields(payload)
+	if error != nil {
+		return error
+	}
+
+	lob, env, error := ParseAccountName(payload.Name)
+	if error == nil {
+		if ValidateEnv(payload.Env) == nil && !strings.EqualFold(env, payload.Env) {
+			problems = append(problems, FieldError{Field: "env", Code: "mismatch", Message: "error: env " + payload.Env + " differs from " + env + ", the env in the account name"})
+		}
+		if ValidateLob(payload.Lob) == nil && !strings.EqualFold(lob, payload.Lob) {
+			problems = append(problems, FieldError{Field: "lob", Code: "mismatch", Message: "error: lob " + payload.Lob + " differs from " + lob + ", the lob in the account name"})
+		}
+	}
+	return NewValidationError(problems)
+}
+
+// PayloadFromTags rebuilds the payload from the tags GenerateTags wrote, which are keyed by AccountPayload field name.
+func PayloadFromTags(accountID string, tags map[string]string) AccountPayload {
+	var payload AccountPayload
+	val := reflect.ValueOf(&payload).Elem()
+	typeOfS := val.Type()
+
+	// Iterate over fields of struct
+	for i := 0; i < val.NumField(); i++ {
+		if value, ok := tags[typeOfS.Field(i).Name]; ok {
+			val.Field(i).SetString(value)
+		}
+	}
+
+	payload.AccountID = accountID
+	return payload
+}
+
+// PayloadFromAccount rebuilds the payload from the account's tags. The account name records the lob and env the
+// account was created with, so it is only used for them when the Lob and Env tags, which a move rewrites, are missing.
+func PayloadFromAccount(accountID string, accountName string, tags map[string]string) (AccountPayload, error) {
+	lob, env, error := ParseAccountName(accountName)
+	if error != nil {
+		return AccountPayload{}, error
+	}
+
+	payload := PayloadFromTags(accountID, tags)
+	if payload.Lob == "" {
+		payload.Lob = lob
+	}
+	if payload.Env == "" {
+		payload.Env = env
+	}
+	return payload, nil
+}
@@ -0,0 +1,136 @@
+package automation
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidatePayload(t *testing.T) {
+	testPayload := AccountPayload{
+		Name:          "AWS_SEC_test_Dev",
+		CostCenter:    "01234",
+		AccountPOC:    "john.doe@example.com",
+		ApplicationID: "00000000-0000-0000-0000-000000000000",
+		Env:           "Dev",
+		Lob:           "SEC",
+		AccountID:     "",
+	}
+	error := ValidatePayload(testPayload)
+	if error != nil {
+		t.Fatal("Payload failed validation: ", error.Error())
+	}
+
+	//test for malformed payload catch
+	testPayload.Name = "AWS_IS_test_Dev"
+	error = ValidatePayload(testPayload)
+	if error == nil {
+		t.Fatal("Payload was expected to fail but didn't ")
+
+	}
+
+	//test that a name with too few parts is rejected rather than panicking
+	testPayload.Name = "AWS_SEC_Dev"
+	error = ValidatePayload(testPayload)
+	if error == nil {
+		t.Fatal("Payload with a short name was expected to fail but didn't ")
+	}
+}
+
+func TestValidateFields(t *testing.T) {
+	problems, error := ValidateFields(AccountPayload{
+		Name:          "AWS_SEC_test_Dev",
+		CostCenter:    "01234",
+		AccountPOC:    "john.doe@example.com",
+		ApplicationID: "0a1b2c3d-0000-0000-0000-00000000abcd",
+		Env:           "DEV",
+		Lob:           "SEC",
+	})
+	if error != nil || len(problems) != 0 {
+		t.Fatal("Payload failed validation: ", problems, error)
+	}
+
+	//test that an accountPOC must be in the EMAIL_DOMAIN domain, as swagger.json is deployed to require
+	defer os.Setenv("EMAIL_DOMAIN", os.Getenv("EMAIL_DOMAIN"))
+	os.Setenv("EMAIL_DOMAIN", "@corp.example.org")
+	for accountPOC, expectedProblems := range map[string]int{"jane.doe@corp.example.org": 0, "john.doe@example.com": 1, "jane.doe@corpxexample.org": 1} {
+		problems, _ = ValidateFields(AccountPayload{Name: "AWS_SEC_test_Dev", CostCenter: "01234", AccountPOC: accountPOC, ApplicationID: "0a1b2c3d-0000-0000-0000-00000000abcd", Env: "Dev", Lob: "SEC"})
+		if len(problems) != expectedProblems {
+			t.Fatal("Unexpected problems for ", accountPOC, ": ", problems)
+		}
+	}
+	os.Unsetenv("EMAIL_DOMAIN")
+	problems, _ = ValidateFields(AccountPayload{Name: "AWS_SEC_test_Dev", CostCenter: "01234", AccountPOC: "john.doe@gmail.com", ApplicationID: "0a1b2c3d-0000-0000-0000-00000000abcd", Env: "Dev", Lob: "SEC"})
+	if len(problems) != 1 || problems[0].Field != "accountPOC" {
+		t.Fatal("An accountPOC outside the default domain was expected to fail: ", problems)
+	}
+
+	//test that every field that is not valid is reported at once, with its code
+	problems, error = ValidateFields(AccountPayload{
+		Name:          "AWS_SEC_test_Dev",
+		CostCenter:    "1234",
+		AccountPOC:    "john.doe",
+		ApplicationID: "not-a-uuid",
+		Env:           "Staging",
+		Lob:           "IS",
+	})
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	expectedCodes := map[string]string{
+		"costCenter":    "invalid_format",
+		"accountPOC":    "invalid_format",
+		"applicationId": "invalid_format",
+		"env":           "invalid_value",
+	}
+	codes := map[string]string{}
+	for _, problem := range problems {
+		codes[problem.Field] = problem.Code
+	}
+	if !cmp.Equal(codes, expectedCodes) {
+		t.Fatal("Unexpected problems: ", cmp.Diff(expectedCodes, codes))
+	}
+
+	//test that missing fields are required and a name breaking the convention names its segment
+	problems, _ = ValidateFields(AccountPayload{Name: "AWS_SEC_my_app_Dev", Lob: "sec"})
+	codes = map[string]string{}
+	for _, problem := range problems {
+		codes[problem.Field] = problem.Code
+	}
+	expectedCodes = map[string]string{
+		"name":          "too_many_segments",
+		"costCenter":    "required",
+		"accountPOC":    "required",
+		"applicationId": "required",
+		"env":           "required",
+		"lob":           "invalid_format",
+	}
+	if !cmp.Equal(codes, expectedCodes) || problems[0].Segment != "env" {
+		t.Fatal("Unexpected problems: ", problems)
+	}
+
+	//test that ValidatePayload returns them as a *ValidationError, along with a lob differing from the name's
+	error = ValidatePayload(AccountPayload{Name: "AWS_SEC_test_Dev", Env: "Dev", Lob: "IS"})
+	validationError, ok := error.(*ValidationError)
+	if !ok || len(validationError.Problems) != 4 || validationError.Problems[3].Field != "lob" || validationError.Problems[3].Code != "mismatch" {
+		t.Fatal("Unexpected validation error: ", error)
+	}
+}
+
+func TestParseAccountName(t *testing.T) {
+	lob, env, error := ParseAccountName("AWS_SEC_test_Dev")
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if lob != "SEC" || env != "Dev" {
+		t.Fatal("Account name was not parsed as expected: ", lob, env)
+	}
+
+	for _, accountName := range []string{"AWS_SEC_Dev", "AWS_SEC_my_app_Dev", "gcp_SEC_test_Dev", ""} {
+		_, _, error = ParseAccountName(accountName)
+		if error == nil {
+			t.Fatal("Account name was expected to fail but didn't: ", accountName)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package automation
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+	"gopkg.in/yaml.v3"
+)
+
+// placeholderPattern matches the {attribute} placeholders in a placement path template.
+var placeholderPattern = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// MatchValues are the values a placement rule accepts for one attribute. In a rules document
+// it is either a single value or a list of them.
+type MatchValues []string
+
+func (v *MatchValues) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*v = MatchValues{value.Value}
+		return nil
+	}
+	var values []string
+	error := value.Decode(&values)
+	if error != nil {
+		return error
+	}
+	*v = values
+	return nil
+}
+
+// PlacementRule places the accounts whose attributes all match one of the given values in the OU at Path,
+// a template of OU names from the root such as "Workloads/{env}/{lob}".
+type PlacementRule struct {
+	Name  string                 `json:"name" yaml:"name"`
+	Match map[string]MatchValues `json:"match" yaml:"match"`
+	Path  string                 `json:"path" yaml:"path"`
+}
+
+// PlacementRules are evaluated in order; the first rule that matches places the account,
+// and Default places any account no rule matches.
+type PlacementRules struct {
+	Rules   []PlacementRule `json:"rules" yaml:"rules"`
+	Default PlacementRule   `json:"default" yaml:"default"`
+}
+
+// PlacementAttributes returns the attributes placement rules match on, the payload's fields keyed by
+// field name as they are tagged on the account.
+func PlacementAttributes(payload AccountPayload) map[string]string {
+	attributes := map[string]string{}
+	val := reflect.ValueOf(payload)
+	typeOfS := val.Type()
+
+	// Iterate over fields of struct
+	for i := 0; i < val.NumField(); i++ {
+		key, value := typeOfS.Field(i).Name, fmt.Sprintf("%v", val.Field(i).Interface())
+		if !strings.EqualFold(key, "AccountID") {
+			attributes[key] = value
+		}
+	}
+	return attributes
+}
+
+// attribute looks an attribute up by name, ignoring case, so rules can say "lob" for the Lob tag.
+func attribute(attributes map[string]string, name string) (string, bool) {
+	for key, value := range attributes {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// ParsePlacementRules reads a JSON or YAML rules document and checks that every rule only refers to known attributes.
+func ParsePlacementRules(document []byte) (*PlacementRules, error) {
+	var rules PlacementRules
+	error := yaml.Unmarshal(document, &rules)
+	if error != nil {
+		return nil, errors.New("error: placement rules could not be parsed: " + error.Error())
+	}
+
+	if rules.Default.Name == "" {
+		rules.Default.Name = "default"
+	}
+	if len(rules.Default.Match) > 0 {
+		return nil, errors.New("error: placement rule default cannot have a match")
+	}
+
+	known := PlacementAttributes(AccountPayload{})
+	for _, rule := range append(rules.Rules, rules.Default) {
+		if strings.Trim(rule.Path, "/") == "" {
+			return nil, errors.New("error: placement rule " + rule.Name + " has no path")
+		}
+		placeholders := len(placeholderPattern.FindAllString(rule.Path, -1))
+		if strings.Count(rule.Path, "{") != placeholders || strings.Count(rule.Path, "}") != placeholders {
+			return nil, errors.New("error: placement rule " + rule.Name + " has a malformed path " + rule.Path)
+		}
+		for _, placeholder := range placeholderPattern.FindAllStringSubmatch(rule.Path, -1) {
+			if _, ok := attribute(known, placeholder[1]); !ok {
+				return nil, errors.New("error: placement rule " + rule.Name + " uses unknown attribute " + placeholder[1])
+			}
+		}
+		for name := range rule.Match {
+			if _, ok := attribute(known, name); !ok {
+				return nil, errors.New("error: placement rule " + rule.Name + " matches on unknown attribute " + name)
+			}
+		}
+	}
+	return &rules, nil
+}
+
+// LoadPlacementRules parses the rules document in the PLACEMENT_RULES environment variable.
+// It returns nil when there is none, in which case the built-in Workloads/Security walk is used.
+func LoadPlacementRules() (*PlacementRules, error) {
+	document := os.Getenv("PLACEMENT_RULES")
+	if strings.TrimSpace(document) == "" {
+		return nil, nil
+	}
+	return ParsePlacementRules([]byte(document))
+}
+
+func (r PlacementRule) Matches(attributes map[string]string) bool {
+	for name, values := range r.Match {
+		value, _ := attribute(attributes, name)
+		matched := false
+		for _, accepted := range values {
+			if strings.EqualFold(value, accepted) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// RenderPath fills the rule's path template in from the attributes.
+func (r PlacementRule) RenderPath(attributes map[string]string) (string, error) {
+	var missing string
+	path := placeholderPattern.ReplaceAllStringFunc(r.Path, func(placeholder string) string {
+		value, _ := attribute(attributes, strings.Trim(placeholder, "{}"))
+		if value == "" && missing == "" {
+			missing = placeholder
+		}
+		return value
+	})
+	if missing != "" {
+		return "", errors.New("error: placement rule " + r.Name + " needs " + missing + ", which the request does not have")
+	}
+	return strings.Trim(path, "/"), nil
+}
+
+// Evaluate returns the first rule that matches the attributes, or the default rule, and the path it places them at.
+func (r *PlacementRules) Evaluate(attributes map[string]string) (*PlacementRule, string, error) {
+	rule := &r.Default
+	for i := range r.Rules {
+		if r.Rules[i].Matches(attributes) {
+			rule = &r.Rules[i]
+			break
+		}
+	}
+	path, error := rule.RenderPath(attributes)
+	if error != nil {
+		return nil, "", error
+	}
+	return rule, path, nil
+}
+
+// PlaceAccount evaluates the rules against the payload and returns the root ID and the ID of the OU they place it in,
+// creating the OUs missing from the path where autoCreate allows when it is not nil.
+func PlaceAccount(svc organizationsiface.OrganizationsAPI, tree *OrgTree, rules *PlacementRules, payload AccountPayload, autoCreate *OUAutoCreation) (string, string, error) {
+	rule, path, error := rules.Evaluate(PlacementAttributes(payload))
+	if error != nil {
+		return "", "", error
+	}
+	log.Println("Placement rule ", rule.Name, " places account at ", path)
+	return WalkOUPath(svc, tree, path, autoCreate)
+}
@@ -0,0 +1,36 @@
+package automation
+
+import (
+	"testing"
+)
+
+func TestParsePlacementRules(t *testing.T) {
+	rules, error := ParsePlacementRules([]byte(`{"rules": [{"name": "sandbox", "match": {"env": ["Lab"]}, "path": "Sandbox/{lob}"}], "default": {"path": "Workloads/{env}/{lob}"}}`))
+	if error != nil {
+		t.Fatal(error.Error())
+	}
+	if len(rules.Rules) != 1 || rules.Rules[0].Match["env"][0] != "Lab" || rules.Default.Name != "default" {
+		t.Fatal("JSON placement rules not parsed as expected: ", rules)
+	}
+
+	testCases := []struct {
+		name     string
+		document string
+	}{
+		{"invalid document", `rules: [`},
+		{"no default", `rules: [{name: sandbox, path: "Sandbox/{lob}"}]`},
+		{"rule without path", `{rules: [{name: sandbox}], default: {path: "Workloads/{env}/{lob}"}}`},
+		{"unknown placeholder", `default: {path: "Workloads/{region}/{lob}"}`},
+		{"unknown match", `{rules: [{name: sandbox, match: {region: us}, path: Sandbox}], default: {path: Workloads}}`},
+		{"malformed path", `default: {path: "Workloads/{env/{lob}"}`},
+		{"default with match", `default: {match: {env: Lab}, path: Workloads}`},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, error := ParsePlacementRules([]byte(testCase.document))
+			if error == nil {
+				t.Fatal("Placement rules were expected to fail but didn't")
+			}
+		})
+	}
+}
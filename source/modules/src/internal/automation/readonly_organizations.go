@@ -0,0 +1,802 @@
+// Code generated by gen_readonly.go; DO NOT EDIT.
+
+package automation
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/organizations"
+)
+
+// refusedOrganizations refuses every Organizations operation. ReadOnlyClient embeds it, so any operation it does not
+// allow fails rather than reaching Organizations.
+type refusedOrganizations struct{}
+
+func (refusedOrganizations) AcceptHandshake(_ *organizations.AcceptHandshakeInput) (*organizations.AcceptHandshakeOutput, error) {
+	return nil, readOnlyError("AcceptHandshake")
+}
+
+func (refusedOrganizations) AcceptHandshakeRequest(_ *organizations.AcceptHandshakeInput) (*request.Request, *organizations.AcceptHandshakeOutput) {
+	return &request.Request{Error: readOnlyError("AcceptHandshakeRequest")}, nil
+}
+
+func (refusedOrganizations) AcceptHandshakeWithContext(_ context.Context, _ *organizations.AcceptHandshakeInput, _ ...request.Option) (*organizations.AcceptHandshakeOutput, error) {
+	return nil, readOnlyError("AcceptHandshakeWithContext")
+}
+
+func (refusedOrganizations) AttachPolicy(_ *organizations.AttachPolicyInput) (*organizations.AttachPolicyOutput, error) {
+	return nil, readOnlyError("AttachPolicy")
+}
+
+func (refusedOrganizations) AttachPolicyRequest(_ *organizations.AttachPolicyInput) (*request.Request, *organizations.AttachPolicyOutput) {
+	return &request.Request{Error: readOnlyError("AttachPolicyRequest")}, nil
+}
+
+func (refusedOrganizations) AttachPolicyWithContext(_ context.Context, _ *organizations.AttachPolicyInput, _ ...request.Option) (*organizations.AttachPolicyOutput, error) {
+	return nil, readOnlyError("AttachPolicyWithContext")
+}
+
+func (refusedOrganizations) CancelHandshake(_ *organizations.CancelHandshakeInput) (*organizations.CancelHandshakeOutput, error) {
+	return nil, readOnlyError("CancelHandshake")
+}
+
+func (refusedOrganizations) CancelHandshakeRequest(_ *organizations.CancelHandshakeInput) (*request.Request, *organizations.CancelHandshakeOutput) {
+	return &request.Request{Error: readOnlyError("CancelHandshakeRequest")}, nil
+}
+
+func (refusedOrganizations) CancelHandshakeWithContext(_ context.Context, _ *organizations.CancelHandshakeInput, _ ...request.Option) (*organizations.CancelHandshakeOutput, error) {
+	return nil, readOnlyError("CancelHandshakeWithContext")
+}
+
+func (refusedOrganizations) CloseAccount(_ *organizations.CloseAccountInput) (*organizations.CloseAccountOutput, error) {
+	return nil, readOnlyError("CloseAccount")
+}
+
+func (refusedOrganizations) CloseAccountRequest(_ *organizations.CloseAccountInput) (*request.Request, *organizations.CloseAccountOutput) {
+	return &request.Request{Error: readOnlyError("CloseAccountRequest")}, nil
+}
+
+func (refusedOrganizations) CloseAccountWithContext(_ context.Context, _ *organizations.CloseAccountInput, _ ...request.Option) (*organizations.CloseAccountOutput, error) {
+	return nil, readOnlyError("CloseAccountWithContext")
+}
+
+func (refusedOrganizations) CreateAccount(_ *organizations.CreateAccountInput) (*organizations.CreateAccountOutput, error) {
+	return nil, readOnlyError("CreateAccount")
+}
+
+func (refusedOrganizations) CreateAccountRequest(_ *organizations.CreateAccountInput) (*request.Request, *organizations.CreateAccountOutput) {
+	return &request.Request{Error: readOnlyError("CreateAccountRequest")}, nil
+}
+
+func (refusedOrganizations) CreateAccountWithContext(_ context.Context, _ *organizations.CreateAccountInput, _ ...request.Option) (*organizations.CreateAccountOutput, error) {
+	return nil, readOnlyError("CreateAccountWithContext")
+}
+
+func (refusedOrganizations) CreateGovCloudAccount(_ *organizations.CreateGovCloudAccountInput) (*organizations.CreateGovCloudAccountOutput, error) {
+	return nil, readOnlyError("CreateGovCloudAccount")
+}
+
+func (refusedOrganizations) CreateGovCloudAccountRequest(_ *organizations.CreateGovCloudAccountInput) (*request.Request, *organizations.CreateGovCloudAccountOutput) {
+	return &request.Request{Error: readOnlyError("CreateGovCloudAccountRequest")}, nil
+}
+
+func (refusedOrganizations) CreateGovCloudAccountWithContext(_ context.Context, _ *organizations.CreateGovCloudAccountInput, _ ...request.Option) (*organizations.CreateGovCloudAccountOutput, error) {
+	return nil, readOnlyError("CreateGovCloudAccountWithContext")
+}
+
+func (refusedOrganizations) CreateOrganization(_ *organizations.CreateOrganizationInput) (*organizations.CreateOrganizationOutput, error) {
+	return nil, readOnlyError("CreateOrganization")
+}
+
+func (refusedOrganizations) CreateOrganizationRequest(_ *organizations.CreateOrganizationInput) (*request.Request, *organizations.CreateOrganizationOutput) {
+	return &request.Request{Error: readOnlyError("CreateOrganizationRequest")}, nil
+}
+
+func (refusedOrganizations) CreateOrganizationWithContext(_ context.Context, _ *organizations.CreateOrganizationInput, _ ...request.Option) (*organizations.CreateOrganizationOutput, error) {
+	return nil, readOnlyError("CreateOrganizationWithContext")
+}
+
+func (refusedOrganizations) CreateOrganizationalUnit(_ *organizations.CreateOrganizationalUnitInput) (*organizations.CreateOrganizationalUnitOutput, error) {
+	return nil, readOnlyError("CreateOrganizationalUnit")
+}
+
+func (refusedOrganizations) CreateOrganizationalUnitRequest(_ *organizations.CreateOrganizationalUnitInput) (*request.Request, *organizations.CreateOrganizationalUnitOutput) {
+	return &request.Request{Error: readOnlyError("CreateOrganizationalUnitRequest")}, nil
+}
+
+func (refusedOrganizations) CreateOrganizationalUnitWithContext(_ context.Context, _ *organizations.CreateOrganizationalUnitInput, _ ...request.Option) (*organizations.CreateOrganizationalUnitOutput, error) {
+	return nil, readOnlyError("CreateOrganizationalUnitWithContext")
+}
+
+func (refusedOrganizations) CreatePolicy(_ *organizations.CreatePolicyInput) (*organizations.CreatePolicyOutput, error) {
+	return nil, readOnlyError("CreatePolicy")
+}
+
+func (refusedOrganizations) CreatePolicyRequest(_ *organizations.CreatePolicyInput) (*request.Request, *organizations.CreatePolicyOutput) {
+	return &request.Request{Error: readOnlyError("CreatePolicyRequest")}, nil
+}
+
+func (refusedOrganizations) CreatePolicyWithContext(_ context.Context, _ *organizations.CreatePolicyInput, _ ...request.Option) (*organizations.CreatePolicyOutput, error) {
+	return nil, readOnlyError("CreatePolicyWithContext")
+}
+
+func (refusedOrganizations) DeclineHandshake(_ *organizations.DeclineHandshakeInput) (*organizations.DeclineHandshakeOutput, error) {
+	return nil, readOnlyError("DeclineHandshake")
+}
+
+func (refusedOrganizations) DeclineHandshakeRequest(_ *organizations.DeclineHandshakeInput) (*request.Request, *organizations.DeclineHandshakeOutput) {
+	return &request.Request{Error: readOnlyError("DeclineHandshakeRequest")}, nil
+}
+
+func (refusedOrganizations) DeclineHandshakeWithContext(_ context.Context, _ *organizations.DeclineHandshakeInput, _ ...request.Option) (*organizations.DeclineHandshakeOutput, error) {
+	return nil, readOnlyError("DeclineHandshakeWithContext")
+}
+
+func (refusedOrganizations) DeleteOrganization(_ *organizations.DeleteOrganizationInput) (*organizations.DeleteOrganizationOutput, error) {
+	return nil, readOnlyError("DeleteOrganization")
+}
+
+func (refusedOrganizations) DeleteOrganizationRequest(_ *organizations.DeleteOrganizationInput) (*request.Request, *organizations.DeleteOrganizationOutput) {
+	return &request.Request{Error: readOnlyError("DeleteOrganizationRequest")}, nil
+}
+
+func (refusedOrganizations) DeleteOrganizationWithContext(_ context.Context, _ *organizations.DeleteOrganizationInput, _ ...request.Option) (*organizations.DeleteOrganizationOutput, error) {
+	return nil, readOnlyError("DeleteOrganizationWithContext")
+}
+
+func (refusedOrganizations) DeleteOrganizationalUnit(_ *organizations.DeleteOrganizationalUnitInput) (*organizations.DeleteOrganizationalUnitOutput, error) {
+	return nil, readOnlyError("DeleteOrganizationalUnit")
+}
+
+func (refusedOrganizations) DeleteOrganizationalUnitRequest(_ *organizations.DeleteOrganizationalUnitInput) (*request.Request, *organizations.DeleteOrganizationalUnitOutput) {
+	return &request.Request{Error: readOnlyError("DeleteOrganizationalUnitRequest")}, nil
+}
+
+func (refusedOrganizations) DeleteOrganizationalUnitWithContext(_ context.Context, _ *organizations.DeleteOrganizationalUnitInput, _ ...request.Option) (*organizations.DeleteOrganizationalUnitOutput, error) {
+	return nil, readOnlyError("DeleteOrganizationalUnitWithContext")
+}
+
+func (refusedOrganizations) DeletePolicy(_ *organizations.DeletePolicyInput) (*organizations.DeletePolicyOutput, error) {
+	return nil, readOnlyError("DeletePolicy")
+}
+
+func (refusedOrganizations) DeletePolicyRequest(_ *organizations.DeletePolicyInput) (*request.Request, *organizations.DeletePolicyOutput) {
+	return &request.Request{Error: readOnlyError("DeletePolicyRequest")}, nil
+}
+
+func (refusedOrganizations) DeletePolicyWithContext(_ context.Context, _ *organizations.DeletePolicyInput, _ ...request.Option) (*organizations.DeletePolicyOutput, error) {
+	return nil, readOnlyError("DeletePolicyWithContext")
+}
+
+func (refusedOrganizations) DeleteResourcePolicy(_ *organizations.DeleteResourcePolicyInput) (*organizations.DeleteResourcePolicyOutput, error) {
+	return nil, readOnlyError("DeleteResourcePolicy")
+}
+
+func (refusedOrganizations) DeleteResourcePolicyRequest(_ *organizations.DeleteResourcePolicyInput) (*request.Request, *organizations.DeleteResourcePolicyOutput) {
+	return &request.Request{Error: readOnlyError("DeleteResourcePolicyRequest")}, nil
+}
+
+func (refusedOrganizations) DeleteResourcePolicyWithContext(_ context.Context, _ *organizations.DeleteResourcePolicyInput, _ ...request.Option) (*organizations.DeleteResourcePolicyOutput, error) {
+	return nil, readOnlyError("DeleteResourcePolicyWithContext")
+}
+
+func (refusedOrganizations) DeregisterDelegatedAdministrator(_ *organizations.DeregisterDelegatedAdministratorInput) (*organizations.DeregisterDelegatedAdministratorOutput, error) {
+	return nil, readOnlyError("DeregisterDelegatedAdministrator")
+}
+
+func (refusedOrganizations) DeregisterDelegatedAdministratorRequest(_ *organizations.DeregisterDelegatedAdministratorInput) (*request.Request, *organizations.DeregisterDelegatedAdministratorOutput) {
+	return &request.Request{Error: readOnlyError("DeregisterDelegatedAdministratorRequest")}, nil
+}
+
+func (refusedOrganizations) DeregisterDelegatedAdministratorWithContext(_ context.Context, _ *organizations.DeregisterDelegatedAdministratorInput, _ ...request.Option) (*organizations.DeregisterDelegatedAdministratorOutput, error) {
+	return nil, readOnlyError("DeregisterDelegatedAdministratorWithContext")
+}
+
+func (refusedOrganizations) DescribeAccount(_ *organizations.DescribeAccountInput) (*organizations.DescribeAccountOutput, error) {
+	return nil, readOnlyError("DescribeAccount")
+}
+
+func (refusedOrganizations) DescribeAccountRequest(_ *organizations.DescribeAccountInput) (*request.Request, *organizations.DescribeAccountOutput) {
+	return &request.Request{Error: readOnlyError("DescribeAccountRequest")}, nil
+}
+
+func (refusedOrganizations) DescribeAccountWithContext(_ context.Context, _ *organizations.DescribeAccountInput, _ ...request.Option) (*organizations.DescribeAccountOutput, error) {
+	return nil, readOnlyError("DescribeAccountWithContext")
+}
+
+func (refusedOrganizations) DescribeCreateAccountStatus(_ *organizations.DescribeCreateAccountStatusInput) (*organizations.DescribeCreateAccountStatusOutput, error) {
+	return nil, readOnlyError("DescribeCreateAccountStatus")
+}
+
+func (refusedOrganizations) DescribeCreateAccountStatusRequest(_ *organizations.DescribeCreateAccountStatusInput) (*request.Request, *organizations.DescribeCreateAccountStatusOutput) {
+	return &request.Request{Error: readOnlyError("DescribeCreateAccountStatusRequest")}, nil
+}
+
+func (refusedOrganizations) DescribeCreateAccountStatusWithContext(_ context.Context, _ *organizations.DescribeCreateAccountStatusInput, _ ...request.Option) (*organizations.DescribeCreateAccountStatusOutput, error) {
+	return nil, readOnlyError("DescribeCreateAccountStatusWithContext")
+}
+
+func (refusedOrganizations) DescribeEffectivePolicy(_ *organizations.DescribeEffectivePolicyInput) (*organizations.DescribeEffectivePolicyOutput, error) {
+	return nil, readOnlyError("DescribeEffectivePolicy")
+}
+
+func (refusedOrganizations) DescribeEffectivePolicyRequest(_ *organizations.DescribeEffectivePolicyInput) (*request.Request, *organizations.DescribeEffectivePolicyOutput) {
+	return &request.Request{Error: readOnlyError("DescribeEffectivePolicyRequest")}, nil
+}
+
+func (refusedOrganizations) DescribeEffectivePolicyWithContext(_ context.Context, _ *organizations.DescribeEffectivePolicyInput, _ ...request.Option) (*organizations.DescribeEffectivePolicyOutput, error) {
+	return nil, readOnlyError("DescribeEffectivePolicyWithContext")
+}
+
+func (refusedOrganizations) DescribeHandshake(_ *organizations.DescribeHandshakeInput) (*organizations.DescribeHandshakeOutput, error) {
+	return nil, readOnlyError("DescribeHandshake")
+}
+
+func (refusedOrganizations) DescribeHandshakeRequest(_ *organizations.DescribeHandshakeInput) (*request.Request, *organizations.DescribeHandshakeOutput) {
+	return &request.Request{Error: readOnlyError("DescribeHandshakeRequest")}, nil
+}
+
+func (refusedOrganizations) DescribeHandshakeWithContext(_ context.Context, _ *organizations.DescribeHandshakeInput, _ ...request.Option) (*organizations.DescribeHandshakeOutput, error) {
+	return nil, readOnlyError("DescribeHandshakeWithContext")
+}
+
+func (refusedOrganizations) DescribeOrganization(_ *organizations.DescribeOrganizationInput) (*organizations.DescribeOrganizationOutput, error) {
+	return nil, readOnlyError("DescribeOrganization")
+}
+
+func (refusedOrganizations) DescribeOrganizationRequest(_ *organizations.DescribeOrganizationInput) (*request.Request, *organizations.DescribeOrganizationOutput) {
+	return &request.Request{Error: readOnlyError("DescribeOrganizationRequest")}, nil
+}
+
+func (refusedOrganizations) DescribeOrganizationWithContext(_ context.Context, _ *organizations.DescribeOrganizationInput, _ ...request.Option) (*organizations.DescribeOrganizationOutput, error) {
+	return nil, readOnlyError("DescribeOrganizationWithContext")
+}
+
+func (refusedOrganizations) DescribeOrganizationalUnit(_ *organizations.DescribeOrganizationalUnitInput) (*organizations.DescribeOrganizationalUnitOutput, error) {
+	return nil, readOnlyError("DescribeOrganizationalUnit")
+}
+
+func (refusedOrganizations) DescribeOrganizationalUnitRequest(_ *organizations.DescribeOrganizationalUnitInput) (*request.Request, *organizations.DescribeOrganizationalUnitOutput) {
+	return &request.Request{Error: readOnlyError("DescribeOrganizationalUnitRequest")}, nil
+}
+
+func (refusedOrganizations) DescribeOrganizationalUnitWithContext(_ context.Context, _ *organizations.DescribeOrganizationalUnitInput, _ ...request.Option) (*organizations.DescribeOrganizationalUnitOutput, error) {
+	return nil, readOnlyError("DescribeOrganizationalUnitWithContext")
+}
+
+func (refusedOrganizations) DescribePolicy(_ *organizations.DescribePolicyInput) (*organizations.DescribePolicyOutput, error) {
+	return nil, readOnlyError("DescribePolicy")
+}
+
+func (refusedOrganizations) DescribePolicyRequest(_ *organizations.DescribePolicyInput) (*request.Request, *organizations.DescribePolicyOutput) {
+	return &request.Request{Error: readOnlyError("DescribePolicyRequest")}, nil
+}
+
+func (refusedOrganizations) DescribePolicyWithContext(_ context.Context, _ *organizations.DescribePolicyInput, _ ...request.Option) (*organizations.DescribePolicyOutput, error) {
+	return nil, readOnlyError("DescribePolicyWithContext")
+}
+
+func (refusedOrganizations) DescribeResourcePolicy(_ *organizations.DescribeResourcePolicyInput) (*organizations.DescribeResourcePolicyOutput, error) {
+	return nil, readOnlyError("DescribeResourcePolicy")
+}
+
+func (refusedOrganizations) DescribeResourcePolicyRequest(_ *organizations.DescribeResourcePolicyInput) (*request.Request, *organizations.DescribeResourcePolicyOutput) {
+	return &request.Request{Error: readOnlyError("DescribeResourcePolicyRequest")}, nil
+}
+
+func (refusedOrganizations) DescribeResourcePolicyWithContext(_ context.Context, _ *organizations.DescribeResourcePolicyInput, _ ...request.Option) (*organizations.DescribeResourcePolicyOutput, error) {
+	return nil, readOnlyError("DescribeResourcePolicyWithContext")
+}
+
+func (refusedOrganizations) DetachPolicy(_ *organizations.DetachPolicyInput) (*organizations.DetachPolicyOutput, error) {
+	return nil, readOnlyError("DetachPolicy")
+}
+
+func (refusedOrganizations) DetachPolicyRequest(_ *organizations.DetachPolicyInput) (*request.Request, *organizations.DetachPolicyOutput) {
+	return &request.Request{Error: readOnlyError("DetachPolicyRequest")}, nil
+}
+
+func (refusedOrganizations) DetachPolicyWithContext(_ context.Context, _ *organizations.DetachPolicyInput, _ ...request.Option) (*organizations.DetachPolicyOutput, error) {
+	return nil, readOnlyError("DetachPolicyWithContext")
+}
+
+func (refusedOrganizations) DisableAWSServiceAccess(_ *organizations.DisableAWSServiceAccessInput) (*organizations.DisableAWSServiceAccessOutput, error) {
+	return nil, readOnlyError("DisableAWSServiceAccess")
+}
+
+func (refusedOrganizations) DisableAWSServiceAccessRequest(_ *organizations.DisableAWSServiceAccessInput) (*request.Request, *organizations.DisableAWSServiceAccessOutput) {
+	return &request.Request{Error: readOnlyError("DisableAWSServiceAccessRequest")}, nil
+}
+
+func (refusedOrganizations) DisableAWSServiceAccessWithContext(_ context.Context, _ *organizations.DisableAWSServiceAccessInput, _ ...request.Option) (*organizations.DisableAWSServiceAccessOutput, error) {
+	return nil, readOnlyError("DisableAWSServiceAccessWithContext")
+}
+
+func (refusedOrganizations) DisablePolicyType(_ *organizations.DisablePolicyTypeInput) (*organizations.DisablePolicyTypeOutput, error) {
+	return nil, readOnlyError("DisablePolicyType")
+}
+
+func (refusedOrganizations) DisablePolicyTypeRequest(_ *organizations.DisablePolicyTypeInput) (*request.Request, *organizations.DisablePolicyTypeOutput) {
+	return &request.Request{Error: readOnlyError("DisablePolicyTypeRequest")}, nil
+}
+
+func (refusedOrganizations) DisablePolicyTypeWithContext(_ context.Context, _ *organizations.DisablePolicyTypeInput, _ ...request.Option) (*organizations.DisablePolicyTypeOutput, error) {
+	return nil, readOnlyError("DisablePolicyTypeWithContext")
+}
+
+func (refusedOrganizations) EnableAWSServiceAccess(_ *organizations.EnableAWSServiceAccessInput) (*organizations.EnableAWSServiceAccessOutput, error) {
+	return nil, readOnlyError("EnableAWSServiceAccess")
+}
+
+func (refusedOrganizations) EnableAWSServiceAccessRequest(_ *organizations.EnableAWSServiceAccessInput) (*request.Request, *organizations.EnableAWSServiceAccessOutput) {
+	return &request.Request{Error: readOnlyError("EnableAWSServiceAccessRequest")}, nil
+}
+
+func (refusedOrganizations) EnableAWSServiceAccessWithContext(_ context.Context, _ *organizations.EnableAWSServiceAccessInput, _ ...request.Option) (*organizations.EnableAWSServiceAccessOutput, error) {
+	return nil, readOnlyError("EnableAWSServiceAccessWithContext")
+}
+
+func (refusedOrganizations) EnableAllFeatures(_ *organizations.EnableAllFeaturesInput) (*organizations.EnableAllFeaturesOutput, error) {
+	return nil, readOnlyError("EnableAllFeatures")
+}
+
+func (refusedOrganizations) EnableAllFeaturesRequest(_ *organizations.EnableAllFeaturesInput) (*request.Request, *organizations.EnableAllFeaturesOutput) {
+	return &request.Request{Error: readOnlyError("EnableAllFeaturesRequest")}, nil
+}
+
+func (refusedOrganizations) EnableAllFeaturesWithContext(_ context.Context, _ *organizations.EnableAllFeaturesInput, _ ...request.Option) (*organizations.EnableAllFeaturesOutput, error) {
+	return nil, readOnlyError("EnableAllFeaturesWithContext")
+}
+
+func (refusedOrganizations) EnablePolicyType(_ *organizations.EnablePolicyTypeInput) (*organizations.EnablePolicyTypeOutput, error) {
+	return nil, readOnlyError("EnablePolicyType")
+}
+
+func (refusedOrganizations) EnablePolicyTypeRequest(_ *organizations.EnablePolicyTypeInput) (*request.Request, *organizations.EnablePolicyTypeOutput) {
+	return &request.Request{Error: readOnlyError("EnablePolicyTypeRequest")}, nil
+}
+
+func (refusedOrganizations) EnablePolicyTypeWithContext(_ context.Context, _ *organizations.EnablePolicyTypeInput, _ ...request.Option) (*organizations.EnablePolicyTypeOutput, error) {
+	return nil, readOnlyError("EnablePolicyTypeWithContext")
+}
+
+func (refusedOrganizations) InviteAccountToOrganization(_ *organizations.InviteAccountToOrganizationInput) (*organizations.InviteAccountToOrganizationOutput, error) {
+	return nil, readOnlyError("InviteAccountToOrganization")
+}
+
+func (refusedOrganizations) InviteAccountToOrganizationRequest(_ *organizations.InviteAccountToOrganizationInput) (*request.Request, *organizations.InviteAccountToOrganizationOutput) {
+	return &request.Request{Error: readOnlyError("InviteAccountToOrganizationRequest")}, nil
+}
+
+func (refusedOrganizations) InviteAccountToOrganizationWithContext(_ context.Context, _ *organizations.InviteAccountToOrganizationInput, _ ...request.Option) (*organizations.InviteAccountToOrganizationOutput, error) {
+	return nil, readOnlyError("InviteAccountToOrganizationWithContext")
+}
+
+func (refusedOrganizations) LeaveOrganization(_ *organizations.LeaveOrganizationInput) (*organizations.LeaveOrganizationOutput, error) {
+	return nil, readOnlyError("LeaveOrganization")
+}
+
+func (refusedOrganizations) LeaveOrganizationRequest(_ *organizations.LeaveOrganizationInput) (*request.Request, *organizations.LeaveOrganizationOutput) {
+	return &request.Request{Error: readOnlyError("LeaveOrganizationRequest")}, nil
+}
+
+func (refusedOrganizations) LeaveOrganizationWithContext(_ context.Context, _ *organizations.LeaveOrganizationInput, _ ...request.Option) (*organizations.LeaveOrganizationOutput, error) {
+	return nil, readOnlyError("LeaveOrganizationWithContext")
+}
+
+func (refusedOrganizations) ListAWSServiceAccessForOrganization(_ *organizations.ListAWSServiceAccessForOrganizationInput) (*organizations.ListAWSServiceAccessForOrganizationOutput, error) {
+	return nil, readOnlyError("ListAWSServiceAccessForOrganization")
+}
+
+func (refusedOrganizations) ListAWSServiceAccessForOrganizationPages(_ *organizations.ListAWSServiceAccessForOrganizationInput, _ func(*organizations.ListAWSServiceAccessForOrganizationOutput, bool) bool) error {
+	return readOnlyError("ListAWSServiceAccessForOrganizationPages")
+}
+
+func (refusedOrganizations) ListAWSServiceAccessForOrganizationPagesWithContext(_ context.Context, _ *organizations.ListAWSServiceAccessForOrganizationInput, _ func(*organizations.ListAWSServiceAccessForOrganizationOutput, bool) bool, _ ...request.Option) error {
+	return readOnlyError("ListAWSServiceAccessForOrganizationPagesWithContext")
+}
+
+func (refusedOrganizations) ListAWSServiceAccessForOrganizationRequest(_ *organizations.ListAWSServiceAccessForOrganizationInput) (*request.Request, *organizations.ListAWSServiceAccessForOrganizationOutput) {
+	return &request.Request{Error: readOnlyError("ListAWSServiceAccessForOrganizationRequest")}, nil
+}
+
+func (refusedOrganizations) ListAWSServiceAccessForOrganizationWithContext(_ context.Context, _ *organizations.ListAWSServiceAccessForOrganizationInput, _ ...request.Option) (*organizations.ListAWSServiceAccessForOrganizationOutput, error) {
+	return nil, readOnlyError("ListAWSServiceAccessForOrganizationWithContext")
+}
+
+func (refusedOrganizations) ListAccounts(_ *organizations.ListAccountsInput) (*organizations.ListAccountsOutput, error) {
+	return nil, readOnlyError("ListAccounts")
+}
+
+func (refusedOrganizations) ListAccountsForParent(_ *organizations.ListAccountsForParentInput) (*organizations.ListAccountsForParentOutput, error) {
+	return nil, readOnlyError("ListAccountsForParent")
+}
+
+func (refusedOrganizations) ListAccountsForParentPages(_ *organizations.ListAccountsForParentInput, _ func(*organizations.ListAccountsForParentOutput, bool) bool) error {
+	return readOnlyError("ListAccountsForParentPages")
+}
+
+func (refusedOrganizations) ListAccountsForParentPagesWithContext(_ context.Context, _ *organizations.ListAccountsForParentInput, _ func(*organizations.ListAccountsForParentOutput, bool) bool, _ ...request.Option) error {
+	return readOnlyError("ListAccountsForParentPagesWithContext")
+}
+
+func (refusedOrganizations) ListAccountsForParentRequest(_ *organizations.ListAccountsForParentInput) (*request.Request, *organizations.ListAccountsForParentOutput) {
+	return &request.Request{Error: readOnlyError("ListAccountsForParentRequest")}, nil
+}
+
+func (refusedOrganizations) ListAccountsForParentWithContext(_ context.Context, _ *organizations.ListAccountsForParentInput, _ ...request.Option) (*organizations.ListAccountsForParentOutput, error) {
+	return nil, readOnlyError("ListAccountsForParentWithContext")
+}
+
+func (refusedOrganizations) ListAccountsPages(_ *organizations.ListAccountsInput, _ func(*organizations.ListAccountsOutput, bool) bool) error {
+	return readOnlyError("ListAccountsPages")
+}
+
+func (refusedOrganizations) ListAccountsPagesWithContext(_ context.Context, _ *organizations.ListAccountsInput, _ func(*organizations.ListAccountsOutput, bool) bool, _ ...request.Option) error {
+	return readOnlyError("ListAccountsPagesWithContext")
+}
+
+func (refusedOrganizations) ListAccountsRequest(_ *organizations.ListAccountsInput) (*request.Request, *organizations.ListAccountsOutput) {
+	return &request.Request{Error: readOnlyError("ListAccountsRequest")}, nil
+}
+
+func (refusedOrganizations) ListAccountsWithContext(_ context.Context, _ *organizations.ListAccountsInput, _ ...request.Option) (*organizations.ListAccountsOutput, error) {
+	return nil, readOnlyError("ListAccountsWithContext")
+}
+
+func (refusedOrganizations) ListChildren(_ *organizations.ListChildrenInput) (*organizations.ListChildrenOutput, error) {
+	return nil, readOnlyError("ListChildren")
+}
+
+func (refusedOrganizations) ListChildrenPages(_ *organizations.ListChildrenInput, _ func(*organizations.ListChildrenOutput, bool) bool) error {
+	return readOnlyError("ListChildrenPages")
+}
+
+func (refusedOrganizations) ListChildrenPagesWithContext(_ context.Context, _ *organizations.ListChildrenInput, _ func(*organizations.ListChildrenOutput, bool) bool, _ ...request.Option) error {
+	return readOnlyError("ListChildrenPagesWithContext")
+}
+
+func (refusedOrganizations) ListChildrenRequest(_ *organizations.ListChildrenInput) (*request.Request, *organizations.ListChildrenOutput) {
+	return &request.Request{Error: readOnlyError("ListChildrenRequest")}, nil
+}
+
+func (refusedOrganizations) ListChildrenWithContext(_ context.Context, _ *organizations.ListChildrenInput, _ ...request.Option) (*organizations.ListChildrenOutput, error) {
+	return nil, readOnlyError("ListChildrenWithContext")
+}
+
+func (refusedOrganizations) ListCreateAccountStatus(_ *organizations.ListCreateAccountStatusInput) (*organizations.ListCreateAccountStatusOutput, error) {
+	return nil, readOnlyError("ListCreateAccountStatus")
+}
+
+func (refusedOrganizations) ListCreateAccountStatusPages(_ *organizations.ListCreateAccountStatusInput, _ func(*organizations.ListCreateAccountStatusOutput, bool) bool) error {
+	return readOnlyError("ListCreateAccountStatusPages")
+}
+
+func (refusedOrganizations) ListCreateAccountStatusPagesWithContext(_ context.Context, _ *organizations.ListCreateAccountStatusInput, _ func(*organizations.ListCreateAccountStatusOutput, bool) bool, _ ...request.Option) error {
+	return readOnlyError("ListCreateAccountStatusPagesWithContext")
+}
+
+func (refusedOrganizations) ListCreateAccountStatusRequest(_ *organizations.ListCreateAccountStatusInput) (*request.Request, *organizations.ListCreateAccountStatusOutput) {
+	return &request.Request{Error: readOnlyError("ListCreateAccountStatusRequest")}, nil
+}
+
+func (refusedOrganizations) ListCreateAccountStatusWithContext(_ context.Context, _ *organizations.ListCreateAccountStatusInput, _ ...request.Option) (*organizations.ListCreateAccountStatusOutput, error) {
+	return nil, readOnlyError("ListCreateAccountStatusWithContext")
+}
+
+func (refusedOrganizations) ListDelegatedAdministrators(_ *organizations.ListDelegatedAdministratorsInput) (*organizations.ListDelegatedAdministratorsOutput, error) {
+	return nil, readOnlyError("ListDelegatedAdministrators")
+}
+
+func (refusedOrganizations) ListDelegatedAdministratorsPages(_ *organizations.ListDelegatedAdministratorsInput, _ func(*organizations.ListDelegatedAdministratorsOutput, bool) bool) error {
+	return readOnlyError("ListDelegatedAdministratorsPages")
+}
+
+func (refusedOrganizations) ListDelegatedAdministratorsPagesWithContext(_ context.Context, _ *organizations.ListDelegatedAdministratorsInput, _ func(*organizations.ListDelegatedAdministratorsOutput, bool) bool, _ ...request.Option) error {
+	return readOnlyError("ListDelegatedAdministratorsPagesWithContext")
+}
+
+func (refusedOrganizations) ListDelegatedAdministratorsRequest(_ *organizations.ListDelegatedAdministratorsInput) (*request.Request, *organizations.ListDelegatedAdministratorsOutput) {
+	return &request.Request{Error: readOnlyError("ListDelegatedAdministratorsRequest")}, nil
+}
+
+func (refusedOrganizations) ListDelegatedAdministratorsWithContext(_ context.Context, _ *organizations.ListDelegatedAdministratorsInput, _ ...request.Option) (*organizations.ListDelegatedAdministratorsOutput, error) {
+	return nil, readOnlyError("ListDelegatedAdministratorsWithContext")
+}
+
+func (refusedOrganizations) ListDelegatedServicesForAccount(_ *organizations.ListDelegatedServicesForAccountInput) (*organizations.ListDelegatedServicesForAccountOutput, error) {
+	return nil, readOnlyError("ListDelegatedServicesForAccount")
+}
+
+func (refusedOrganizations) ListDelegatedServicesForAccountPages(_ *organizations.ListDelegatedServicesForAccountInput, _ func(*organizations.ListDelegatedServicesForAccountOutput, bool) bool) error {
+	return readOnlyError("ListDelegatedServicesForAccountPages")
+}
+
+func (refusedOrganizations) ListDelegatedServicesForAccountPagesWithContext(_ context.Context, _ *organizations.ListDelegatedServicesForAccountInput, _ func(*organizations.ListDelegatedServicesForAccountOutput, bool) bool, _ ...request.Option) error {
+	return readOnlyError("ListDelegatedServicesForAccountPagesWithContext")
+}
+
+func (refusedOrganizations) ListDelegatedServicesForAccountRequest(_ *organizations.ListDelegatedServicesForAccountInput) (*request.Request, *organizations.ListDelegatedServicesForAccountOutput) {
+	return &request.Request{Error: readOnlyError("ListDelegatedServicesForAccountRequest")}, nil
+}
+
+func (refusedOrganizations) ListDelegatedServicesForAccountWithContext(_ context.Context, _ *organizations.ListDelegatedServicesForAccountInput, _ ...request.Option) (*organizations.ListDelegatedServicesForAccountOutput, error) {
+	return nil, readOnlyError("ListDelegatedServicesForAccountWithContext")
+}
+
+func (refusedOrganizations) ListHandshakesForAccount(_ *organizations.ListHandshakesForAccountInput) (*organizations.ListHandshakesForAccountOutput, error) {
+	return nil, readOnlyError("ListHandshakesForAccount")
+}
+
+func (refusedOrganizations) ListHandshakesForAccountPages(_ *organizations.ListHandshakesForAccountInput, _ func(*organizations.ListHandshakesForAccountOutput, bool) bool) error {
+	return readOnlyError("ListHandshakesForAccountPages")
+}
+
+func (refusedOrganizations) ListHandshakesForAccountPagesWithContext(_ context.Context, _ *organizations.ListHandshakesForAccountInput, _ func(*organizations.ListHandshakesForAccountOutput, bool) bool, _ ...request.Option) error {
+	return readOnlyError("ListHandshakesForAccountPagesWithContext")
+}
+
+func (refusedOrganizations) ListHandshakesForAccountRequest(_ *organizations.ListHandshakesForAccountInput) (*request.Request, *organizations.ListHandshakesForAccountOutput) {
+	return &request.Request{Error: readOnlyError("ListHandshakesForAccountRequest")}, nil
+}
+
+func (refusedOrganizations) ListHandshakesForAccountWithContext(_ context.Context, _ *organizations.ListHandshakesForAccountInput, _ ...request.Option) (*organizations.ListHandshakesForAccountOutput, error) {
+	return nil, readOnlyError("ListHandshakesForAccountWithContext")
+}
+
+func (refusedOrganizations) ListHandshakesForOrganization(_ *organizations.ListHandshakesForOrganizationInput) (*organizations.ListHandshakesForOrganizationOutput, error) {
+	return nil, readOnlyError("ListHandshakesForOrganization")
+}
+
+func (refusedOrganizations) ListHandshakesForOrganizationPages(_ *organizations.ListHandshakesForOrganizationInput, _ func(*organizations.ListHandshakesForOrganizationOutput, bool) bool) error {
+	return readOnlyError("ListHandshakesForOrganizationPages")
+}
+
+func (refusedOrganizations) ListHandshakesForOrganizationPagesWithContext(_ context.Context, _ *organizations.ListHandshakesForOrganizationInput, _ func(*organizations.ListHandshakesForOrganizationOutput, bool) bool, _ ...request.Option) error {
+	return readOnlyError("ListHandshakesForOrganizationPagesWithContext")
+}
+
+func (refusedOrganizations) ListHandshakesForOrganizationRequest(_ *organizations.ListHandshakesForOrganizationInput) (*request.Request, *organizations.ListHandshakesForOrganizationOutput) {
+	return &request.Request{Error: readOnlyError("ListHandshakesForOrganizationRequest")}, nil
+}
+
+func (refusedOrganizations) ListHandshakesForOrganizationWithContext(_ context.Context, _ *organizations.ListHandshakesForOrganizationInput, _ ...request.Option) (*organizations.ListHandshakesForOrganizationOutput, error) {
+	return nil, readOnlyError("ListHandshakesForOrganizationWithContext")
+}
+
+func (refusedOrganizations) ListOrganizationalUnitsForParent(_ *organizations.ListOrganizationalUnitsForParentInput) (*organizations.ListOrganizationalUnitsForParentOutput, error) {
+	return nil, readOnlyError("ListOrganizationalUnitsForParent")
+}
+
+func (refusedOrganizations) ListOrganizationalUnitsForParentPages(_ *organizations.ListOrganizationalUnitsForParentInput, _ func(*organizations.ListOrganizationalUnitsForParentOutput, bool) bool) error {
+	return readOnlyError("ListOrganizationalUnitsForParentPages")
+}
+
+func (refusedOrganizations) ListOrganizationalUnitsForParentPagesWithContext(_ context.Context, _ *organizations.ListOrganizationalUnitsForParentInput, _ func(*organizations.ListOrganizationalUnitsForParentOutput, bool) bool, _ ...request.Option) error {
+	return readOnlyError("ListOrganizationalUnitsForParentPagesWithContext")
+}
+
+func (refusedOrganizations) ListOrganizationalUnitsForParentRequest(_ *organizations.ListOrganizationalUnitsForParentInput) (*request.Request, *organizations.ListOrganizationalUnitsForParentOutput) {
+	return &request.Request{Error: readOnlyError("ListOrganizationalUnitsForParentRequest")}, nil
+}
+
+func (refusedOrganizations) ListOrganizationalUnitsForParentWithContext(_ context.Context, _ *organizations.ListOrganizationalUnitsForParentInput, _ ...request.Option) (*organizations.ListOrganizationalUnitsForParentOutput, error) {
+	return nil, readOnlyError("ListOrganizationalUnitsForParentWithContext")
+}
+
+func (refusedOrganizations) ListParents(_ *organizations.ListParentsInput) (*organizations.ListParentsOutput, error) {
+	return nil, readOnlyError("ListParents")
+}
+
+func (refusedOrganizations) ListParentsPages(_ *organizations.ListParentsInput, _ func(*organizations.ListParentsOutput, bool) bool) error {
+	return readOnlyError("ListParentsPages")
+}
+
+func (refusedOrganizations) ListParentsPagesWithContext(_ context.Context, _ *organizations.ListParentsInput, _ func(*organizations.ListParentsOutput, bool) bool, _ ...request.Option) error {
+	return readOnlyError("ListParentsPagesWithContext")
+}
+
+func (refusedOrganizations) ListParentsRequest(_ *organizations.ListParentsInput) (*request.Request, *organizations.ListParentsOutput) {
+	return &request.Request{Error: readOnlyError("ListParentsRequest")}, nil
+}
+
+func (refusedOrganizations) ListParentsWithContext(_ context.Context, _ *organizations.ListParentsInput, _ ...request.Option) (*organizations.ListParentsOutput, error) {
+	return nil, readOnlyError("ListParentsWithContext")
+}
+
+func (refusedOrganizations) ListPolicies(_ *organizations.ListPoliciesInput) (*organizations.ListPoliciesOutput, error) {
+	return nil, readOnlyError("ListPolicies")
+}
+
+func (refusedOrganizations) ListPoliciesForTarget(_ *organizations.ListPoliciesForTargetInput) (*organizations.ListPoliciesForTargetOutput, error) {
+	return nil, readOnlyError("ListPoliciesForTarget")
+}
+
+func (refusedOrganizations) ListPoliciesForTargetPages(_ *organizations.ListPoliciesForTargetInput, _ func(*organizations.ListPoliciesForTargetOutput, bool) bool) error {
+	return readOnlyError("ListPoliciesForTargetPages")
+}
+
+func (refusedOrganizations) ListPoliciesForTargetPagesWithContext(_ context.Context, _ *organizations.ListPoliciesForTargetInput, _ func(*organizations.ListPoliciesForTargetOutput, bool) bool, _ ...request.Option) error {
+	return readOnlyError("ListPoliciesForTargetPagesWithContext")
+}
+
+func (refusedOrganizations) ListPoliciesForTargetRequest(_ *organizations.ListPoliciesForTargetInput) (*request.Request, *organizations.ListPoliciesForTargetOutput) {
+	return &request.Request{Error: readOnlyError("ListPoliciesForTargetRequest")}, nil
+}
+
+func (refusedOrganizations) ListPoliciesForTargetWithContext(_ context.Context, _ *organizations.ListPoliciesForTargetInput, _ ...request.Option) (*organizations.ListPoliciesForTargetOutput, error) {
+	return nil, readOnlyError("ListPoliciesForTargetWithContext")
+}
+
+func (refusedOrganizations) ListPoliciesPages(_ *organizations.ListPoliciesInput, _ func(*organizations.ListPoliciesOutput, bool) bool) error {
+	return readOnlyError("ListPoliciesPages")
+}
+
+func (refusedOrganizations) ListPoliciesPagesWithContext(_ context.Context, _ *organizations.ListPoliciesInput, _ func(*organizations.ListPoliciesOutput, bool) bool, _ ...request.Option) error {
+	return readOnlyError("ListPoliciesPagesWithContext")
+}
+
+func (refusedOrganizations) ListPoliciesRequest(_ *organizations.ListPoliciesInput) (*request.Request, *organizations.ListPoliciesOutput) {
+	return &request.Request{Error: readOnlyError("ListPoliciesRequest")}, nil
+}
+
+func (refusedOrganizations) ListPoliciesWithContext(_ context.Context, _ *organizations.ListPoliciesInput, _ ...request.Option) (*organizations.ListPoliciesOutput, error) {
+	return nil, readOnlyError("ListPoliciesWithContext")
+}
+
+func (refusedOrganizations) ListRoots(_ *organizations.ListRootsInput) (*organizations.ListRootsOutput, error) {
+	return nil, readOnlyError("ListRoots")
+}
+
+func (refusedOrganizations) ListRootsPages(_ *organizations.ListRootsInput, _ func(*organizations.ListRootsOutput, bool) bool) error {
+	return readOnlyError("ListRootsPages")
+}
+
+func (refusedOrganizations) ListRootsPagesWithContext(_ context.Context, _ *organizations.ListRootsInput, _ func(*organizations.ListRootsOutput, bool) bool, _ ...request.Option) error {
+	return readOnlyError("ListRootsPagesWithContext")
+}
+
+func (refusedOrganizations) ListRootsRequest(_ *organizations.ListRootsInput) (*request.Request, *organizations.ListRootsOutput) {
+	return &request.Request{Error: readOnlyError("ListRootsRequest")}, nil
+}
+
+func (refusedOrganizations) ListRootsWithContext(_ context.Context, _ *organizations.ListRootsInput, _ ...request.Option) (*organizations.ListRootsOutput, error) {
+	return nil, readOnlyError("ListRootsWithContext")
+}
+
+func (refusedOrganizations) ListTagsForResource(_ *organizations.ListTagsForResourceInput) (*organizations.ListTagsForResourceOutput, error) {
+	return nil, readOnlyError("ListTagsForResource")
+}
+
+func (refusedOrganizations) ListTagsForResourcePages(_ *organizations.ListTagsForResourceInput, _ func(*organizations.ListTagsForResourceOutput, bool) bool) error {
+	return readOnlyError("ListTagsForResourcePages")
+}
+
+func (refusedOrganizations) ListTagsForResourcePagesWithContext(_ context.Context, _ *organizations.ListTagsForResourceInput, _ func(*organizations.ListTagsForResourceOutput, bool) bool, _ ...request.Option) error {
+	return readOnlyError("ListTagsForResourcePagesWithContext")
+}
+
+func (refusedOrganizations) ListTagsForResourceRequest(_ *organizations.ListTagsForResourceInput) (*request.Request, *organizations.ListTagsForResourceOutput) {
+	return &request.Request{Error: readOnlyError("ListTagsForResourceRequest")}, nil
+}
+
+func (refusedOrganizations) ListTagsForResourceWithContext(_ context.Context, _ *organizations.ListTagsForResourceInput, _ ...request.Option) (*organizations.ListTagsForResourceOutput, error) {
+	return nil, readOnlyError("ListTagsForResourceWithContext")
+}
+
+func (refusedOrganizations) ListTargetsForPolicy(_ *organizations.ListTargetsForPolicyInput) (*organizations.ListTargetsForPolicyOutput, error) {
+	return nil, readOnlyError("ListTargetsForPolicy")
+}
+
+func (refusedOrganizations) ListTargetsForPolicyPages(_ *organizations.ListTargetsForPolicyInput, _ func(*organizations.ListTargetsForPolicyOutput, bool) bool) error {
+	return readOnlyError("ListTargetsForPolicyPages")
+}
+
+func (refusedOrganizations) ListTargetsForPolicyPagesWithContext(_ context.Context, _ *organizations.ListTargetsForPolicyInput, _ func(*organizations.ListTargetsForPolicyOutput, bool) bool, _ ...request.Option) error {
+	return readOnlyError("ListTargetsForPolicyPagesWithContext")
+}
+
+func (refusedOrganizations) ListTargetsForPolicyRequest(_ *organizations.ListTargetsForPolicyInput) (*request.Request, *organizations.ListTargetsForPolicyOutput) {
+	return &request.Request{Error: readOnlyError("ListTargetsForPolicyRequest")}, nil
+}
+
+func (refusedOrganizations) ListTargetsForPolicyWithContext(_ context.Context, _ *organizations.ListTargetsForPolicyInput, _ ...request.Option) (*organizations.ListTargetsForPolicyOutput, error) {
+	return nil, readOnlyError("ListTargetsForPolicyWithContext")
+}
+
+func (refusedOrganizations) MoveAccount(_ *organizations.MoveAccountInput) (*organizations.MoveAccountOutput, error) {
+	return nil, readOnlyError("MoveAccount")
+}
+
+func (refusedOrganizations) MoveAccountRequest(_ *organizations.MoveAccountInput) (*request.Request, *organizations.MoveAccountOutput) {
+	return &request.Request{Error: readOnlyError("MoveAccountRequest")}, nil
+}
+
+func (refusedOrganizations) MoveAccountWithContext(_ context.Context, _ *organizations.MoveAccountInput, _ ...request.Option) (*organizations.MoveAccountOutput, error) {
+	return nil, readOnlyError("MoveAccountWithContext")
+}
+
+func (refusedOrganizations) PutResourcePolicy(_ *organizations.PutResourcePolicyInput) (*organizations.PutResourcePolicyOutput, error) {
+	return nil, readOnlyError("PutResourcePolicy")
+}
+
+func (refusedOrganizations) PutResourcePolicyRequest(_ *organizations.PutResourcePolicyInput) (*request.Request, *organizations.PutResourcePolicyOutput) {
+	return &request.Request{Error: readOnlyError("PutResourcePolicyRequest")}, nil
+}
+
+func (refusedOrganizations) PutResourcePolicyWithContext(_ context.Context, _ *organizations.PutResourcePolicyInput, _ ...request.Option) (*organizations.PutResourcePolicyOutput, error) {
+	return nil, readOnlyError("PutResourcePolicyWithContext")
+}
+
+func (refusedOrganizations) RegisterDelegatedAdministrator(_ *organizations.RegisterDelegatedAdministratorInput) (*organizations.RegisterDelegatedAdministratorOutput, error) {
+	return nil, readOnlyError("RegisterDelegatedAdministrator")
+}
+
+func (refusedOrganizations) RegisterDelegatedAdministratorRequest(_ *organizations.RegisterDelegatedAdministratorInput) (*request.Request, *organizations.RegisterDelegatedAdministratorOutput) {
+	return &request.Request{Error: readOnlyError("RegisterDelegatedAdministratorRequest")}, nil
+}
+
+func (refusedOrganizations) RegisterDelegatedAdministratorWithContext(_ context.Context, _ *organizations.RegisterDelegatedAdministratorInput, _ ...request.Option) (*organizations.RegisterDelegatedAdministratorOutput, error) {
+	return nil, readOnlyError("RegisterDelegatedAdministratorWithContext")
+}
+
+func (refusedOrganizations) RemoveAccountFromOrganization(_ *organizations.RemoveAccountFromOrganizationInput) (*organizations.RemoveAccountFromOrganizationOutput, error) {
+	return nil, readOnlyError("RemoveAccountFromOrganization")
+}
+
+func (refusedOrganizations) RemoveAccountFromOrganizationRequest(_ *organizations.RemoveAccountFromOrganizationInput) (*request.Request, *organizations.RemoveAccountFromOrganizationOutput) {
+	return &request.Request{Error: readOnlyError("RemoveAccountFromOrganizationRequest")}, nil
+}
+
+func (refusedOrganizations) RemoveAccountFromOrganizationWithContext(_ context.Context, _ *organizations.RemoveAccountFromOrganizationInput, _ ...request.Option) (*organizations.RemoveAccountFromOrganizationOutput, error) {
+	return nil, readOnlyError("RemoveAccountFromOrganizationWithContext")
+}
+
+func (refusedOrganizations) TagResource(_ *organizations.TagResourceInput) (*organizations.TagResourceOutput, error) {
+	return nil, readOnlyError("TagResource")
+}
+
+func (refusedOrganizations) TagResourceRequest(_ *organizations.TagResourceInput) (*request.Request, *organizations.TagResourceOutput) {
+	return &request.Request{Error: readOnlyError("TagResourceRequest")}, nil
+}
+
+func (refusedOrganizations) TagResourceWithContext(_ context.Context, _ *organizations.TagResourceInput, _ ...request.Option) (*organizations.TagResourceOutput, error) {
+	return nil, readOnlyError("TagResourceWithContext")
+}
+
+func (refusedOrganizations) UntagResource(_ *organizations.UntagResourceInput) (*organizations.UntagResourceOutput, error) {
+	return nil, readOnlyError("UntagResource")
+}
+
+func (refusedOrganizations) UntagResourceRequest(_ *organizations.UntagResourceInput) (*request.Request, *organizations.UntagResourceOutput) {
+	return &request.Request{Error: readOnlyError("UntagResourceRequest")}, nil
+}
+
+func (refusedOrganizations) UntagResourceWithContext(_ context.Context, _ *organizations.UntagResourceInput, _ ...request.Option) (*organizations.UntagResourceOutput, error) {
+	return nil, readOnlyError("UntagResourceWithContext")
+}
+
+func (refusedOrganizations) UpdateOrganizationalUnit(_ *organizations.UpdateOrganizationalUnitInput) (*organizations.UpdateOrganizationalUnitOutput, error) {
+	return nil, readOnlyError("UpdateOrganizationalUnit")
+}
+
+func (refusedOrganizations) UpdateOrganizationalUnitRequest(_ *organizations.UpdateOrganizationalUnitInput) (*request.Request, *organizations.UpdateOrganizationalUnitOutput) {
+	return &request.Request{Error: readOnlyError("UpdateOrganizationalUnitRequest")}, nil
+}
+
+func (refusedOrganizations) UpdateOrganizationalUnitWithContext(_ context.Context, _ *organizations.UpdateOrganizationalUnitInput, _ ...request.Option) (*organizations.UpdateOrganizationalUnitOutput, error) {
+	return nil, readOnlyError("UpdateOrganizationalUnitWithContext")
+}
+
+func (refusedOrganizations) UpdatePolicy(_ *organizations.UpdatePolicyInput) (*organizations.UpdatePolicyOutput, error) {
+	return nil, readOnlyError("UpdatePolicy")
+}
+
+func (refusedOrganizations) UpdatePolicyRequest(_ *organizations.UpdatePolicyInput) (*request.Request, *organizations.UpdatePolicyOutput) {
+	return &request.Request{Error: readOnlyError("UpdatePolicyRequest")}, nil
+}
+
+func (refusedOrganizations) UpdatePolicyWithContext(_ context.Context, _ *organizations.UpdatePolicyInput, _ ...request.Option) (*organizations.UpdatePolicyOutput, error) {
+	return nil, readOnlyError("UpdatePolicyWithContext")
+}
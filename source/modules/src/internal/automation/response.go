@@ -0,0 +1,57 @@
+package automation
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// HandleErrors reports error to the caller with the status, code and retryable hint ClassifyError gives it, as a
+// JSON body. statusCode is the status for errors that are not classified by their own code.
+func HandleErrors(error error, statusCode int) (*events.APIGatewayProxyResponse, error) {
+	log.Println("ERROR: ", error.Error())
+	apiError := ClassifyError(error, statusCode)
+	// an APIError only holds strings and a bool, so it always marshals
+	jsonResponseBody, _ := json.Marshal(apiError)
+	response := &events.APIGatewayProxyResponse{
+		StatusCode: apiError.StatusCode,
+		Body:       string(jsonResponseBody),
+	}
+
+	// return empty error to allow apigw to accurately represent statusCode and error.Error()
+	// removing nil will 'make' apigw think the lambda is not exiting gracefully
+	return response, nil
+}
+
+// HandleNamingError reports an account name that does not follow the naming convention as a 400 whose JSON body
+// names the convention, the segment that is wrong and why.
+func HandleNamingError(namingError *NamingError) (*events.APIGatewayProxyResponse, error) {
+	log.Println("ERROR: ", namingError.Error())
+	jsonResponseBody, error := json.Marshal(namingError)
+	if error != nil {
+		return HandleErrors(error, 500)
+	}
+
+	response := &events.APIGatewayProxyResponse{
+		StatusCode: 400,
+		Body:       string(jsonResponseBody),
+	}
+	return response, nil
+}
+
+// HandleValidationError reports every field of a request that is not valid as a 400 whose JSON body names each
+// field, why it is not valid and a code for it.
+func HandleValidationError(validationError *ValidationError) (*events.APIGatewayProxyResponse, error) {
+	log.Println("ERROR: ", validationError.Error())
+	jsonResponseBody, error := json.Marshal(validationError)
+	if error != nil {
+		return HandleErrors(error, 500)
+	}
+
+	response := &events.APIGatewayProxyResponse{
+		StatusCode: validationError.StatusCode,
+		Body:       string(jsonResponseBody),
+	}
+	return response, nil
+}
@@ -0,0 +1,81 @@
+package automation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+)
+
+// GenerateTags returns a tag for each payload field but the account ID, keyed by field name.
+func GenerateTags(payload AccountPayload) []*organizations.Tag {
+	var tags []*organizations.Tag
+	val := reflect.ValueOf(payload)
+	typeOfS := val.Type()
+
+	// Iterate over fields of struct
+	for i := 0; i < val.NumField(); i++ {
+		key, value := typeOfS.Field(i).Name, fmt.Sprintf("%v", val.Field(i).Interface())
+		tag := &organizations.Tag{
+			Key:   &key,
+			Value: &value,
+		}
+
+		if !strings.EqualFold(key, "AccountID") {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+// GenerateKeysAndTags returns the tags GenerateTags would write along with their keys, so they can be untagged first.
+func GenerateKeysAndTags(payload AccountPayload) ([]*string, []*organizations.Tag) {
+	var keys []*string
+	tags := GenerateTags(payload)
+	for _, tag := range tags {
+		keys = append(keys, tag.Key)
+	}
+	return keys, tags
+}
+
+func TagAccount(svc organizationsiface.OrganizationsAPI, tags []*organizations.Tag, accountID string) error {
+	tagInput := &organizations.TagResourceInput{
+		ResourceId: &accountID,
+		Tags:       tags,
+	}
+	_, error := svc.TagResource(tagInput)
+	return error
+}
+
+func UntagAccount(svc organizationsiface.OrganizationsAPI, keys []*string, accountID string) error {
+	input := &organizations.UntagResourceInput{
+		ResourceId: &accountID,
+		TagKeys:    keys,
+	}
+
+	_, error := svc.UntagResource(input)
+	return error
+}
+
+func ListAccountTags(svc organizationsiface.OrganizationsAPI, accountID string) (map[string]string, error) {
+	tags := map[string]string{}
+	input := &organizations.ListTagsForResourceInput{ResourceId: &accountID}
+	for {
+		output, error := svc.ListTagsForResource(input)
+		if error != nil {
+			return nil, error
+		}
+		for _, tag := range output.Tags {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+
+		if output.NextToken == nil {
+			return tags, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
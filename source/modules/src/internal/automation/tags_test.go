@@ -0,0 +1,103 @@
+package automation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGenerateTags(t *testing.T) {
+	payload := AccountPayload{
+		Name:          "AWS_SEC_test_Dev",
+		CostCenter:    "01234",
+		AccountPOC:    "john.doe@example.com",
+		ApplicationID: "00000000-0000-0000-0000-000000000000",
+		Env:           "DEV",
+		Lob:           "SEC",
+	}
+	name, costCenter, accountPOC, applicationID, env, lob := "Name", "CostCenter", "AccountPOC", "ApplicationID", "Env", "Lob"
+	expectedTags := []*organizations.Tag{
+		{
+			Key:   &name,
+			Value: &payload.Name,
+		},
+		{
+			Key:   &costCenter,
+			Value: &payload.CostCenter,
+		},
+		{
+			Key:   &accountPOC,
+			Value: &payload.AccountPOC,
+		},
+		{
+			Key:   &applicationID,
+			Value: &payload.ApplicationID,
+		},
+		{
+			Key:   &env,
+			Value: &payload.Env,
+		},
+		{
+			Key:   &lob,
+			Value: &payload.Lob,
+		},
+	}
+	tags := GenerateTags(payload)
+
+	if !cmp.Equal(tags, expectedTags) {
+		t.Fatal("Account tagging returned unexpected output")
+	}
+}
+
+func TestGenerateKeysAndTags(t *testing.T) {
+	payload := AccountPayload{
+		Name:          "AWS_SEC_test_Dev",
+		CostCenter:    "01234",
+		AccountPOC:    "john.doe@example.com",
+		ApplicationID: "00000000-0000-0000-0000-000000000000",
+		Env:           "DEV",
+		Lob:           "SEC",
+	}
+	name, costCenter, accountPOC, applicationID, env, lob := "Name", "CostCenter", "AccountPOC", "ApplicationID", "Env", "Lob"
+	expectedTags := []*organizations.Tag{
+		{
+			Key:   &name,
+			Value: &payload.Name,
+		},
+		{
+			Key:   &costCenter,
+			Value: &payload.CostCenter,
+		},
+		{
+			Key:   &accountPOC,
+			Value: &payload.AccountPOC,
+		},
+		{
+			Key:   &applicationID,
+			Value: &payload.ApplicationID,
+		},
+		{
+			Key:   &env,
+			Value: &payload.Env,
+		},
+		{
+			Key:   &lob,
+			Value: &payload.Lob,
+		},
+	}
+
+	expectedKeys := []*string{&name, &costCenter, &accountPOC, &applicationID, &env, &lob}
+	keys, tags := GenerateKeysAndTags(payload)
+	for i := range keys {
+		if !strings.EqualFold(*keys[i], *expectedKeys[i]) {
+			t.Fatal("Account tagging returned unexpected output for keys")
+
+		}
+	}
+
+	if !cmp.Equal(tags, expectedTags) {
+		t.Fatal("Account tagging returned unexpected output tags")
+	}
+}
@@ -0,0 +1,144 @@
+package automation
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+// The patterns the payload fields must match, kept the same as the patterns in swagger.json so a request API GW
+// lets through is judged the same way here.
+var (
+	costCenterPattern    = regexp.MustCompile(`^\d{5}$`)
+	applicationIDPattern = regexp.MustCompile(`^[A-Fa-f0-9]{8}-[A-Fa-f0-9]{4}-[A-Fa-f0-9]{4}-[A-Fa-f0-9]{4}-[A-Fa-f0-9]{12}$`)
+	lobPattern           = regexp.MustCompile(`^[A-Z]+$`)
+)
+
+// DefaultEmailDomain is the domain an accountPOC must be in when EMAIL_DOMAIN is not set.
+const DefaultEmailDomain = "example.com"
+
+// EmailDomain returns the domain, without its @, of the EMAIL_DOMAIN environment variable, which Terraform also
+// deploys swagger.json's accountPOC pattern with.
+func EmailDomain() string {
+	domain := strings.TrimPrefix(os.Getenv("EMAIL_DOMAIN"), "@")
+	if domain == "" {
+		return DefaultEmailDomain
+	}
+	return domain
+}
+
+// Envs are the envs an account can be created in. Case is ignored when they are compared with the payload.
+var Envs = []string{"Lab", "Dev", "Test", "Prod"}
+
+// CanonicalEnv returns env as it is written in Envs, or env itself when it is not one of them.
+func CanonicalEnv(env string) string {
+	for _, known := range Envs {
+		if strings.EqualFold(env, known) {
+			return known
+		}
+	}
+	return env
+}
+
+// FieldError is one field of a request that is not valid.
+type FieldError struct {
+	Field string `json:"field"`
+	// Code is one of required, invalid_format, invalid_value and mismatch, or for the name one of the
+	// NamingError codes.
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Segment is the naming convention segment a name breaks, see NamingError.
+	Segment string `json:"segment,omitempty"`
+}
+
+// ValidationError reports every field of a request that is not valid as the JSON body of a single 400, in the
+// APIError envelope with the fields under problems.
+type ValidationError struct {
+	APIError
+	Problems []FieldError `json:"problems"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := []string{}
+	for _, problem := range e.Problems {
+		messages = append(messages, problem.Message)
+	}
+	return e.Message + ": " + strings.Join(messages, "; ")
+}
+
+// NewValidationError returns a *ValidationError for problems, or nil when there are none.
+func NewValidationError(problems []FieldError) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ValidationError{APIError: APIError{StatusCode: 400, Code: "invalid_request", Message: "error: the request is not valid"}, Problems: problems}
+}
+
+// ValidateEnv returns the problem with env, or nil when it is one of Envs.
+func ValidateEnv(env string) *FieldError {
+	if env == "" {
+		return &FieldError{Field: "env", Code: "required", Message: "error: env is required"}
+	}
+	for _, known := range Envs {
+		if strings.EqualFold(env, known) {
+			return nil
+		}
+	}
+	return &FieldError{Field: "env", Code: "invalid_value", Message: "error: env " + env + " is not one of " + strings.Join(Envs, ", ")}
+}
+
+// ValidateLob returns the problem with lob, or nil when it is made of upper case letters.
+func ValidateLob(lob string) *FieldError {
+	if lob == "" {
+		return &FieldError{Field: "lob", Code: "required", Message: "error: lob is required"}
+	}
+	if !lobPattern.MatchString(lob) {
+		return &FieldError{Field: "lob", Code: "invalid_format", Message: "error: lob " + lob + " must be made of upper case letters"}
+	}
+	return nil
+}
+
+// ValidateFields checks every field of payload on its own, against swagger.json's patterns and the naming convention,
+// and returns every problem found. Only an error loading the naming convention, which is not the request's fault,
+// is returned as an error.
+func ValidateFields(payload AccountPayload) ([]FieldError, error) {
+	var problems []FieldError
+	problem := func(field string, code string, message string) {
+		problems = append(problems, FieldError{Field: field, Code: code, Message: message})
+	}
+
+	if payload.Name == "" {
+		problem("name", "required", "error: name is required")
+	} else {
+		_, _, error := ParseAccountName(payload.Name)
+		if namingError, ok := error.(*NamingError); ok {
+			problems = append(problems, FieldError{Field: "name", Code: namingError.Code, Message: namingError.Message, Segment: namingError.Segment})
+		} else if error != nil {
+			return nil, error
+		}
+	}
+
+	if payload.CostCenter == "" {
+		problem("costCenter", "required", "error: costCenter is required")
+	} else if !costCenterPattern.MatchString(payload.CostCenter) {
+		problem("costCenter", "invalid_format", "error: costCenter "+payload.CostCenter+" must be 5 digits")
+	}
+	if payload.AccountPOC == "" {
+		problem("accountPOC", "required", "error: accountPOC is required")
+	} else if domain := EmailDomain(); !regexp.MustCompile(`^[A-Za-z0-9._%+-]+@` + regexp.QuoteMeta(domain) + `$`).MatchString(payload.AccountPOC) {
+		problem("accountPOC", "invalid_format", "error: accountPOC "+payload.AccountPOC+" must be an email address in the "+domain+" domain")
+	}
+	if payload.ApplicationID == "" {
+		problem("applicationId", "required", "error: applicationId is required")
+	} else if !applicationIDPattern.MatchString(payload.ApplicationID) {
+		problem("applicationId", "invalid_format", "error: applicationId "+payload.ApplicationID+" must be a UUID")
+	}
+
+	if fieldError := ValidateEnv(payload.Env); fieldError != nil {
+		problems = append(problems, *fieldError)
+	}
+	if fieldError := ValidateLob(payload.Lob); fieldError != nil {
+		problems = append(problems, *fieldError)
+	}
+	return problems, nil
+}
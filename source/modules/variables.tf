@@ -33,12 +33,62 @@ variable "runtime_env" {
   description = "LAB|DEV|TEST|PROD environment this is being deployed to. When elevating to PROD, ensure PROD is passed."
 }
 
+variable "execution_mode" {
+  type        = string
+  description = "live|dry-run|simulated mode the Lambdas run in. When empty, PROD runs live and every other runtime_env is simulated."
+  default     = ""
+}
+
 variable "infosec_ous" {
   type        = map(string)
-  description = "Map of security related OU IDs."
+  description = "Map of security related OU IDs. Overrides the path given for the same LOB in infosec_ou_paths."
+  default     = {}
+}
+
+variable "infosec_ou_paths" {
+  type        = map(string)
+  description = "Map of security related OU paths from the org root, such as /Security."
+  default     = {}
 }
 
 variable "workload_ou" {
   type        = string
-  description = "Workload (or Application OU) that requesters of new accounts will be having their accounts deployed in."
+  description = "Workload (or Application OU) that requesters of new accounts will be having their accounts deployed in. Overrides workload_ou_path."
+  default     = ""
+}
+
+variable "workload_ou_path" {
+  type        = string
+  description = "Path of the Workload OU from the org root, such as /Workloads. Used when workload_ou is empty."
+  default     = ""
+}
+
+variable "suspended_ou" {
+  type        = string
+  description = "OU that accounts are moved to before they are closed. Leave empty to close accounts in place."
+  default     = ""
+}
+
+variable "placement_rules" {
+  type        = string
+  description = "JSON or YAML document of placement rules mapping account attributes to an OU path. Leave empty to place accounts with infosec_ous and workload_ou."
+  default     = ""
+}
+
+variable "account_naming_convention" {
+  type        = string
+  description = "JSON or YAML document of the account naming convention, a pattern such as {prefix}_{lob}_{app}_{env} and the regex of each segment. Leave empty for aws_LOB_name_ENV."
+  default     = ""
+}
+
+variable "org_tree_ttl" {
+  type        = string
+  description = "How long the create Lambda caches the organization's OUs between lookups, as a duration such as 5m. 0 turns the cache off."
+  default     = "5m"
+}
+
+variable "auto_create_ou_parents" {
+  type        = list(string)
+  description = "OU IDs or paths from the org root under which the create Lambda may create missing env and lob OUs. Leave empty to never create OUs."
+  default     = []
 }